@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// APIKey represents a key allowed to call the API, only the hash of its secret is stored
+type APIKey struct {
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	Hash      string    `db:"key_hash"`
+	Role      Role      `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// CanManage informs if the API key can modify the URL mapping, only its owner or a role allowed to override links can
+func (k APIKey) CanManage(m URLMapping) bool {
+	if k.Role.Can(PermissionOverrideLinks) {
+		return true
+	}
+	return k.Role.Can(PermissionWriteLinks) && m.Owner != "" && m.Owner == k.ID
+}
+
+// apiKeyContextKey is the key of the authenticated API key within a context
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey returns a copy of the context holding the authenticated API key
+func ContextWithAPIKey(ctx context.Context, apiKey APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+}
+
+// APIKeyFromContext retrieves the authenticated API key of the context, if any
+func APIKeyFromContext(ctx context.Context) (APIKey, bool) {
+	apiKey, ok := ctx.Value(apiKeyContextKey{}).(APIKey)
+	return apiKey, ok
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyCanManage(t *testing.T) {
+	scenarios := []struct {
+		Name     string
+		APIKey   APIKey
+		Owner    string
+		Expected bool
+	}{
+		{Name: "owner", APIKey: APIKey{ID: "key-1", Role: RoleEditor}, Owner: "key-1", Expected: true},
+		{Name: "not owner", APIKey: APIKey{ID: "key-2", Role: RoleEditor}, Owner: "key-1", Expected: false},
+		{Name: "no owner", APIKey: APIKey{ID: "key-1", Role: RoleEditor}, Owner: "", Expected: false},
+		{Name: "viewer owner", APIKey: APIKey{ID: "key-1", Role: RoleViewer}, Owner: "key-1", Expected: false},
+		{Name: "admin", APIKey: APIKey{ID: "admin", Role: RoleAdmin}, Owner: "key-1", Expected: true},
+		{Name: "admin on no owner", APIKey: APIKey{ID: "admin", Role: RoleAdmin}, Owner: "", Expected: true},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			urlMapping := URLMapping{Slug: "example", OriginalURL: "https://example.com", Owner: scenario.Owner}
+
+			// When
+			canManage := scenario.APIKey.CanManage(urlMapping)
+
+			// Then
+			assert.Equal(t, scenario.Expected, canManage)
+		})
+	}
+}
+
+func TestAPIKeyContext(t *testing.T) {
+	t.Run("with API key", func(t *testing.T) {
+		// Given
+		apiKey := APIKey{ID: "key-1", Name: "marketing"}
+		ctx := ContextWithAPIKey(context.Background(), apiKey)
+
+		// When
+		retrievedAPIKey, ok := APIKeyFromContext(ctx)
+
+		// Then
+		assert.True(t, ok)
+		assert.Equal(t, apiKey, retrievedAPIKey)
+	})
+	t.Run("without API key", func(t *testing.T) {
+		// When
+		retrievedAPIKey, ok := APIKeyFromContext(context.Background())
+
+		// Then
+		assert.False(t, ok)
+		assert.Empty(t, retrievedAPIKey)
+	})
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Device is the class of device a link is accessed from
+type Device string
+
+var (
+	DeviceDesktop Device = "desktop"
+	DeviceMobile  Device = "mobile"
+	DeviceTablet  Device = "tablet"
+	DeviceBot     Device = "bot"
+)
+
+// UnknownReferrer is the referrer of the clicks whose Referer header is not an URL with a host
+const UnknownReferrer = "unknown"
+
+// ClickEvent represents the access of a short link by a client
+// The request fields are captured when the link is resolved, the other ones are enriched from them before being recorded
+type ClickEvent struct {
+	Referrer  string // The Referer header, reduced to its host once enriched, empty on a direct access
+	UserAgent string
+	IP        string
+	Visitor   string // The salted hash of the IP and the User-Agent, identifying the unique visitors
+	Browser   string // Empty if unknown
+	OS        string // Empty if unknown
+	Device    Device // Empty if unknown
+	Country   string // The ISO 3166-1 alpha-2 code, empty if unknown
+}
+
+// ClickBreakdown represents the number of clicks of a link sharing a value of a dimension, such as a referrer or a country
+type ClickBreakdown struct {
+	Value   string
+	Counter int
+}
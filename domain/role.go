@@ -0,0 +1,58 @@
+package domain
+
+// Role represents the role of an API key, it grants a set of permissions
+type Role string
+
+const (
+	// RoleAdmin can do everything, including managing API keys, managing links of other API keys, disabling or purging links and reading the metrics
+	RoleAdmin Role = "admin"
+	// RoleEditor can read statistics and links, create links and manage its own links
+	RoleEditor Role = "editor"
+	// RoleViewer can only read statistics
+	RoleViewer Role = "viewer"
+)
+
+// Permission represents an action an API key can be allowed to perform
+type Permission string
+
+const (
+	// PermissionReadStatistics allows to read the statistics of the URLs
+	PermissionReadStatistics Permission = "statistics:read"
+	// PermissionReadLinks allows to retrieve, list, lookup and resolve links
+	PermissionReadLinks Permission = "links:read"
+	// PermissionWriteLinks allows to create links and to retarget or delete the owned ones
+	PermissionWriteLinks Permission = "links:write"
+	// PermissionOverrideLinks allows to retarget or delete any link, whatever its owner
+	PermissionOverrideLinks Permission = "links:override"
+	// PermissionDisableLinks allows to disable any link and to enable it again
+	PermissionDisableLinks Permission = "links:disable"
+	// PermissionPurgeLinks allows to purge any link, along with its tombstone
+	PermissionPurgeLinks Permission = "links:purge"
+	// PermissionManageAPIKeys allows to create API keys
+	PermissionManageAPIKeys Permission = "api-keys:manage"
+	// PermissionReadMetrics allows to read the service metrics, which also expose the command line and the runtime of the process
+	PermissionReadMetrics Permission = "metrics:read"
+)
+
+// rolePermissions holds the policy: the permissions granted to each role
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin:  {PermissionReadStatistics, PermissionReadLinks, PermissionWriteLinks, PermissionOverrideLinks, PermissionDisableLinks, PermissionPurgeLinks, PermissionManageAPIKeys, PermissionReadMetrics},
+	RoleEditor: {PermissionReadStatistics, PermissionReadLinks, PermissionWriteLinks},
+	RoleViewer: {PermissionReadStatistics},
+}
+
+// IsValid informs if the role is a known one
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can informs if the role grants the permission
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
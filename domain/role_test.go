@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleIsValid(t *testing.T) {
+	assert.True(t, RoleAdmin.IsValid())
+	assert.True(t, RoleEditor.IsValid())
+	assert.True(t, RoleViewer.IsValid())
+	assert.False(t, Role("owner").IsValid())
+	assert.False(t, Role("").IsValid())
+}
+
+func TestRoleCan(t *testing.T) {
+	scenarios := []struct {
+		Name       string
+		Role       Role
+		Permission Permission
+		Expected   bool
+	}{
+		{Name: "viewer reads statistics", Role: RoleViewer, Permission: PermissionReadStatistics, Expected: true},
+		{Name: "viewer can't read links", Role: RoleViewer, Permission: PermissionReadLinks, Expected: false},
+		{Name: "viewer can't write links", Role: RoleViewer, Permission: PermissionWriteLinks, Expected: false},
+		{Name: "viewer can't read metrics", Role: RoleViewer, Permission: PermissionReadMetrics, Expected: false},
+		{Name: "editor reads statistics", Role: RoleEditor, Permission: PermissionReadStatistics, Expected: true},
+		{Name: "editor writes links", Role: RoleEditor, Permission: PermissionWriteLinks, Expected: true},
+		{Name: "editor can't override links", Role: RoleEditor, Permission: PermissionOverrideLinks, Expected: false},
+		{Name: "editor can't manage API keys", Role: RoleEditor, Permission: PermissionManageAPIKeys, Expected: false},
+		{Name: "editor can't disable links", Role: RoleEditor, Permission: PermissionDisableLinks, Expected: false},
+		{Name: "editor can't purge links", Role: RoleEditor, Permission: PermissionPurgeLinks, Expected: false},
+		{Name: "editor can't read metrics", Role: RoleEditor, Permission: PermissionReadMetrics, Expected: false},
+		{Name: "admin overrides links", Role: RoleAdmin, Permission: PermissionOverrideLinks, Expected: true},
+		{Name: "admin disables links", Role: RoleAdmin, Permission: PermissionDisableLinks, Expected: true},
+		{Name: "admin purges links", Role: RoleAdmin, Permission: PermissionPurgeLinks, Expected: true},
+		{Name: "admin manages API keys", Role: RoleAdmin, Permission: PermissionManageAPIKeys, Expected: true},
+		{Name: "admin reads metrics", Role: RoleAdmin, Permission: PermissionReadMetrics, Expected: true},
+		{Name: "unknown role", Role: Role("owner"), Permission: PermissionReadStatistics, Expected: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// When
+			can := scenario.Role.Can(scenario.Permission)
+
+			// Then
+			assert.Equal(t, scenario.Expected, can)
+		})
+	}
+}
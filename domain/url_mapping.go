@@ -2,9 +2,40 @@ package domain
 
 import "time"
 
+// URLMappingStatus is the type of the status of an URL mapping
+type URLMappingStatus string
+
+var (
+	// URLMappingStatusActive is the status of an URL mapping that can be retrieved
+	URLMappingStatusActive URLMappingStatus = "active"
+	// URLMappingStatusExpired is the status of an URL mapping that has expired
+	URLMappingStatusExpired URLMappingStatus = "expired"
+	// URLMappingStatusDisabled is the status of an URL mapping that has been disabled by an admin
+	URLMappingStatusDisabled URLMappingStatus = "disabled"
+)
+
 // URLMapping represents an URL mapping data between a short URL and its original form
 type URLMapping struct {
-	Slug        string    `db:"slug"`
-	OriginalURL string    `db:"original_url"`
-	InsertedAt  time.Time `db:"inserted_at"`
+	Slug        string     `db:"slug"`
+	OriginalURL string     `db:"original_url"`
+	InsertedAt  time.Time  `db:"inserted_at"`
+	ExpiresAt   *time.Time `db:"expires_at"` // nil when the URL mapping never expires
+	Owner       string     `db:"owner"`      // ID of the API key that created the URL mapping, empty if created without authentication
+	Disabled    bool       `db:"disabled"`   // A disabled URL mapping is kept but can't be retrieved
+}
+
+// IsExpired informs if the URL mapping is expired at the given time
+func (m URLMapping) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
+// Status returns the status of the URL mapping at the given time
+func (m URLMapping) Status(now time.Time) URLMappingStatus {
+	if m.IsExpired(now) {
+		return URLMappingStatusExpired
+	}
+	if m.Disabled {
+		return URLMappingStatusDisabled
+	}
+	return URLMappingStatusActive
 }
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLMappingIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	scenarios := []struct {
+		Name      string
+		ExpiresAt *time.Time
+		Expected  bool
+	}{
+		{Name: "never expires", ExpiresAt: nil, Expected: false},
+		{Name: "expired", ExpiresAt: &past, Expected: true},
+		{Name: "expires right now", ExpiresAt: &now, Expected: true},
+		{Name: "not expired yet", ExpiresAt: &future, Expected: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			urlMapping := URLMapping{Slug: "example", OriginalURL: "https://example.com", ExpiresAt: scenario.ExpiresAt}
+
+			// When
+			isExpired := urlMapping.IsExpired(now)
+
+			// Then
+			assert.Equal(t, scenario.Expected, isExpired)
+		})
+	}
+}
+
+func TestURLMappingStatus(t *testing.T) {
+	t.Run("active", func(t *testing.T) {
+		// Given
+		urlMapping := URLMapping{Slug: "example", OriginalURL: "https://example.com"}
+
+		// When
+		status := urlMapping.Status(time.Now())
+
+		// Then
+		assert.Equal(t, URLMappingStatusActive, status)
+	})
+	t.Run("expired", func(t *testing.T) {
+		// Given
+		expiredAt := time.Now().Add(-time.Minute)
+		urlMapping := URLMapping{Slug: "example", OriginalURL: "https://example.com", ExpiresAt: &expiredAt}
+
+		// When
+		status := urlMapping.Status(time.Now())
+
+		// Then
+		assert.Equal(t, URLMappingStatusExpired, status)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		// Given
+		urlMapping := URLMapping{Slug: "example", OriginalURL: "https://example.com", Disabled: true}
+
+		// When
+		status := urlMapping.Status(time.Now())
+
+		// Then
+		assert.Equal(t, URLMappingStatusDisabled, status)
+	})
+}
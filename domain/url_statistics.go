@@ -1,8 +1,21 @@
 package domain
 
+import "time"
+
 // URLStatistic represents an URL statistic with a shortened counter and an accessed counter
+// It holds the counters of either all the slugs of the URL, or of a single slug when Slug is set
 type URLStatistic struct {
 	URL              string
+	Slug             string
+	ShortenedCounter int
+	AccessedCounter  int
+	UniqueVisitors   int // Approximate
+}
+
+// URLStatisticPoint represents the counters of an URL within a bucket of time
+type URLStatisticPoint struct {
+	Time             time.Time // The start of the bucket
 	ShortenedCounter int
 	AccessedCounter  int
+	UniqueVisitors   int // Approximate
 }
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jxskiss/base62"
+)
+
+// apiKeySecretPrefix prefixes the API key secrets to ease their identification (e.g. by secret scanners)
+const apiKeySecretPrefix string = "usk_"
+
+// APIKeyGeneratorCmd represents an API key generator function signature
+// It returns a public identifier and a secret, the secret should only be given once to the API key holder
+type APIKeyGeneratorCmd func() (id string, secret string, err error)
+
+// generateAPIKey generates a random API key
+func generateAPIKey() APIKeyGeneratorCmd {
+	return func() (string, string, error) {
+		idBytes := make([]byte, 8)
+		_, err := rand.Read(idBytes)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate API key id: %w", err)
+		}
+
+		secretBytes := make([]byte, 32)
+		_, err = rand.Read(secretBytes)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate API key secret: %w", err)
+		}
+
+		return hex.EncodeToString(idBytes), apiKeySecretPrefix + base62.EncodeToString(secretBytes), nil
+	}
+}
+
+// APIKeyGeneratorCmdBuilder builds an API key generator command
+func APIKeyGeneratorCmdBuilder() APIKeyGeneratorCmd {
+	return generateAPIKey()
+}
@@ -0,0 +1,39 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		cmd := APIKeyGeneratorCmdBuilder()
+
+		// When
+		id, secret, err := cmd()
+		require.NoError(t, err)
+
+		// Then
+		assert.Len(t, id, 16)
+		assert.True(t, strings.HasPrefix(secret, apiKeySecretPrefix))
+		assert.Greater(t, len(secret), 40)
+	})
+	t.Run("API keys are unique", func(t *testing.T) {
+		// Given
+		cmd := APIKeyGeneratorCmdBuilder()
+
+		// When
+		id1, secret1, err := cmd()
+		require.NoError(t, err)
+		id2, secret2, err := cmd()
+		require.NoError(t, err)
+
+		// Then
+		assert.NotEqual(t, id1, id2)
+		assert.NotEqual(t, secret1, secret2)
+	})
+}
@@ -0,0 +1,23 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// APIKeyHasherCmd represents an API key hasher function signature
+type APIKeyHasherCmd func(secret string) string
+
+// hashAPIKey hashes an API key secret so that it is never stored in clear
+// The secrets are random enough for a fast hash to be safe, and a fast hash allows to look them up
+func hashAPIKey() APIKeyHasherCmd {
+	return func(secret string) string {
+		hash := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(hash[:])
+	}
+}
+
+// APIKeyHasherCmdBuilder builds an API key hasher command
+func APIKeyHasherCmdBuilder() APIKeyHasherCmd {
+	return hashAPIKey()
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAPIKey(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		cmd := APIKeyHasherCmdBuilder()
+
+		// When
+		hash := cmd("usk_secret")
+
+		// Then
+		assert.Len(t, hash, 64)
+		assert.NotContains(t, hash, "usk_secret")
+	})
+	t.Run("hash is consistent", func(t *testing.T) {
+		// Given
+		cmd := APIKeyHasherCmdBuilder()
+
+		// When
+		hash1 := cmd("usk_secret")
+		hash2 := cmd("usk_secret")
+		hash3 := cmd("usk_other-secret")
+
+		// Then
+		assert.Equal(t, hash1, hash2)
+		assert.NotEqual(t, hash1, hash3)
+	})
+}
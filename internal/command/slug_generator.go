@@ -7,11 +7,12 @@ import (
 )
 
 // SlugGeneratorCmd represents a slug generator function signature
-type SlugGeneratorCmd func(url string) string
+// attempt is the number of collisions already encountered for the URL, each one extends the slug by one character
+type SlugGeneratorCmd func(url string, attempt int) string
 
 // generateSlug generates a consistent slug for a given URL
 func generateSlug(slugLenght int) SlugGeneratorCmd {
-	return func(url string) string {
+	return func(url string, attempt int) string {
 		// Generate SHA-1 hash of the URL
 		hasher := sha1.New()
 		hasher.Write([]byte(url))
@@ -20,10 +21,10 @@ func generateSlug(slugLenght int) SlugGeneratorCmd {
 		// Encode the hash bytes using Base62
 		base62Hash := base62.EncodeToString(hashBytes)
 
-		// Shorten the slug to only 8 characters
+		// Shorten the slug to only 8 characters (plus one per collision attempt)
 		slug := base62Hash
-		if len(slug) > slugLenght {
-			slug = slug[:slugLenght]
+		if len(slug) > slugLenght+attempt {
+			slug = slug[:slugLenght+attempt]
 		}
 
 		return slug
@@ -13,7 +13,7 @@ func TestGenerateSlug(t *testing.T) {
 		cmd := SlugGeneratorCmdBuilder(8)
 
 		// When
-		slug := cmd(url)
+		slug := cmd(url, 0)
 
 		// Then
 		assert.Len(t, slug, 8)
@@ -26,7 +26,7 @@ func TestGenerateSlug(t *testing.T) {
 
 		// When
 		for i := 0; i < 5; i++ {
-			slug := cmd(url)
+			slug := cmd(url, 0)
 			slugs[slug] = true
 		}
 
@@ -34,4 +34,17 @@ func TestGenerateSlug(t *testing.T) {
 		assert.Len(t, slugs, 1)
 
 	})
+	t.Run("slug is extended on collision attempt", func(t *testing.T) {
+		// Given
+		url := "https://example.com"
+		cmd := SlugGeneratorCmdBuilder(8)
+
+		// When
+		slug := cmd(url, 0)
+		extendedSlug := cmd(url, 2)
+
+		// Then
+		assert.Len(t, extendedSlug, 10)
+		assert.Equal(t, slug, extendedSlug[:8])
+	})
 }
@@ -2,13 +2,14 @@ package command
 
 import (
 	"errors"
+	"strings"
 	"unicode"
 )
 
 var (
 	// ErrInvalidSlugLenght is the error when a slug lenght is invalid
 	ErrInvalidSlugLenght error = errors.New("slug lenght is invalid")
-	// ErrInvalidSlugNonAlphanumeric is the error when a slug is invalid because it has non alphanumeric character
+	// ErrInvalidSlugNonAlphanumeric is the error when a slug is invalid because it has non alphanumeric character that is not allowed
 	ErrInvalidSlugNonAlphanumeric error = errors.New("slug is invalid because of non alphanumeric character")
 )
 
@@ -16,13 +17,13 @@ var (
 type SlugValidatorCmd func(slug string) error
 
 // validateSlug ensures that a slug is valid
-func validateSlug(slugLenght int) SlugValidatorCmd {
+func validateSlug(slugLenght int, allowedCharacters string) SlugValidatorCmd {
 	return func(slug string) error {
-		if len(slug) > slugLenght {
+		if len(slug) == 0 || len(slug) > slugLenght {
 			return ErrInvalidSlugLenght
 		}
 		for _, char := range slug {
-			if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			if !unicode.IsLetter(char) && !unicode.IsDigit(char) && !strings.ContainsRune(allowedCharacters, char) {
 				return ErrInvalidSlugNonAlphanumeric
 			}
 		}
@@ -31,6 +32,7 @@ func validateSlug(slugLenght int) SlugValidatorCmd {
 }
 
 // SlugValidatorCmdBuilder builds a slug validator command
-func SlugValidatorCmdBuilder(slugLenght int) SlugValidatorCmd {
-	return validateSlug(slugLenght)
+// allowedCharacters holds the non alphanumeric characters accepted within a slug (e.g. "-")
+func SlugValidatorCmdBuilder(slugLenght int, allowedCharacters string) SlugValidatorCmd {
+	return validateSlug(slugLenght, allowedCharacters)
 }
@@ -10,7 +10,7 @@ func TestValidatorSlug(t *testing.T) {
 	t.Run("nominal", func(t *testing.T) {
 		// Given
 		slug := "zTw34enA"
-		cmd := SlugValidatorCmdBuilder(8)
+		cmd := SlugValidatorCmdBuilder(8, "")
 
 		// When
 		err := cmd(slug)
@@ -21,7 +21,18 @@ func TestValidatorSlug(t *testing.T) {
 	t.Run("with a short slug", func(t *testing.T) {
 		// Given
 		slug := "z"
-		cmd := SlugValidatorCmdBuilder(8)
+		cmd := SlugValidatorCmdBuilder(8, "")
+
+		// When
+		err := cmd(slug)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("with an allowed character", func(t *testing.T) {
+		// Given
+		slug := "spr-sale"
+		cmd := SlugValidatorCmdBuilder(8, "-")
 
 		// When
 		err := cmd(slug)
@@ -33,7 +44,18 @@ func TestValidatorSlug(t *testing.T) {
 		t.Run("because of lenght", func(t *testing.T) {
 			// Given
 			slug := "zTw34enAh"
-			cmd := SlugValidatorCmdBuilder(8)
+			cmd := SlugValidatorCmdBuilder(8, "")
+
+			// When
+			err := cmd(slug)
+
+			// Then
+			assert.ErrorIs(t, err, ErrInvalidSlugLenght)
+		})
+		t.Run("because it is empty", func(t *testing.T) {
+			// Given
+			slug := ""
+			cmd := SlugValidatorCmdBuilder(8, "")
 
 			// When
 			err := cmd(slug)
@@ -44,7 +66,18 @@ func TestValidatorSlug(t *testing.T) {
 		t.Run("because of non alphanumeric character", func(t *testing.T) {
 			// Given
 			slug := "zTw+4enA"
-			cmd := SlugValidatorCmdBuilder(8)
+			cmd := SlugValidatorCmdBuilder(8, "")
+
+			// When
+			err := cmd(slug)
+
+			// Then
+			assert.ErrorIs(t, err, ErrInvalidSlugNonAlphanumeric)
+		})
+		t.Run("because of non allowed character", func(t *testing.T) {
+			// Given
+			slug := "spr_sale"
+			cmd := SlugValidatorCmdBuilder(8, "-")
 
 			// When
 			err := cmd(slug)
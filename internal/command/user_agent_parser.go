@@ -0,0 +1,53 @@
+package command
+
+import (
+	"strings"
+	"urlShortenerService/domain"
+
+	"github.com/mssola/useragent"
+)
+
+// UserAgentParserCmd represents an user agent parser function signature, returning the browser, the operating system and the class of device, each empty if unknown
+type UserAgentParserCmd func(userAgent string) (browser string, os string, device domain.Device)
+
+// parseUserAgent parses the given User-Agent header
+func parseUserAgent() UserAgentParserCmd {
+	return func(userAgent string) (string, string, domain.Device) {
+		ua := useragent.New(strings.TrimSpace(userAgent))
+		browser, _ := ua.Browser()
+
+		// The apple mobile systems are reported as the platform they run on
+		os := ua.OSInfo().Name
+		switch ua.Platform() {
+		case "iPhone":
+			os = "iOS"
+		case "iPad":
+			os = "iPadOS"
+		}
+
+		return browser, os, deviceOf(ua, os)
+	}
+}
+
+// deviceOf returns the class of device of the parsed user agent, the android tablets being the android devices not reported as mobile
+func deviceOf(ua *useragent.UserAgent, os string) domain.Device {
+	switch {
+	case ua.Bot():
+		return domain.DeviceBot
+	case ua.Platform() == "iPad":
+		return domain.DeviceTablet
+	case os == "Android" && !strings.Contains(ua.UA(), "Mobile"):
+		return domain.DeviceTablet
+	case ua.Mobile():
+		return domain.DeviceMobile
+	case os != "":
+		return domain.DeviceDesktop
+	default:
+		return ""
+	}
+}
+
+// UserAgentParserCmdBuilder builds an user agent parser command
+func UserAgentParserCmdBuilder() UserAgentParserCmd {
+	return parseUserAgent()
+}
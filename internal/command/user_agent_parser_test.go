@@ -0,0 +1,73 @@
+package command
+
+import (
+	"testing"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	scenarios := []struct {
+		Name            string
+		UserAgent       string
+		ExpectedBrowser string
+		ExpectedOS      string
+		ExpectedDevice  domain.Device
+	}{
+		{
+			Name:            "desktop",
+			UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			ExpectedBrowser: "Chrome", ExpectedOS: "Windows", ExpectedDevice: domain.DeviceDesktop,
+		},
+		{
+			Name:            "iphone",
+			UserAgent:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			ExpectedBrowser: "Safari", ExpectedOS: "iOS", ExpectedDevice: domain.DeviceMobile,
+		},
+		{
+			Name:            "ipad",
+			UserAgent:       "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			ExpectedBrowser: "Safari", ExpectedOS: "iPadOS", ExpectedDevice: domain.DeviceTablet,
+		},
+		{
+			Name:            "android phone",
+			UserAgent:       "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			ExpectedBrowser: "Chrome", ExpectedOS: "Android", ExpectedDevice: domain.DeviceMobile,
+		},
+		{
+			Name:            "android tablet",
+			UserAgent:       "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			ExpectedBrowser: "Chrome", ExpectedOS: "Android", ExpectedDevice: domain.DeviceTablet,
+		},
+		{
+			Name:            "bot",
+			UserAgent:       "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			ExpectedBrowser: "Googlebot", ExpectedOS: "", ExpectedDevice: domain.DeviceBot,
+		},
+		{
+			Name:            "command line client",
+			UserAgent:       "curl/8.4.0",
+			ExpectedBrowser: "curl", ExpectedOS: "", ExpectedDevice: "",
+		},
+		{
+			Name:            "empty",
+			UserAgent:       "",
+			ExpectedBrowser: "", ExpectedOS: "", ExpectedDevice: "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			cmd := UserAgentParserCmdBuilder()
+
+			// When
+			browser, os, device := cmd(scenario.UserAgent)
+
+			// Then
+			assert.Equal(t, scenario.ExpectedBrowser, browser)
+			assert.Equal(t, scenario.ExpectedOS, os)
+			assert.Equal(t, scenario.ExpectedDevice, device)
+		})
+	}
+}
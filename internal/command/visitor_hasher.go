@@ -0,0 +1,27 @@
+package command
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// VisitorHasherCmd represents a visitor hasher function signature
+// It returns an identifier of the visitor to count the unique visitors of the links without storing their IP
+type VisitorHasherCmd func(ip string, userAgent string) string
+
+// hashVisitor hashes the IP and the User-Agent of a visitor with a secret salt, so that the identifier can't be reversed by hashing every IP
+func hashVisitor(salt string) VisitorHasherCmd {
+	return func(ip string, userAgent string) string {
+		mac := hmac.New(sha256.New, []byte(salt))
+		mac.Write([]byte(ip))
+		mac.Write([]byte{0}) // Separates the IP from the User-Agent
+		mac.Write([]byte(userAgent))
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	}
+}
+
+// VisitorHasherCmdBuilder builds a visitor hasher command, the salt being shared by all the replicas for a visitor to be counted once
+func VisitorHasherCmdBuilder(salt string) VisitorHasherCmd {
+	return hashVisitor(salt)
+}
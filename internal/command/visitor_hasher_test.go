@@ -0,0 +1,26 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashVisitor(t *testing.T) {
+	// Given
+	cmd := VisitorHasherCmdBuilder("salt")
+	ip := "82.64.1.1"
+	userAgent := "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0"
+
+	// When
+	visitor := cmd(ip, userAgent)
+
+	// Then
+	assert.Len(t, visitor, 32)
+	assert.NotContains(t, visitor, ip)
+	assert.Equal(t, visitor, cmd(ip, userAgent))
+	assert.NotEqual(t, visitor, cmd("82.64.1.2", userAgent))
+	assert.NotEqual(t, visitor, cmd(ip, "curl/8.4.0"))
+	assert.NotEqual(t, visitor, VisitorHasherCmdBuilder("other salt")(ip, userAgent))
+	assert.NotEqual(t, cmd("1.2.3.4", "5"), cmd("1.2.3.", "45"))
+}
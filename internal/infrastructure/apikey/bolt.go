@@ -0,0 +1,112 @@
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"urlShortenerService/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// apiKeysBucket holds the API keys by ID
+	apiKeysBucket = []byte("api_keys")
+	// hashIndexBucket indexes the IDs of the API keys by hash
+	hashIndexBucket = []byte("api_keys_key_hash_idx")
+)
+
+// boltAPIKey represents an API key as stored within the database
+type boltAPIKey struct {
+	Name      string      `json:"name"`
+	Hash      string      `json:"key_hash"`
+	Role      domain.Role `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// BoltStore represents a store within a bbolt database file, shared with the other bolt stores
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore creates the buckets of the API keys within the database, which is closed by its owner
+func NewBoltStore(db *bolt.DB) (*BoltStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{apiKeysBucket, hashIndexBucket} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// boltGetByID reads an API key given its ID within a transaction
+func boltGetByID(tx *bolt.Tx, id string) (domain.APIKey, error) {
+	value := tx.Bucket(apiKeysBucket).Get([]byte(id))
+	if value == nil {
+		return domain.APIKey{}, ErrNotFound
+	}
+	var stored boltAPIKey
+	err := json.Unmarshal(value, &stored)
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to decode API key [%s]: %w", id, err)
+	}
+	return domain.APIKey{ID: id, Name: stored.Name, Hash: stored.Hash, Role: stored.Role, CreatedAt: stored.CreatedAt}, nil
+}
+
+// Get implements the Store interface
+func (s *BoltStore) Get(ctx context.Context, hash string) (domain.APIKey, error) {
+	var apiKey domain.APIKey
+	err := s.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(hashIndexBucket).Get([]byte(hash))
+		if id == nil {
+			return ErrNotFound
+		}
+		var err error
+		apiKey, err = boltGetByID(tx, string(id))
+		return err
+	})
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// Set implements the Store interface
+func (s *BoltStore) Set(ctx context.Context, apiKey domain.APIKey) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		hashIndex := tx.Bucket(hashIndexBucket)
+		existing, err := boltGetByID(tx, apiKey.ID)
+		switch {
+		case err == nil:
+			// The creation date is kept, as the PSQL store does
+			apiKey.CreatedAt = existing.CreatedAt
+			err = hashIndex.Delete([]byte(existing.Hash))
+			if err != nil {
+				return err
+			}
+		case !errors.Is(err, ErrNotFound):
+			return err
+		case apiKey.CreatedAt.IsZero():
+			apiKey.CreatedAt = time.Now()
+		}
+
+		value, err := json.Marshal(boltAPIKey{Name: apiKey.Name, Hash: apiKey.Hash, Role: apiKey.Role, CreatedAt: apiKey.CreatedAt.UTC()})
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(apiKeysBucket).Put([]byte(apiKey.ID), value)
+		if err != nil {
+			return err
+		}
+		return hashIndex.Put([]byte(apiKey.Hash), []byte(apiKey.ID))
+	})
+}
@@ -0,0 +1,53 @@
+package apikey
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTestBoltDB opens, or creates, the database file
+func openTestBoltDB(t *testing.T, path string) *bolt.DB {
+	db, err := bolt.Open(path, 0o600, nil)
+	require.NoError(t, err)
+	return db
+}
+
+func TestBoltStore(t *testing.T) {
+	db := openTestBoltDB(t, filepath.Join(t.TempDir(), "urls.db"))
+	defer db.Close()
+	store, err := NewBoltStore(db)
+	require.NoError(t, err)
+
+	RunStoreTests(t, store)
+}
+
+func TestBoltStoreReopen(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "urls.db")
+	db := openTestBoltDB(t, path)
+	store, err := NewBoltStore(db)
+	require.NoError(t, err)
+	apiKey := domain.APIKey{ID: "kept-key", Name: "marketing", Hash: "kept-hash", Role: domain.RoleEditor}
+	require.NoError(t, store.Set(ctx, apiKey))
+	require.NoError(t, db.Close())
+
+	// When
+	db = openTestBoltDB(t, path)
+	defer db.Close()
+	store, err = NewBoltStore(db)
+	require.NoError(t, err)
+
+	// Then
+	retrievedAPIKey, err := store.Get(ctx, apiKey.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, apiKey.ID, retrievedAPIKey.ID)
+	assert.Equal(t, apiKey.Role, retrievedAPIKey.Role)
+	assert.False(t, retrievedAPIKey.CreatedAt.IsZero())
+}
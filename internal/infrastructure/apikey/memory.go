@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"context"
+	"sync"
+	"time"
+	"urlShortenerService/domain"
+)
+
+// MemoryStore represents an in memory store, meant for development as nothing is persisted
+type MemoryStore struct {
+	mutex   sync.RWMutex
+	apiKeys map[string]domain.APIKey // By ID
+}
+
+// NewMemoryStore creates an empty in memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{apiKeys: map[string]domain.APIKey{}}
+}
+
+// Get implements the Store interface
+func (s *MemoryStore) Get(ctx context.Context, hash string) (domain.APIKey, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, apiKey := range s.apiKeys {
+		if apiKey.Hash == hash {
+			return apiKey, nil
+		}
+	}
+	return domain.APIKey{}, ErrNotFound
+}
+
+// Set implements the Store interface
+func (s *MemoryStore) Set(ctx context.Context, apiKey domain.APIKey) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if existing, exists := s.apiKeys[apiKey.ID]; exists {
+		// The creation date is kept, as the PSQL store does
+		apiKey.CreatedAt = existing.CreatedAt
+	} else if apiKey.CreatedAt.IsZero() {
+		apiKey.CreatedAt = time.Now()
+	}
+	s.apiKeys[apiKey.ID] = apiKey
+	return nil
+}
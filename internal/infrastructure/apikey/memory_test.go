@@ -0,0 +1,11 @@
+package apikey
+
+import (
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	RunStoreTests(t, store)
+}
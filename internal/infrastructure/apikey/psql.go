@@ -0,0 +1,51 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+	"urlShortenerService/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// getStmt is the prepared statement to retrieve an API key given its hash from the database
+	getStmt string = "SELECT id, name, key_hash, role, created_at FROM api_keys WHERE key_hash=$1;"
+	// setStmt is the prepared statement to insert or replace an API key into the database
+	setStmt string = "INSERT INTO api_keys (id, name, key_hash, role, created_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (id) DO UPDATE SET name = $2, key_hash = $3, role = $4;"
+)
+
+// PSQLStore represents a postgres SQL store
+type PSQLStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPSQLStore creates a PSQLStore on a pool of connections shared with the other PSQL stores, closed by its owner
+// The schema is expected to be up to date, see psql.Migrator
+func NewPSQLStore(pool *pgxpool.Pool) *PSQLStore {
+	return &PSQLStore{pool: pool}
+}
+
+// Get implements the Store interface
+func (s *PSQLStore) Get(ctx context.Context, hash string) (domain.APIKey, error) {
+	var apiKey domain.APIKey
+	err := s.pool.QueryRow(ctx, getStmt, hash).Scan(&apiKey.ID, &apiKey.Name, &apiKey.Hash, &apiKey.Role, &apiKey.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.APIKey{}, ErrNotFound
+		}
+		return domain.APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// Set implements the Store interface
+func (s *PSQLStore) Set(ctx context.Context, apiKey domain.APIKey) error {
+	if apiKey.CreatedAt.IsZero() {
+		apiKey.CreatedAt = time.Now()
+	}
+	_, err := s.pool.Exec(ctx, setStmt, apiKey.ID, apiKey.Name, apiKey.Hash, apiKey.Role, apiKey.CreatedAt.UTC())
+	return err
+}
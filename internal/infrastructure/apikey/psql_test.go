@@ -0,0 +1,26 @@
+package apikey
+
+import (
+	"context"
+	"os"
+	"testing"
+	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/psql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPSQLStore(t *testing.T) {
+	os.Setenv("env", "test")
+	defer os.Unsetenv("env")
+	conf, err := config.Load()
+	require.NoError(t, err)
+	pool, err := psql.NewPool(context.Background(), conf.Database)
+	require.NoError(t, err)
+	defer pool.Close()
+	_, err = psql.MigrateUp(context.Background(), pool, 0)
+	require.NoError(t, err)
+	store := NewPSQLStore(pool)
+
+	RunStoreTests(t, store)
+}
@@ -0,0 +1,21 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"urlShortenerService/domain"
+)
+
+var (
+	// ErrNotFound is the error when an API key is not found within the database
+	ErrNotFound error = errors.New("api key not found")
+)
+
+// Store represents operations on apikey Store
+type Store interface {
+	// Get retrieves the API key given the hash of its secret
+	// It returns ErrNotFound if no API key has this hash
+	Get(ctx context.Context, hash string) (domain.APIKey, error)
+	// Set stores the API key, replacing the one with the same ID if any
+	Set(ctx context.Context, apiKey domain.APIKey) error
+}
@@ -0,0 +1,73 @@
+package apikey
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type StoreTestSuite struct {
+	Store
+}
+
+func RunStoreTests(t *testing.T, store Store) {
+	suite := &StoreTestSuite{Store: store}
+
+	t.Run("TestSet", suite.TestSet)
+	t.Run("TestGet", suite.TestGet)
+}
+
+func (suite *StoreTestSuite) TestSet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	apiKey := domain.APIKey{ID: "set-key", Name: "marketing", Hash: "set-hash-1", Role: domain.RoleEditor}
+	err := suite.Store.Set(ctx, apiKey)
+	require.NoError(t, err)
+
+	// When
+	apiKey.Hash = "set-hash-2"
+	apiKey.Role = domain.RoleAdmin
+	err = suite.Store.Set(ctx, apiKey)
+	require.NoError(t, err)
+
+	// Then
+	retrievedAPIKey, err := suite.Store.Get(ctx, "set-hash-2")
+	require.NoError(t, err)
+	assert.Equal(t, apiKey.ID, retrievedAPIKey.ID)
+	assert.Equal(t, apiKey.Name, retrievedAPIKey.Name)
+	assert.Equal(t, domain.RoleAdmin, retrievedAPIKey.Role)
+	assert.False(t, retrievedAPIKey.CreatedAt.IsZero())
+	_, err = suite.Store.Get(ctx, "set-hash-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func (suite *StoreTestSuite) TestGet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	apiKey := domain.APIKey{ID: "get-key", Name: "analytics", Hash: "get-hash", Role: domain.RoleViewer}
+	err := suite.Store.Set(ctx, apiKey)
+	require.NoError(t, err)
+
+	t.Run("found", func(t *testing.T) {
+		// When
+		retrievedAPIKey, err := suite.Store.Get(ctx, apiKey.Hash)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, apiKey.ID, retrievedAPIKey.ID)
+		assert.Equal(t, apiKey.Name, retrievedAPIKey.Name)
+		assert.Equal(t, apiKey.Hash, retrievedAPIKey.Hash)
+		assert.Equal(t, apiKey.Role, retrievedAPIKey.Role)
+	})
+	t.Run("not found", func(t *testing.T) {
+		// When
+		retrievedAPIKey, err := suite.Store.Get(ctx, "unknown-hash")
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, retrievedAPIKey)
+	})
+}
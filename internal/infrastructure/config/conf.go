@@ -11,16 +11,65 @@ import (
 // Load reads and loads the config inside a structure
 func Load() (*Conf, error) {
 	// Load default
+	viper.SetDefault("storage.backend", StorageBackendPSQL)
+	viper.SetDefault("storage.bolt.path", "data/urls.db")
+	viper.SetDefault("database.max-conns", 10)
+	viper.SetDefault("database.min-conns", 1)
+	viper.SetDefault("database.max-conn-idle-time", 30*time.Minute)
+	viper.SetDefault("database.health-check-period", time.Minute)
+	viper.SetDefault("database.auto-migrate", true)
 	viper.SetDefault("redis.max-results", 100)
+	viper.SetDefault("statistics.hourly-retention", 7*24*time.Hour)  // One week
+	viper.SetDefault("statistics.daily-retention", 365*24*time.Hour) // One year
+	viper.SetDefault("statistics.breakdown-max-values", 1000)
+	viper.SetDefault("statistics.buffer.queue-size", 10000)
+	viper.SetDefault("statistics.buffer.workers", 4)
+	viper.SetDefault("statistics.buffer.flush-size", 500)
+	viper.SetDefault("statistics.buffer.flush-interval", time.Second)
+	viper.SetDefault("cache.enabled", true)
+	viper.SetDefault("cache.capacity", 10000)
+	viper.SetDefault("cache.ttl", time.Minute)
+	viper.SetDefault("cache.negative-ttl", 5*time.Second)
+	viper.SetDefault("cache.bloom-filter.enabled", false)
+	viper.SetDefault("cache.invalidation.enabled", true)
+	viper.SetDefault("cache.shared.enabled", true)
+	viper.SetDefault("cache.shared.ttl", time.Hour)
+	viper.SetDefault("cache.shared.timeout", 100*time.Millisecond)
+	viper.SetDefault("cache.invalidation.channel", "shorturl:invalidations")
+	viper.SetDefault("cache.bloom-filter.expected-slugs", 1_000_000)
+	viper.SetDefault("cache.bloom-filter.false-positive-rate", 0.01)
 	viper.SetDefault("slug.maximal-lenght", 8)
+	viper.SetDefault("slug.custom-maximal-lenght", 32)
+	viper.SetDefault("slug.allowed-characters", "-")
+	viper.SetDefault("slug.max-collision-retries", 3)
 	viper.SetDefault("slug.time-to-expire", 7*24*time.Hour) // One week
+	viper.SetDefault("auth.enabled", true)
+	viper.SetDefault("rate-limit.enabled", true)
+	viper.SetDefault("rate-limit.shorten.requests", 60)
+	viper.SetDefault("rate-limit.shorten.window", time.Minute)
+	viper.SetDefault("rate-limit.shorten-batch.requests", 10)
+	viper.SetDefault("rate-limit.shorten-batch.window", time.Minute)
+	viper.SetDefault("rate-limit.redirect.requests", 600)
+	viper.SetDefault("rate-limit.redirect.window", time.Minute)
+	viper.SetDefault("rate-limit.resolve-batch.requests", 60)
+	viper.SetDefault("rate-limit.resolve-batch.window", time.Minute)
+
+	// Load secrets from env variables
+	err := viper.BindEnv("auth.admin-key", "ADMIN_API_KEY")
+	if err != nil {
+		return &Conf{}, fmt.Errorf("failed to bind env variables: %w", err)
+	}
+	err = viper.BindEnv("statistics.visitor-salt", "VISITOR_SALT")
+	if err != nil {
+		return &Conf{}, fmt.Errorf("failed to bind env variables: %w", err)
+	}
 
 	// Load from config file
 	viper.SetConfigName(os.Getenv("env"))
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("../../../conf/") // Local and test
 	viper.AddConfigPath("./conf/")        // Docker
-	err := viper.ReadInConfig()
+	err = viper.ReadInConfig()
 	if err != nil {
 		return &Conf{}, fmt.Errorf("failed to read config: %w", err)
 	}
@@ -36,19 +85,54 @@ func Load() (*Conf, error) {
 
 // Conf represents the configuration of the application
 type Conf struct {
+	Storage      StorageConfig      `mapstructure:"storage"`
 	Database     PSQLConnConfig     `mapstructure:"database"`
 	Redis        RedisConfig        `mapstructure:"redis"`
+	Cache        CacheConfig        `mapstructure:"cache"`
+	Statistics   StatisticsConfig   `mapstructure:"statistics"`
+	GeoIP        GeoIPConfig        `mapstructure:"geoip"`
+	Server       ServerConfig       `mapstructure:"server"`
 	ServerDomain ServerDomainConfig `mapstructure:"server-domain"`
 	Slug         SlugConfig         `mapstructure:"slug"`
+	Auth         AuthConfig         `mapstructure:"auth"`
+	RateLimit    RateLimitConfig    `mapstructure:"rate-limit"`
+}
+
+// StorageBackend is the type of the backend storing the data of the service
+type StorageBackend string
+
+var (
+	// StorageBackendPSQL stores the data within a PSQL database and a Redis
+	StorageBackendPSQL StorageBackend = "psql"
+	// StorageBackendMemory stores the data in memory, meant for development as nothing is persisted nor shared between replicas
+	StorageBackendMemory StorageBackend = "memory"
+	// StorageBackendBolt stores the URL mappings within an embedded bbolt database file, meant for a single replica without database
+	StorageBackendBolt StorageBackend = "bolt"
+)
+
+// StorageConfig represents the configuration of the storage
+type StorageConfig struct {
+	Backend StorageBackend `mapstructure:"backend"`
+	Bolt    BoltConfig     `mapstructure:"bolt"`
+}
+
+// BoltConfig represents the configuration of the embedded bbolt database
+type BoltConfig struct {
+	Path string `mapstructure:"path"` // The database file, created if missing
 }
 
-// PSQLConnConfig represents the configuration to connect to a PSQL database
+// PSQLConnConfig represents the configuration to connect to a PSQL database through a pool of connections
 type PSQLConnConfig struct {
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	DbName   string `mapstructure:"dbname"`
+	User              string        `mapstructure:"user"`
+	Password          string        `mapstructure:"password"`
+	Host              string        `mapstructure:"host"`
+	Port              int           `mapstructure:"port"`
+	DbName            string        `mapstructure:"dbname"`
+	MaxConns          int           `mapstructure:"max-conns"`           // The maximal size of the pool
+	MinConns          int           `mapstructure:"min-conns"`           // The number of connections kept open even when idle
+	MaxConnIdleTime   time.Duration `mapstructure:"max-conn-idle-time"`  // The duration after which an idle connection is closed
+	HealthCheckPeriod time.Duration `mapstructure:"health-check-period"` // The period of the idle connections health check
+	AutoMigrate       bool          `mapstructure:"auto-migrate"`        // Whether the pending migrations are applied at startup
 }
 
 // ToConnString generates a conn string based on the conn config
@@ -68,6 +152,64 @@ func (c *RedisConfig) ToAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// StatisticsConfig represents the configuration of the statistics recorded per bucket of time
+type StatisticsConfig struct {
+	HourlyRetention    time.Duration          `mapstructure:"hourly-retention"`     // The duration the hourly buckets are kept, 0 meaning not recorded
+	DailyRetention     time.Duration          `mapstructure:"daily-retention"`      // The duration the daily buckets are kept, 0 meaning not recorded
+	VisitorSalt        string                 `mapstructure:"visitor-salt"`         // Optional, the secret salt of the hash identifying the unique visitors, loaded from VISITOR_SALT
+	BreakdownMaxValues int                    `mapstructure:"breakdown-max-values"` // The values of a dimension kept per slug, the least clicked ones being dropped, 0 meaning no limit
+	Buffer             StatisticsBufferConfig `mapstructure:"buffer"`
+}
+
+// StatisticsBufferConfig represents the configuration of the buffer in front of Redis, coalescing the statistics to store them in batches
+type StatisticsBufferConfig struct {
+	QueueSize     int           `mapstructure:"queue-size"`     // The maximal number of writes waiting for a worker, the next ones being dropped
+	Workers       int           `mapstructure:"workers"`        // The number of workers coalescing and storing the statistics
+	FlushSize     int           `mapstructure:"flush-size"`     // The number of statistics a worker coalesces before storing them
+	FlushInterval time.Duration `mapstructure:"flush-interval"` // The maximal duration a statistic waits within a worker before being stored
+}
+
+// GeoIPConfig represents the configuration of the geolocation of the clients accessing the links
+type GeoIPConfig struct {
+	DatabasePath string `mapstructure:"database-path"` // Optional, a MaxMind country database file such as GeoLite2 Country, the countries being unknown without it
+}
+
+// CacheConfig represents the configuration of the in memory cache of URL mappings in front of the database
+type CacheConfig struct {
+	Enabled      bool               `mapstructure:"enabled"`
+	Capacity     int                `mapstructure:"capacity"`     // The maximal number of URL mappings, the least recently used one being dropped first
+	TTL          time.Duration      `mapstructure:"ttl"`          // The duration an URL mapping is kept, 0 meaning until dropped to make room
+	NegativeTTL  time.Duration      `mapstructure:"negative-ttl"` // The duration an unknown slug is remembered as such, 0 meaning never
+	BloomFilter  BloomFilterConfig  `mapstructure:"bloom-filter"`
+	Invalidation InvalidationConfig `mapstructure:"invalidation"`
+	Shared       SharedCacheConfig  `mapstructure:"shared"`
+}
+
+// SharedCacheConfig represents the configuration of the cache of URL mappings shared by all the replicas through Redis, between the in memory cache and the database
+type SharedCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`     // The duration an URL mapping is kept, shortened to its expiration
+	Timeout time.Duration `mapstructure:"timeout"` // The maximal duration to connect to Redis or wait for its answer, the database being used instead
+}
+
+// InvalidationConfig represents the configuration of the invalidation of the caches of all the replicas through a Redis pub/sub channel
+type InvalidationConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Channel string `mapstructure:"channel"`
+}
+
+// BloomFilterConfig represents the configuration of the bloom filter of the known slugs, answering the unknown ones without the database
+type BloomFilterConfig struct {
+	Enabled           bool    `mapstructure:"enabled"`
+	ExpectedSlugs     int     `mapstructure:"expected-slugs"`      // The number of slugs the filter is sized for
+	FalsePositiveRate float64 `mapstructure:"false-positive-rate"` // The rate of unknown slugs still looked up once the expected number of slugs is reached
+}
+
+// ServerConfig represents the configuration of the HTTP server
+type ServerConfig struct {
+	TrustedProxies []string `mapstructure:"trusted-proxies"` // The IPs or CIDRs of the proxies whose X-Forwarded-For header is trusted, none by default so that the client IP is the peer one
+}
+
 // ServerDomainConfig represents the configuration of the server domain
 type ServerDomainConfig struct {
 	Scheme string `mapstructure:"scheme"`
@@ -86,6 +228,35 @@ func (c *ServerDomainConfig) CreateBaseURL() string {
 
 // SlugConfig represents the configuration of the slug
 type SlugConfig struct {
-	MaximalLenght int           `mapstructure:"maximal-lenght"`
-	TimeToExpire  time.Duration `mapstructure:"time-to-expire"`
+	MaximalLenght       int           `mapstructure:"maximal-lenght"`
+	CustomMaximalLenght int           `mapstructure:"custom-maximal-lenght"`
+	AllowedCharacters   string        `mapstructure:"allowed-characters"`
+	MaxCollisionRetries int           `mapstructure:"max-collision-retries"`
+	TimeToExpire        time.Duration `mapstructure:"time-to-expire"`
+}
+
+// ValidatorMaximalLenght returns the maximal lenght a slug can have, either generated (extended on collisions) or custom
+func (c *SlugConfig) ValidatorMaximalLenght() int {
+	return max(c.MaximalLenght+c.MaxCollisionRetries, c.CustomMaximalLenght)
+}
+
+// AuthConfig represents the configuration of the API key authentication
+type AuthConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	AdminKey string `mapstructure:"admin-key"` // Optional, the secret of the bootstrap admin API key, loaded from ADMIN_API_KEY
+}
+
+// RateLimitConfig represents the configuration of the rate limits per route, shared by all the replicas through Redis
+type RateLimitConfig struct {
+	Enabled      bool                `mapstructure:"enabled"`
+	Shorten      RateLimitRuleConfig `mapstructure:"shorten"`       // POST /shorten
+	ShortenBatch RateLimitRuleConfig `mapstructure:"shorten-batch"` // POST /shorten/batch
+	Redirect     RateLimitRuleConfig `mapstructure:"redirect"`      // GET /:slug and /:slug/force
+	ResolveBatch RateLimitRuleConfig `mapstructure:"resolve-batch"` // POST /resolve/batch
+}
+
+// RateLimitRuleConfig represents a rate limit of a route, 0 requests meaning unlimited
+type RateLimitRuleConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
 }
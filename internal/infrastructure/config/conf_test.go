@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,27 @@ func TestLoadConfig(t *testing.T) {
 		// Then
 		require.NoError(t, err)
 		assert.NotEmpty(t, conf)
+		assert.Equal(t, StorageBackendPSQL, conf.Storage.Backend)
+		assert.Equal(t, "data/urls.db", conf.Storage.Bolt.Path)
+		assert.Equal(t, 10, conf.Database.MaxConns)
+		assert.Equal(t, time.Minute, conf.Database.HealthCheckPeriod)
+		assert.True(t, conf.Database.AutoMigrate)
+		assert.Empty(t, conf.GeoIP.DatabasePath)
+		assert.Equal(t, StatisticsConfig{
+			HourlyRetention:    7 * 24 * time.Hour,
+			DailyRetention:     365 * 24 * time.Hour,
+			BreakdownMaxValues: 1000,
+			Buffer:             StatisticsBufferConfig{QueueSize: 10000, Workers: 4, FlushSize: 500, FlushInterval: time.Second},
+		}, conf.Statistics)
+		assert.Equal(t, CacheConfig{
+			Enabled:      true,
+			Capacity:     10000,
+			TTL:          time.Minute,
+			NegativeTTL:  5 * time.Second,
+			BloomFilter:  BloomFilterConfig{ExpectedSlugs: 1_000_000, FalsePositiveRate: 0.01},
+			Invalidation: InvalidationConfig{Enabled: true, Channel: "shorturl:invalidations"},
+			Shared:       SharedCacheConfig{Enabled: true, TTL: time.Hour, Timeout: 100 * time.Millisecond},
+		}, conf.Cache)
 	})
 	t.Run("config file not fount", func(t *testing.T) {
 		// Given
@@ -95,3 +117,33 @@ func TestCreateBaseURL(t *testing.T) {
 		assert.Equal(t, "https://www.example.com", baseURL)
 	})
 }
+
+func TestValidatorMaximalLenght(t *testing.T) {
+	t.Run("custom lenght is greater", func(t *testing.T) {
+		// Given
+		cfg := SlugConfig{
+			MaximalLenght:       8,
+			CustomMaximalLenght: 32,
+		}
+
+		// When
+		maximalLenght := cfg.ValidatorMaximalLenght()
+
+		// Then
+		assert.Equal(t, 32, maximalLenght)
+	})
+	t.Run("generated lenght is greater", func(t *testing.T) {
+		// Given
+		cfg := SlugConfig{
+			MaximalLenght:       8,
+			CustomMaximalLenght: 4,
+			MaxCollisionRetries: 3,
+		}
+
+		// When
+		maximalLenght := cfg.ValidatorMaximalLenght()
+
+		// Then
+		assert.Equal(t, 11, maximalLenght)
+	})
+}
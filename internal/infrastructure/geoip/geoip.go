@@ -0,0 +1,9 @@
+package geoip
+
+import "net"
+
+// Locator represents operations on the geolocation of IP addresses
+type Locator interface {
+	// Country returns the ISO 3166-1 alpha-2 code of the country of the IP address, empty if unknown
+	Country(ip net.IP) (string, error)
+}
@@ -0,0 +1,46 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// MaxMindLocator represents a locator reading an offline MaxMind database file, such as GeoLite2 Country, thread proof
+type MaxMindLocator struct {
+	reader *maxminddb.Reader
+}
+
+// maxMindCountryRecord represents the part of a record of a MaxMind database holding the country
+type maxMindCountryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// NewMaxMindLocator opens the MaxMind database file and return it inside a MaxMindLocator
+func NewMaxMindLocator(path string) (*MaxMindLocator, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database [%s]: %w", path, err)
+	}
+
+	return &MaxMindLocator{reader: reader}, nil
+}
+
+// Country implements the Locator interface
+func (l *MaxMindLocator) Country(ip net.IP) (string, error) {
+	var record maxMindCountryRecord
+	err := l.reader.Lookup(ip, &record)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate IP [%s]: %w", ip, err)
+	}
+
+	return record.Country.ISOCode, nil
+}
+
+// Close closes the database file
+func (l *MaxMindLocator) Close() error {
+	return l.reader.Close()
+}
@@ -0,0 +1,66 @@
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabase is a MaxMind database of IPv4 addresses with a single node, locating 0.0.0.0/1 in France and nothing else
+var testDatabase = []byte(
+	// Search tree of a single node of 24 bits records, the left one pointing to the data and the right one to nothing
+	"\x00\x00\x11\x00\x00\x01" +
+		// Data section separator
+		"\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00" +
+		// Data section: {"country": {"iso_code": "FR"}}
+		"\xe1\x47country\xe1\x48iso_code\x42FR" +
+		// Metadata: {"node_count": 1, "record_size": 24, "ip_version": 4}
+		"\xab\xcd\xefMaxMind.com" +
+		"\xe3\x4anode_count\xc1\x01\x4brecord_size\xa1\x18\x4aip_version\xa1\x04")
+
+func newTestMaxMindLocator(t *testing.T) *MaxMindLocator {
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	require.NoError(t, os.WriteFile(path, testDatabase, 0o600))
+	locator, err := NewMaxMindLocator(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { locator.Close() })
+	return locator
+}
+
+func TestMaxMindLocator(t *testing.T) {
+	scenarios := []struct {
+		Name            string
+		IP              net.IP
+		ExpectedCountry string
+		ExpectError     bool
+	}{
+		{Name: "located", IP: net.ParseIP("82.64.1.1"), ExpectedCountry: "FR"},
+		{Name: "not located", IP: net.ParseIP("192.168.1.1"), ExpectedCountry: ""},
+		{Name: "IPv6 within an IPv4 database", IP: net.ParseIP("2001:db8::1"), ExpectError: true},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			locator := newTestMaxMindLocator(t)
+
+			// When
+			country, err := locator.Country(scenario.IP)
+
+			// Then
+			require.Equal(t, scenario.ExpectError, err != nil)
+			assert.Equal(t, scenario.ExpectedCountry, country)
+		})
+	}
+}
+
+func TestNewMaxMindLocatorMissingFile(t *testing.T) {
+	// When
+	_, err := NewMaxMindLocator(filepath.Join(t.TempDir(), "missing.mmdb"))
+
+	// Then
+	require.Error(t, err)
+}
@@ -0,0 +1,14 @@
+package invalidation
+
+import (
+	"context"
+)
+
+// Bus represents operations on invalidation Bus, spreading the changed slugs between the replicas of the service
+type Bus interface {
+	// Publish informs the other replicas that the URL mappings of the slugs have changed (created, updated, deleted or expired)
+	Publish(ctx context.Context, slugs []string) error
+	// Subscribe calls onInvalidate with the slugs published by the other replicas until the context is done
+	// As the slugs published while disconnected are lost, onDisconnect is called once the subscription fails and onResubscribe once subscribed again
+	Subscribe(ctx context.Context, onInvalidate func(slugs []string), onResubscribe func(), onDisconnect func())
+}
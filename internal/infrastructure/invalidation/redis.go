@@ -0,0 +1,138 @@
+package invalidation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/glog"
+)
+
+const (
+	// minResubscribeDelay is the delay before subscribing again after a first failure
+	minResubscribeDelay = 100 * time.Millisecond
+	// maxResubscribeDelay is the maximal delay between two subscription attempts
+	maxResubscribeDelay = 5 * time.Second
+)
+
+// message represents the payload published on the channel
+type message struct {
+	Origin string   `json:"origin"` // The replica that published the message, which ignores it
+	Slugs  []string `json:"slugs"`
+}
+
+// RedisBus represents a bus relying on a redis pub/sub channel
+type RedisBus struct {
+	client  *redis.Client
+	channel string
+	origin  string
+}
+
+// NewRedisBus connects to a redis and return it inside a RedisBus publishing on the channel
+func NewRedisBus(cfg config.RedisConfig, channel string) (*RedisBus, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: cfg.ToAddr(),
+	})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	origin := make([]byte, 8)
+	_, err = rand.Read(origin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the replica identifier: %w", err)
+	}
+
+	return &RedisBus{client: client, channel: channel, origin: hex.EncodeToString(origin)}, nil
+}
+
+// Publish implements the Bus interface
+func (b *RedisBus) Publish(ctx context.Context, slugs []string) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(message{Origin: b.origin, Slugs: slugs})
+	if err != nil {
+		return err
+	}
+	err = b.client.Publish(ctx, b.channel, payload).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish the invalidation of %d slugs: %w", len(slugs), err)
+	}
+	return nil
+}
+
+// Subscribe implements the Bus interface
+func (b *RedisBus) Subscribe(ctx context.Context, onInvalidate func(slugs []string), onResubscribe func(), onDisconnect func()) {
+	delay := minResubscribeDelay
+	disconnected := false
+	for ctx.Err() == nil {
+		err := b.receive(ctx, onInvalidate, func() {
+			if disconnected {
+				onResubscribe()
+			}
+			disconnected = false
+			delay = minResubscribeDelay
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !disconnected {
+			onDisconnect()
+		}
+		disconnected = true
+		glog.Warningf("invalidation channel [%s] disconnected, subscribing again in %s: %s", b.channel, delay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxResubscribeDelay)
+	}
+}
+
+// receive subscribes to the channel and handles its messages until an error occurs
+func (b *RedisBus) receive(ctx context.Context, onInvalidate func(slugs []string), onSubscribed func()) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+	// The blocking reads don't watch the context, closing the subscription ends them
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+
+	// Wait for the subscription confirmation
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	onSubscribed()
+
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+
+		var m message
+		err = json.Unmarshal([]byte(msg.Payload), &m)
+		if err != nil {
+			glog.Warningf("invalid message on invalidation channel [%s]: %s", b.channel, err)
+			continue
+		}
+		if m.Origin != b.origin {
+			onInvalidate(m.Slugs)
+		}
+	}
+}
+
+// Close closes the redis connection
+func (b *RedisBus) Close() error {
+	return b.client.Close()
+}
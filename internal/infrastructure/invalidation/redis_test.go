@@ -0,0 +1,67 @@
+package invalidation
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The pub/sub is not supported by miniredis, the tests rely on the redis of docker-compose.test.yml
+func TestRedisBus(t *testing.T) {
+	os.Setenv("env", "test")
+	defer os.Unsetenv("env")
+	conf, err := config.Load()
+	require.NoError(t, err)
+	channel := "test:invalidations:" + t.Name()
+
+	publisher, err := NewRedisBus(conf.Redis, channel)
+	require.NoError(t, err)
+	defer publisher.Close()
+	subscriber, err := NewRedisBus(conf.Redis, channel)
+	require.NoError(t, err)
+	defer subscriber.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	invalidated := make(chan []string, 10)
+	go subscriber.Subscribe(ctx, func(slugs []string) { invalidated <- slugs }, func() {}, func() {})
+
+	t.Run("nominal", func(t *testing.T) {
+		// When
+		require.Eventually(t, func() bool {
+			// Published until the subscription is confirmed
+			err := publisher.Publish(context.Background(), []string{"jV6gHv0o", "spring-sale"})
+			require.NoError(t, err)
+			select {
+			case slugs := <-invalidated:
+				// Then
+				assert.Equal(t, []string{"jV6gHv0o", "spring-sale"}, slugs)
+				return true
+			case <-time.After(100 * time.Millisecond):
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+	t.Run("own messages ignored", func(t *testing.T) {
+		// Given
+		for len(invalidated) > 0 {
+			<-invalidated
+		}
+
+		// When
+		err := subscriber.Publish(context.Background(), []string{"own"})
+		require.NoError(t, err)
+
+		// Then
+		select {
+		case slugs := <-invalidated:
+			assert.NotContains(t, slugs, "own")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
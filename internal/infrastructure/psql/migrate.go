@@ -0,0 +1,293 @@
+package psql
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrNoDownMigration is the error when a migration to roll back has no down SQL
+	ErrNoDownMigration error = errors.New("migration has no down sql")
+	// ErrInvalidMigration is the error when a migration file name or content is invalid
+	ErrInvalidMigration error = errors.New("invalid migration")
+)
+
+// migrationsLockID is the key of the advisory lock held while migrating, so that concurrent replicas don't race
+const migrationsLockID int64 = 4_801_233_718_265_093
+
+// defaultTimeToExpireSetting is the transaction setting holding the default expiration of the URLs (slug.time-to-expire), read by the migrations with current_setting
+const defaultTimeToExpireSetting string = "url_shortener.default_time_to_expire"
+
+// migrationFileRegexp matches the migration file names such as 0001_create_urls.up.sql
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migration represents a versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string // Optional, the migration can't be rolled back without it
+}
+
+// MigrationStatus represents a migration along with its application date, nil if pending
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back the migrations embedded in the binary
+type Migrator struct {
+	pool                *pgxpool.Pool
+	migrations          []Migration
+	defaultTimeToExpire time.Duration // Backfills the expiration of the URLs stored before it was introduced, 0 meaning never
+}
+
+// NewMigrator creates a migrator of the embedded migrations on the database of the pool
+func NewMigrator(pool *pgxpool.Pool, defaultTimeToExpire time.Duration) (*Migrator, error) {
+	subFS, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := loadMigrations(subFS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations, defaultTimeToExpire: defaultTimeToExpire}, nil
+}
+
+// MigrateUp applies the pending embedded migrations on the database of the pool
+func MigrateUp(ctx context.Context, pool *pgxpool.Pool, defaultTimeToExpire time.Duration) ([]Migration, error) {
+	migrator, err := NewMigrator(pool, defaultTimeToExpire)
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Up(ctx)
+}
+
+// loadMigrations reads the up and down SQL files of the migrations and sorts them by version
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%w: unexpected file name [%s]", ErrInvalidMigration, entry.Name())
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unexpected version [%s]", ErrInvalidMigration, entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration [%s]: %w", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: version [%d] used by [%s] and [%s]", ErrInvalidMigration, version, migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: version [%d] has no up sql", ErrInvalidMigration, migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// withLock runs the function on a connection holding the migrations advisory lock, after ensuring the schema_migrations table exists
+func (m *Migrator) withLock(ctx context.Context, f func(conn *pgxpool.Conn) error) error {
+	// A session advisory lock belongs to a connection, the whole migration uses the same one
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1);", migrationsLockID)
+	if err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1);", migrationsLockID)
+
+	_, err = conn.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	);`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return f(conn)
+}
+
+// appliedVersions retrieves the versions already applied along with their application date
+func appliedVersions(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}) (map[int64]time.Time, error) {
+	rows, err := q.Query(ctx, "SELECT version, applied_at FROM schema_migrations;")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		err := rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// toInterval formats a duration as a PostgreSQL interval
+func toInterval(d time.Duration) string {
+	return fmt.Sprintf("%d microseconds", d.Microseconds())
+}
+
+// runInTx runs the SQL of a migration and records it within a single transaction
+func (m *Migrator) runInTx(ctx context.Context, conn *pgxpool.Conn, sql string, record string, args ...any) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	// Local to the transaction, so that it does not leak to the pooled connection
+	_, err = tx.Exec(ctx, "SELECT set_config($1, $2, true);", defaultTimeToExpireSetting, toInterval(m.defaultTimeToExpire))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, sql)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, record, args...)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Up applies the pending migrations in order and returns them
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var migrated []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.runInTx(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3);",
+				migration.Version, migration.Name, time.Now().UTC())
+			if err != nil {
+				return fmt.Errorf("failed to apply migration [%d_%s]: %w", migration.Version, migration.Name, err)
+			}
+			migrated = append(migrated, migration)
+		}
+		return nil
+	})
+	return migrated, err
+}
+
+// Down rolls back the given number of the last applied migrations and returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: [%d_%s]", ErrNoDownMigration, migration.Version, migration.Name)
+			}
+			err := m.runInTx(ctx, conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1;",
+				migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration [%d_%s]: %w", migration.Version, migration.Name, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status returns every migration along with its application date, nil if pending
+// It only reads the schema_migrations table, without the lock, so that it does not wait for a running migration
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var tableExists bool
+	err := m.pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL;").Scan(&tableExists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table: %w", err)
+	}
+	// Every migration is pending until the table is created by the first one applied
+	applied := map[int64]time.Time{}
+	if tableExists {
+		applied, err = appliedVersions(ctx, m.pool)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
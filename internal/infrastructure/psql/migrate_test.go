@@ -0,0 +1,93 @@
+package psql
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		fsys := fstest.MapFS{
+			"0002_add_column.up.sql":     {Data: []byte("ALTER TABLE t ADD COLUMN c TEXT;")},
+			"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE t ();")},
+			"0001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+		}
+
+		// When
+		migrations, err := loadMigrations(fsys)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{
+			{Version: 1, Name: "create_table", Up: "CREATE TABLE t ();", Down: "DROP TABLE t;"},
+			{Version: 2, Name: "add_column", Up: "ALTER TABLE t ADD COLUMN c TEXT;"},
+		}, migrations)
+	})
+	t.Run("embedded migrations", func(t *testing.T) {
+		// When
+		migrator, err := NewMigrator(nil, 0)
+
+		// Then
+		require.NoError(t, err)
+		require.NotEmpty(t, migrator.migrations)
+		for i, migration := range migrator.migrations {
+			assert.Equal(t, int64(i+1), migration.Version)
+			assert.NotEmpty(t, migration.Down)
+		}
+	})
+	t.Run("unexpected file name", func(t *testing.T) {
+		// Given
+		fsys := fstest.MapFS{"create_table.sql": {Data: []byte("CREATE TABLE t ();")}}
+
+		// When
+		_, err := loadMigrations(fsys)
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidMigration)
+	})
+	t.Run("missing up sql", func(t *testing.T) {
+		// Given
+		fsys := fstest.MapFS{"0001_create_table.down.sql": {Data: []byte("DROP TABLE t;")}}
+
+		// When
+		_, err := loadMigrations(fsys)
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidMigration)
+	})
+	t.Run("version used twice", func(t *testing.T) {
+		// Given
+		fsys := fstest.MapFS{
+			"0001_create_table.up.sql": {Data: []byte("CREATE TABLE t ();")},
+			"0001_create_other.up.sql": {Data: []byte("CREATE TABLE o ();")},
+		}
+
+		// When
+		_, err := loadMigrations(fsys)
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidMigration)
+	})
+}
+
+func TestToInterval(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// When
+		interval := toInterval(7*24*time.Hour + time.Microsecond)
+
+		// Then
+		assert.Equal(t, "604800000001 microseconds", interval)
+	})
+	t.Run("never expires", func(t *testing.T) {
+		// When
+		interval := toInterval(0)
+
+		// Then
+		assert.Equal(t, "0 microseconds", interval)
+	})
+}
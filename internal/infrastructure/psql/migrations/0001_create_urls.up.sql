@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS urls (
+	slug TEXT PRIMARY KEY,
+	original_url TEXT NOT NULL,
+	inserted_at TIMESTAMP NOT NULL
+);
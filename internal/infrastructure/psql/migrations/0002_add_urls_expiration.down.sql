@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS url_tombstones;
+ALTER TABLE urls DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,9 @@
+-- The URLs stored before the expiration was introduced get the default one (slug.time-to-expire, passed by the migrator), counted from their insertion
+-- A default expiration of 0 leaves them without expiration, meaning that they never expire
+ALTER TABLE urls ADD COLUMN expires_at TIMESTAMP;
+UPDATE urls SET expires_at = inserted_at + NULLIF(current_setting('url_shortener.default_time_to_expire')::INTERVAL, INTERVAL '0');
+CREATE TABLE url_tombstones (
+	slug TEXT PRIMARY KEY,
+	expired_at TIMESTAMP NOT NULL,
+	reason TEXT NOT NULL
+);
@@ -0,0 +1,2 @@
+-- A hash index as a B-tree one would reject the longest URLs
+CREATE INDEX urls_original_url_idx ON urls USING HASH (original_url);
@@ -0,0 +1,8 @@
+CREATE TABLE api_keys (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	key_hash TEXT NOT NULL UNIQUE,
+	role TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
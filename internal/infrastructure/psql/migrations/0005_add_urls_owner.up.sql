@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN owner TEXT;
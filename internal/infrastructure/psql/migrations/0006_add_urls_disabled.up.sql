@@ -0,0 +1,2 @@
+-- A disabled URL is kept but can't be retrieved anymore until it is enabled again
+ALTER TABLE urls ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,79 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolStats represents the statistics of a pool of connections, as exposed for monitoring
+type PoolStats struct {
+	MaxConns                int32 `json:"max_conns"`
+	TotalConns              int32 `json:"total_conns"`
+	IdleConns               int32 `json:"idle_conns"`
+	AcquiredConns           int32 `json:"acquired_conns"`
+	ConstructingConns       int32 `json:"constructing_conns"`
+	AcquireCount            int64 `json:"acquire_count"`
+	AcquireDurationMs       int64 `json:"acquire_duration_ms"` // The total duration waited for a connection
+	EmptyAcquireCount       int64 `json:"empty_acquire_count"` // The number of acquires that waited for a connection to be released or created
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	NewConnsCount           int64 `json:"new_conns_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+}
+
+// NewPool connects a pool of connections to a database given its configuration
+// The zero values of the pool configuration keep the pgxpool defaults
+func NewPool(ctx context.Context, connConf config.PSQLConnConfig) (*pgxpool.Pool, error) {
+	poolConf, err := pgxpool.ParseConfig(connConf.ToConnString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database configuration: %w", err)
+	}
+	if connConf.MaxConns > 0 {
+		poolConf.MaxConns = int32(connConf.MaxConns)
+	}
+	if connConf.MinConns > 0 {
+		poolConf.MinConns = int32(min(connConf.MinConns, int(poolConf.MaxConns)))
+	}
+	if connConf.MaxConnIdleTime > 0 {
+		poolConf.MaxConnIdleTime = connConf.MaxConnIdleTime
+	}
+	if connConf.HealthCheckPeriod > 0 {
+		poolConf.HealthCheckPeriod = connConf.HealthCheckPeriod
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// The pool connects lazily, ensure the database is reachable
+	err = pool.Ping(ctx)
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return pool, nil
+}
+
+// StatsOf returns the statistics of a pool of connections
+func StatsOf(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		MaxConns:                stat.MaxConns(),
+		TotalConns:              stat.TotalConns(),
+		IdleConns:               stat.IdleConns(),
+		AcquiredConns:           stat.AcquiredConns(),
+		ConstructingConns:       stat.ConstructingConns(),
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDurationMs:       stat.AcquireDuration().Milliseconds(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+	}
+}
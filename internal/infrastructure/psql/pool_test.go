@@ -0,0 +1,46 @@
+package psql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPool(t *testing.T) {
+	os.Setenv("env", "test")
+	defer os.Unsetenv("env")
+	conf, err := config.Load()
+	require.NoError(t, err)
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		connConf := conf.Database
+		connConf.MaxConns = 4
+
+		// When
+		pool, err := NewPool(context.Background(), connConf)
+		require.NoError(t, err)
+		defer pool.Close()
+
+		// Then
+		stats := StatsOf(pool)
+		assert.Equal(t, int32(4), stats.MaxConns)
+		assert.GreaterOrEqual(t, stats.TotalConns, int32(1))
+	})
+	t.Run("unreachable database", func(t *testing.T) {
+		// Given
+		connConf := conf.Database
+		connConf.Port = 1
+
+		// When
+		pool, err := NewPool(context.Background(), connConf)
+
+		// Then
+		require.Error(t, err)
+		assert.Nil(t, pool)
+	})
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepThreshold is the number of keys above which the full buckets are dropped
+const memorySweepThreshold = 10_000
+
+// MemoryStore represents an in memory store, meant for development as the limits are not shared by the replicas
+// It implements the same generic cell rate algorithm (GCRA) as the redis store
+type MemoryStore struct {
+	mutex sync.Mutex
+	tats  map[string]time.Time // The theoretical arrival time of the next request per key
+}
+
+// NewMemoryStore creates an empty in memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tats: map[string]time.Time{}}
+}
+
+// Allow implements the Store interface
+func (s *MemoryStore) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if len(s.tats) > memorySweepThreshold {
+		for k, tat := range s.tats {
+			if !tat.After(now) {
+				delete(s.tats, k)
+			}
+		}
+	}
+
+	interval := max(rule.Window/time.Duration(rule.Requests), time.Millisecond)
+	tat := s.tats[key]
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-time.Duration(rule.Requests) * interval)
+	if allowAt.After(now) {
+		return Result{Allowed: false, Limit: rule.Requests, RetryAfter: allowAt.Sub(now), ResetAfter: tat.Sub(now)}, nil
+	}
+
+	s.tats[key] = newTat
+	return Result{
+		Allowed:    true,
+		Limit:      rule.Requests,
+		Remaining:  int(now.Sub(allowAt) / interval),
+		ResetAfter: newTat.Sub(now),
+	}, nil
+}
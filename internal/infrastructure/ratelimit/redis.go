@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// keyPrefix prefixes the rate limit keys within Redis
+const keyPrefix string = "ratelimit:"
+
+// allowScript implements a token bucket with the generic cell rate algorithm (GCRA)
+// Only the theoretical arrival time (TAT) of the next request is stored, in milliseconds, and it expires once the bucket is full again
+// Numbers are formatted as integers before being given to Redis, Lua would format them with an exponent otherwise
+// KEYS[1] is the key, ARGV[1] the current time, ARGV[2] the emission interval of a request and ARGV[3] the burst, the two first in milliseconds
+// It returns whether the request is allowed, the remaining requests, the retry after and the reset after in milliseconds
+var allowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then
+	tat = now
+end
+
+local newTat = tat + interval
+local allowAt = newTat - burst * interval
+if allowAt > now then
+	return {0, 0, allowAt - now, tat - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%d', newTat), 'PX', string.format('%d', newTat - now))
+return {1, math.floor((now - allowAt) / interval), 0, newTat - now}
+`)
+
+// RedisStore represents a redis store, shared by all the replicas of the service
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to a redis and return it inside a RedisStore
+func NewRedisStore(cfg config.RedisConfig) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: cfg.ToAddr(),
+	})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &RedisStore{client: client}, nil
+}
+
+// Allow implements the Store interface
+func (s *RedisStore) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	interval := max(rule.Window.Milliseconds()/int64(rule.Requests), 1)
+	res, err := allowScript.Run(ctx, s.client, []string{keyPrefix + key}, time.Now().UnixMilli(), interval, rule.Requests).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to apply rate limit for [%s]: %w", key, err)
+	}
+	if len(res) != 4 {
+		return Result{}, fmt.Errorf("failed to apply rate limit for [%s]: unexpected script result %v", key, res)
+	}
+
+	return Result{
+		Allowed:    res[0] == 1,
+		Limit:      rule.Requests,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		ResetAfter: time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
+
+// Close closes the redis connection
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Rule represents a rate limit: at most Requests requests per Window, bursts of up to Requests requests included
+type Rule struct {
+	Requests int
+	Window   time.Duration
+}
+
+// IsZero informs if the rule does not limit anything
+func (r Rule) IsZero() bool {
+	return r.Requests <= 0 || r.Window <= 0
+}
+
+// Result represents the outcome of a request against a rate limit
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // The number of requests still allowed right now
+	RetryAfter time.Duration // The time to wait before the next request is allowed, zero if allowed
+	ResetAfter time.Duration // The time until the limit is fully replenished
+}
+
+// Store represents operations on rate limit Store
+type Store interface {
+	// Allow consumes one request of the key against the rule and informs if it is allowed
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
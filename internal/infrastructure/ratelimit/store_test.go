@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type StoreTestSuite struct {
+	Store
+}
+
+func RunStoreTests(t *testing.T, store Store) {
+	suite := &StoreTestSuite{Store: store}
+
+	t.Run("TestAllow", suite.TestAllow)
+	t.Run("TestAllowIsolatesKeys", suite.TestAllowIsolatesKeys)
+}
+
+func (suite *StoreTestSuite) TestAllow(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	rule := Rule{Requests: 2, Window: time.Hour}
+
+	// When
+	first, err := suite.Store.Allow(ctx, "allow-test", rule)
+	require.NoError(t, err)
+	second, err := suite.Store.Allow(ctx, "allow-test", rule)
+	require.NoError(t, err)
+	third, err := suite.Store.Allow(ctx, "allow-test", rule)
+	require.NoError(t, err)
+
+	// Then
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 2, first.Limit)
+	assert.Equal(t, 1, first.Remaining)
+	assert.Zero(t, first.RetryAfter)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, 0, third.Remaining)
+	assert.InDelta(t, 30*time.Minute, third.RetryAfter, float64(time.Second))
+	assert.InDelta(t, time.Hour, third.ResetAfter, float64(time.Second))
+}
+
+func (suite *StoreTestSuite) TestAllowIsolatesKeys(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	rule := Rule{Requests: 1, Window: time.Minute}
+	_, err := suite.Store.Allow(ctx, "isolate-test-1", rule)
+	require.NoError(t, err)
+
+	// When
+	result, err := suite.Store.Allow(ctx, "isolate-test-2", rule)
+	require.NoError(t, err)
+
+	// Then
+	assert.True(t, result.Allowed)
+}
@@ -0,0 +1,67 @@
+package shorturl
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// bloomFilter represents a set of slugs that may tell a slug was added when it was not, but never the opposite
+type bloomFilter struct {
+	mu     sync.RWMutex
+	bits   []uint64
+	size   uint64 // The number of bits
+	hashes uint64 // The number of bits set per slug
+}
+
+// newBloomFilter creates a bloom filter sized to hold the expected number of slugs with the given false positive rate
+func newBloomFilter(expectedSlugs int, falsePositiveRate float64) *bloomFilter {
+	n := math.Max(float64(expectedSlugs), 1)
+	size := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	size = max(size, 64)
+	hashes := uint64(math.Max(math.Round(float64(size)/n*math.Ln2), 1))
+	return &bloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// positions returns the bits of a slug, derived from two hashes (Kirsch-Mitzenmacher)
+func (f *bloomFilter) positions(slug string) []uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(slug))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31 | 1 // Odd so that the positions don't repeat
+
+	positions := make([]uint64, f.hashes)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % f.size
+	}
+	return positions
+}
+
+// add adds a slug to the filter
+func (f *bloomFilter) add(slug string) {
+	positions := f.positions(slug)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, position := range positions {
+		f.bits[position/64] |= 1 << (position % 64)
+	}
+}
+
+// mayContain informs if a slug may have been added, false meaning it certainly was not
+func (f *bloomFilter) mayContain(slug string) bool {
+	positions := f.positions(slug)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, position := range positions {
+		if f.bits[position/64]&(1<<(position%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package shorturl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	t.Run("added slugs", func(t *testing.T) {
+		// Given
+		filter := newBloomFilter(1000, 0.01)
+
+		// When
+		for i := 0; i < 1000; i++ {
+			filter.add(fmt.Sprintf("slug-%d", i))
+		}
+
+		// Then
+		for i := 0; i < 1000; i++ {
+			assert.True(t, filter.mayContain(fmt.Sprintf("slug-%d", i)))
+		}
+	})
+	t.Run("false positive rate", func(t *testing.T) {
+		// Given
+		filter := newBloomFilter(1000, 0.01)
+		for i := 0; i < 1000; i++ {
+			filter.add(fmt.Sprintf("slug-%d", i))
+		}
+
+		// When
+		falsePositives := 0
+		for i := 0; i < 10000; i++ {
+			if filter.mayContain(fmt.Sprintf("unknown-%d", i)) {
+				falsePositives++
+			}
+		}
+
+		// Then
+		assert.Less(t, falsePositives, 300) // 1% expected, with a margin
+	})
+	t.Run("empty", func(t *testing.T) {
+		// Given
+		filter := newBloomFilter(0, 0.01)
+
+		// When
+		mayContain := filter.mayContain("unknown")
+
+		// Then
+		assert.False(t, mayContain)
+	})
+}
@@ -0,0 +1,538 @@
+package shorturl
+
+import (
+	"bytes"
+	context "context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"urlShortenerService/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// urlsBucket holds the URL mappings by slug
+	urlsBucket = []byte("urls")
+	// tombstonesBucket holds the tombstones of the expired URL mappings by slug
+	tombstonesBucket = []byte("url_tombstones")
+	// originalURLIndexBucket indexes the slugs by original URL, the keys being the original URL, a zero byte then the slug
+	originalURLIndexBucket = []byte("urls_original_url_idx")
+	// insertedAtIndexBucket indexes the slugs by inserted date, the keys being the date then the slug
+	insertedAtIndexBucket = []byte("urls_inserted_at_idx")
+	// expiresAtIndexBucket indexes the slugs by expiration date, the keys being the date then the slug
+	expiresAtIndexBucket = []byte("urls_expires_at_idx")
+)
+
+// boltOpenTimeout is the maximal duration to wait for the lock of the database file, held by a single process at a time
+const boltOpenTimeout = time.Second
+
+// boltURLMapping represents an URL mapping as stored within the database
+type boltURLMapping struct {
+	OriginalURL string     `json:"original_url"`
+	InsertedAt  time.Time  `json:"inserted_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	Disabled    bool       `json:"disabled,omitempty"`
+}
+
+// boltTombstone represents the tombstone of an expired URL mapping
+type boltTombstone struct {
+	ExpiredAt time.Time `json:"expired_at"`
+	Reason    string    `json:"reason"`
+}
+
+// BoltStore represents a store embedded within a bbolt database file, for the deployments without PSQL database
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens, or creates, the database file and return it inside a BoltStore
+func NewBoltStore(path string) (*BoltStore, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the database directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database [%s]: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{urlsBucket, tombstonesBucket, originalURLIndexBucket, insertedAtIndexBucket, expiresAtIndexBucket} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// timeKey returns an index key sorting by date then slug
+func timeKey(t time.Time, slug string) []byte {
+	key := make([]byte, 8, 8+len(slug))
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return append(key, slug...)
+}
+
+// originalURLKey returns an index key of an original URL and a slug
+func originalURLKey(originalURL string, slug string) []byte {
+	return []byte(originalURL + "\x00" + slug)
+}
+
+// boltGetURL retrieves the URL mapping of a slug, nil if it does not exist
+func boltGetURL(tx *bolt.Tx, slug string) (*domain.URLMapping, error) {
+	value := tx.Bucket(urlsBucket).Get([]byte(slug))
+	if value == nil {
+		return nil, nil
+	}
+	var stored boltURLMapping
+	err := json.Unmarshal(value, &stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode slug [%s]: %w", slug, err)
+	}
+	return &domain.URLMapping{
+		Slug:        slug,
+		OriginalURL: stored.OriginalURL,
+		InsertedAt:  stored.InsertedAt,
+		ExpiresAt:   stored.ExpiresAt,
+		Owner:       stored.Owner,
+		Disabled:    stored.Disabled,
+	}, nil
+}
+
+// boltPutURL stores an URL mapping along with its index entries
+func boltPutURL(tx *bolt.Tx, url domain.URLMapping) error {
+	value, err := json.Marshal(boltURLMapping{OriginalURL: url.OriginalURL, InsertedAt: url.InsertedAt, ExpiresAt: url.ExpiresAt, Owner: url.Owner, Disabled: url.Disabled})
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(urlsBucket).Put([]byte(url.Slug), value)
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(originalURLIndexBucket).Put(originalURLKey(url.OriginalURL, url.Slug), nil)
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(insertedAtIndexBucket).Put(timeKey(url.InsertedAt, url.Slug), nil)
+	if err != nil {
+		return err
+	}
+	if url.ExpiresAt != nil {
+		return tx.Bucket(expiresAtIndexBucket).Put(timeKey(*url.ExpiresAt, url.Slug), nil)
+	}
+	return nil
+}
+
+// boltDeleteURL deletes an URL mapping along with its index entries
+func boltDeleteURL(tx *bolt.Tx, url domain.URLMapping) error {
+	err := tx.Bucket(urlsBucket).Delete([]byte(url.Slug))
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(originalURLIndexBucket).Delete(originalURLKey(url.OriginalURL, url.Slug))
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(insertedAtIndexBucket).Delete(timeKey(url.InsertedAt, url.Slug))
+	if err != nil {
+		return err
+	}
+	if url.ExpiresAt != nil {
+		return tx.Bucket(expiresAtIndexBucket).Delete(timeKey(*url.ExpiresAt, url.Slug))
+	}
+	return nil
+}
+
+// Delete implements the Store interface
+func (s *BoltStore) Delete(ctx context.Context, slug string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		url, err := boltGetURL(tx, slug)
+		if err != nil {
+			return err
+		}
+		if url == nil {
+			return ErrNotFound
+		}
+		return boltDeleteURL(tx, *url)
+	})
+}
+
+// DeleteExpired implements the Store interface
+// The expired URL mappings are found through the expiration index and replaced by their tombstone
+func (s *BoltStore) DeleteExpired(ctx context.Context) ([]string, error) {
+	now := time.Now()
+	var deletedSlugs []string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var expiredSlugs []string
+		cursor := tx.Bucket(expiresAtIndexBucket).Cursor()
+		for key, _ := cursor.First(); key != nil; key, _ = cursor.Next() {
+			if int64(binary.BigEndian.Uint64(key[:8])) > now.UnixNano() {
+				break
+			}
+			expiredSlugs = append(expiredSlugs, string(key[8:]))
+		}
+
+		for _, slug := range expiredSlugs {
+			url, err := boltGetURL(tx, slug)
+			if err != nil {
+				return err
+			}
+			if url == nil {
+				continue
+			}
+			err = boltDeleteURL(tx, *url)
+			if err != nil {
+				return err
+			}
+			tombstone, err := json.Marshal(boltTombstone{ExpiredAt: *url.ExpiresAt, Reason: tombstoneReasonExpired})
+			if err != nil {
+				return err
+			}
+			err = tx.Bucket(tombstonesBucket).Put([]byte(slug), tombstone)
+			if err != nil {
+				return err
+			}
+			deletedSlugs = append(deletedSlugs, slug)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deletedSlugs, nil
+}
+
+// boltGet retrieves the URL of a slug, ErrExpired if expired or if a tombstone exists, ErrNotFound otherwise
+func boltGet(tx *bolt.Tx, slug string, now time.Time) (domain.URLMapping, error) {
+	url, err := boltGetIncludingExpired(tx, slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	// The URL might be expired but not deleted yet
+	if url.IsExpired(now) {
+		return domain.URLMapping{}, ErrExpired
+	}
+	return url, nil
+}
+
+// boltGetIncludingExpired retrieves the URL mapping of a slug as long as it is not deleted
+func boltGetIncludingExpired(tx *bolt.Tx, slug string) (domain.URLMapping, error) {
+	url, err := boltGetURL(tx, slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	if url == nil {
+		if tx.Bucket(tombstonesBucket).Get([]byte(slug)) != nil {
+			return domain.URLMapping{}, ErrExpired
+		}
+		return domain.URLMapping{}, ErrNotFound
+	}
+	return *url, nil
+}
+
+// Get implements the Store interface
+func (s *BoltStore) Get(ctx context.Context, slug string) (domain.URLMapping, error) {
+	var url domain.URLMapping
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		url, err = boltGet(tx, slug, time.Now())
+		return err
+	})
+	return url, err
+}
+
+// GetIncludingExpired implements the Store interface
+func (s *BoltStore) GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error) {
+	var url domain.URLMapping
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		url, err = boltGetIncludingExpired(tx, slug)
+		return err
+	})
+	return url, err
+}
+
+// GetBatch implements the Store interface
+// All the slugs are retrieved within a single transaction
+func (s *BoltStore) GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error) {
+	now := time.Now()
+	urls := make([]domain.URLMapping, len(slugs))
+	errs := make([]error, len(slugs))
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for i, slug := range slugs {
+			url, err := boltGet(tx, slug, now)
+			switch err {
+			case nil:
+				urls[i] = url
+			case ErrNotFound, ErrExpired:
+				errs[i] = err
+			default:
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return urls, errs, nil
+}
+
+// GetByOriginalURL implements the Store interface
+func (s *BoltStore) GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error) {
+	var urls []domain.URLMapping
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := originalURLKey(originalURL, "")
+		cursor := tx.Bucket(originalURLIndexBucket).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			url, err := boltGetURL(tx, string(key[len(prefix):]))
+			if err != nil {
+				return err
+			}
+			if url != nil {
+				urls = append(urls, *url)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortURLMappings(urls, true)
+	return urls, nil
+}
+
+// List implements the Store interface
+// The URL mappings are browsed through the inserted date index, from the cursor if any
+func (s *BoltStore) List(ctx context.Context, filter ListFilter) (ListPage, error) {
+	var start []byte
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return ListPage{}, err
+		}
+		start = timeKey(cursor.InsertedAt, cursor.Slug)
+	}
+
+	var page ListPage
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(insertedAtIndexBucket).Cursor()
+		var key []byte
+		next := cursor.Prev
+		switch {
+		case filter.Ascending && start == nil:
+			key, _ = cursor.First()
+			next = cursor.Next
+		case filter.Ascending:
+			key, _ = cursor.Seek(start)
+			if bytes.Equal(key, start) {
+				key, _ = cursor.Next()
+			}
+			next = cursor.Next
+		case start == nil:
+			key, _ = cursor.Last()
+		default:
+			// Seek goes to the first key after the cursor when it does not exist anymore, the previous one comes before it
+			key, _ = cursor.Seek(start)
+			if key == nil {
+				key, _ = cursor.Last()
+			} else {
+				key, _ = cursor.Prev()
+			}
+		}
+
+		// One more URL mapping is retrieved to know if there is a next page
+		for ; key != nil && len(page.URLMappings) <= filter.Limit; key, _ = next() {
+			url, err := boltGetURL(tx, string(key[8:]))
+			if err != nil {
+				return err
+			}
+			if url == nil || !matchesFilter(*url, filter) {
+				continue
+			}
+			page.URLMappings = append(page.URLMappings, *url)
+		}
+		return nil
+	})
+	if err != nil {
+		return ListPage{}, err
+	}
+
+	if len(page.URLMappings) > filter.Limit {
+		page.URLMappings = page.URLMappings[:filter.Limit]
+		lastURL := page.URLMappings[len(page.URLMappings)-1]
+		page.NextCursor = encodeCursor(listCursor{InsertedAt: lastURL.InsertedAt, Slug: lastURL.Slug})
+	}
+	return page, nil
+}
+
+// Purge implements the Store interface
+func (s *BoltStore) Purge(ctx context.Context, slug string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		url, err := boltGetURL(tx, slug)
+		if err != nil {
+			return err
+		}
+		tombstones := tx.Bucket(tombstonesBucket)
+		if url == nil && tombstones.Get([]byte(slug)) == nil {
+			return ErrNotFound
+		}
+		if url != nil {
+			err = boltDeleteURL(tx, *url)
+			if err != nil {
+				return err
+			}
+		}
+		return tombstones.Delete([]byte(slug))
+	})
+}
+
+// ScanSlugs implements the Store interface
+func (s *BoltStore) ScanSlugs(ctx context.Context, fn func(slug string)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{urlsBucket, tombstonesBucket} {
+			err := tx.Bucket(bucket).ForEach(func(key, _ []byte) error {
+				fn(string(key))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// boltSet stores an URL mapping within the transaction
+// An URL mapping only replaces one associated to the same URL and owner or expired, as the PSQL store does
+func boltSet(tx *bolt.Tx, shortURL domain.URLMapping, now time.Time) error {
+	existing, err := boltGetURL(tx, shortURL.Slug)
+	if err != nil {
+		return err
+	}
+	shortURL.Disabled = false
+	if existing != nil {
+		if !existing.IsExpired(now) {
+			if existing.OriginalURL != shortURL.OriginalURL || existing.Owner != shortURL.Owner {
+				return ErrSlugAlreadyExists
+			}
+			// Already stored, its insertion date and disabling are kept and its expiration replaced
+			shortURL.InsertedAt = existing.InsertedAt
+			shortURL.Disabled = existing.Disabled
+		}
+		err = boltDeleteURL(tx, *existing)
+		if err != nil {
+			return err
+		}
+	}
+
+	if shortURL.InsertedAt.IsZero() {
+		shortURL.InsertedAt = now
+	}
+	// Stored as the PSQL store would return it
+	shortURL.InsertedAt = shortURL.InsertedAt.UTC().Truncate(time.Microsecond)
+	if shortURL.ExpiresAt != nil {
+		expiresAt := shortURL.ExpiresAt.UTC().Truncate(time.Microsecond)
+		shortURL.ExpiresAt = &expiresAt
+	}
+	return boltPutURL(tx, shortURL)
+}
+
+// Set implements the Store interface
+func (s *BoltStore) Set(ctx context.Context, shortURL domain.URLMapping) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return boltSet(tx, shortURL, time.Now())
+	})
+}
+
+// SetBatch implements the Store interface
+// All the URL mappings are stored within a single transaction
+func (s *BoltStore) SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error) {
+	now := time.Now()
+	errs := make([]error, len(shortURLs))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for i, shortURL := range shortURLs {
+			err := boltSet(tx, shortURL, now)
+			switch err {
+			case nil:
+			case ErrSlugAlreadyExists:
+				errs[i] = err
+			default:
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// SetDisabled implements the Store interface
+func (s *BoltStore) SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+	var updatedURL domain.URLMapping
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		url, err := boltGetURL(tx, slug)
+		if err != nil {
+			return err
+		}
+		if url == nil || url.IsExpired(time.Now()) {
+			return ErrNotFound
+		}
+		url.Disabled = disabled
+		updatedURL = *url
+		return boltPutURL(tx, updatedURL)
+	})
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	return updatedURL, nil
+}
+
+// UpdateOriginalURL implements the Store interface
+func (s *BoltStore) UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+	var updatedURL domain.URLMapping
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		url, err := boltGetURL(tx, slug)
+		if err != nil {
+			return err
+		}
+		if url == nil || url.IsExpired(time.Now()) {
+			return ErrNotFound
+		}
+		err = boltDeleteURL(tx, *url)
+		if err != nil {
+			return err
+		}
+		url.OriginalURL = originalURL
+		updatedURL = *url
+		return boltPutURL(tx, updatedURL)
+	})
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	return updatedURL, nil
+}
+
+// Close closes the database file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// DB returns the database, so that the other bolt stores keep their data within the same file
+func (s *BoltStore) DB() *bolt.DB {
+	return s.db
+}
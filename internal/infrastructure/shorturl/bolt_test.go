@@ -0,0 +1,46 @@
+package shorturl
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStore(t *testing.T) {
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "urls.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	RunStoreTests(t, store)
+}
+
+func TestBoltStoreReopen(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "urls.db")
+	store, err := NewBoltStore(path)
+	require.NoError(t, err)
+	expiresAt := time.Now().Add(-time.Hour)
+	require.NoError(t, store.Set(ctx, domain.URLMapping{Slug: "kept", OriginalURL: "https://example.com"}))
+	require.NoError(t, store.Set(ctx, domain.URLMapping{Slug: "expired", OriginalURL: "https://example.com", ExpiresAt: &expiresAt}))
+	_, err = store.DeleteExpired(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// When
+	store, err = NewBoltStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	// Then
+	url, err := store.Get(ctx, "kept")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", url.OriginalURL)
+	_, err = store.Get(ctx, "expired")
+	assert.ErrorIs(t, err, ErrExpired)
+}
@@ -2,55 +2,350 @@ package shorturl
 
 import (
 	context "context"
-	"sync"
+	"errors"
+	"sync/atomic"
 	"time"
 	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/invalidation"
+
+	"github.com/golang/glog"
 )
 
-// CacheStore represents a inmemory cache store
+// CacheStoreStats represents the statistics of a cache store
+type CacheStoreStats struct {
+	URLs                  CacheStats `json:"urls"`
+	NotFound              CacheStats `json:"not_found"`               // The slugs remembered as unknown
+	BloomFilterRejections int64      `json:"bloom_filter_rejections"` // The lookups of slugs certainly unknown
+}
+
+// CacheStore represents a inmemory cache store in front of a persistent store
 type CacheStore struct {
-	persistentStore Store
-	cacheStore      sync.Map
+	persistentStore       Store
+	cache                 *lruCache
+	notFoundCache         *lruCache
+	bloomFilter           *bloomFilter // nil if disabled
+	bloomFilterLoaded     atomic.Bool  // The filter can't be trusted until it holds all the known slugs
+	bloomFilterRejections atomic.Int64
+	invalidationBus       invalidation.Bus // nil if the cache is not shared with other replicas
+	// The bloom filter may miss the slugs created by the other replicas while the invalidation bus fails
+	publishFailed    atomic.Bool
+	subscriptionLost atomic.Bool
 }
 
-// NewCacheStore creates a cache store
-func NewCacheStore(persistentStore Store) *CacheStore {
-	return &CacheStore{
+// NewCacheStore creates a cache store as configured, the changed slugs being published on the invalidation bus if any
+// The bloom filter, if enabled, is only used once loaded with LoadBloomFilter
+// As it only learns the slugs created by the other replicas through the invalidation bus, it must not be enabled without it
+func NewCacheStore(persistentStore Store, cacheConf config.CacheConfig, invalidationBus invalidation.Bus) *CacheStore {
+	store := &CacheStore{
 		persistentStore: persistentStore,
-		cacheStore:      sync.Map{},
+		invalidationBus: invalidationBus,
+		cache:           newLRUCache(cacheConf.Capacity, cacheConf.TTL),
+		notFoundCache:   newLRUCache(0, 0),
+	}
+	if cacheConf.NegativeTTL > 0 {
+		store.notFoundCache = newLRUCache(cacheConf.Capacity, cacheConf.NegativeTTL)
+	}
+	if cacheConf.BloomFilter.Enabled {
+		store.bloomFilter = newBloomFilter(cacheConf.BloomFilter.ExpectedSlugs, cacheConf.BloomFilter.FalsePositiveRate)
+	}
+	return store
+}
+
+// LoadBloomFilter fills the bloom filter with the slugs known by the persistent store, then starts relying on it
+func (s *CacheStore) LoadBloomFilter(ctx context.Context) error {
+	if s.bloomFilter == nil {
+		return nil
+	}
+	// The slugs stored meanwhile are added by Set as well, so none is missed
+	err := s.persistentStore.ScanSlugs(ctx, s.bloomFilter.add)
+	if err != nil {
+		return err
+	}
+	s.bloomFilterLoaded.Store(true)
+	return nil
+}
+
+// ListenInvalidations evicts the slugs changed by the other replicas until the context is done
+func (s *CacheStore) ListenInvalidations(ctx context.Context) {
+	if s.invalidationBus == nil {
+		return
+	}
+	s.invalidationBus.Subscribe(ctx, s.invalidate, func() {
+		// The slugs changed while disconnected are unknown, nothing cached can be trusted anymore
+		s.cache.clear()
+		s.notFoundCache.clear()
+		err := s.LoadBloomFilter(ctx)
+		if err != nil {
+			glog.Warningf("failed to reload the bloom filter of the slugs: %s", err)
+			return
+		}
+		s.subscriptionLost.Store(false)
+	}, func() {
+		s.subscriptionLost.Store(true)
+	})
+}
+
+// invalidate evicts the slugs changed by another replica
+func (s *CacheStore) invalidate(slugs []string) {
+	for _, slug := range slugs {
+		s.cache.remove(slug)
+		s.notFoundCache.remove(slug)
+		// The slug may have been created
+		if s.bloomFilter != nil {
+			s.bloomFilter.add(slug)
+		}
+	}
+}
+
+// publish informs the other replicas that the slugs have changed
+// A failure is only logged as the change is already stored: the other replicas keep the cached slugs until their TTL,
+// and their bloom filter misses the created ones until they subscribe again, so the bloom filter is not trusted meanwhile
+func (s *CacheStore) publish(ctx context.Context, slugs ...string) {
+	if s.invalidationBus == nil || len(slugs) == 0 {
+		return
 	}
+	err := s.invalidationBus.Publish(ctx, slugs)
+	s.publishFailed.Store(err != nil)
+	if err != nil {
+		glog.Warningf("failed to publish the invalidation of the slugs: %s", err)
+	}
+}
+
+// bloomFilterTrusted informs if the bloom filter is loaded and holds the slugs created by the other replicas as well
+// A failed publish likely means the bus is unreachable, so the slugs published by the other replicas are likely missed too
+func (s *CacheStore) bloomFilterTrusted() bool {
+	return s.bloomFilterLoaded.Load() && !s.publishFailed.Load() && !s.subscriptionLost.Load()
+}
+
+// Stats returns the statistics of the cache
+func (s *CacheStore) Stats() CacheStoreStats {
+	return CacheStoreStats{
+		URLs:                  s.cache.snapshot(),
+		NotFound:              s.notFoundCache.snapshot(),
+		BloomFilterRejections: s.bloomFilterRejections.Load(),
+	}
+}
+
+// isUnknown informs if a slug is known to be unknown by the persistent store, without asking it
+func (s *CacheStore) isUnknown(slug string, now time.Time) bool {
+	if _, exists := s.notFoundCache.get(slug, now); exists {
+		return true
+	}
+	if s.bloomFilterTrusted() && !s.bloomFilter.mayContain(slug) {
+		s.bloomFilterRejections.Add(1)
+		return true
+	}
+	return false
 }
 
 // Set implements Store interface
+// The slug is evicted rather than cached, as only the persistent store knows the full URL mapping (e.g. its inserted date)
 func (s *CacheStore) Set(ctx context.Context, shortURL domain.URLMapping) error {
+	// Added beforehand so that the slug is never rejected once stored
+	if s.bloomFilter != nil {
+		s.bloomFilter.add(shortURL.Slug)
+	}
+
 	err := s.persistentStore.Set(ctx, shortURL)
-	if err != nil {
+	if !isStored(err) {
 		return err
 	}
 
-	s.cacheStore.Store(shortURL.Slug, shortURL.OriginalURL)
-	return nil
+	s.cache.remove(shortURL.Slug)
+	s.notFoundCache.remove(shortURL.Slug)
+	s.publish(ctx, shortURL.Slug)
+	return err
+}
+
+// SetBatch implements Store interface
+func (s *CacheStore) SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error) {
+	if s.bloomFilter != nil {
+		for _, shortURL := range shortURLs {
+			s.bloomFilter.add(shortURL.Slug)
+		}
+	}
+
+	errs, err := s.persistentStore.SetBatch(ctx, shortURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	var storedSlugs []string
+	for i, shortURL := range shortURLs {
+		if isStored(errs[i]) {
+			s.cache.remove(shortURL.Slug)
+			s.notFoundCache.remove(shortURL.Slug)
+			storedSlugs = append(storedSlugs, shortURL.Slug)
+		}
+	}
+	s.publish(ctx, storedSlugs...)
+	return errs, nil
 }
 
 // Get implements Store interface
 func (s *CacheStore) Get(ctx context.Context, slug string) (domain.URLMapping, error) {
-	originalURL, exists := s.cacheStore.Load(slug)
+	now := time.Now()
+	urlMapping, exists := s.cache.get(slug, now)
 	if exists {
-		return domain.URLMapping{
-			Slug:        slug,
-			OriginalURL: originalURL.(string),
-		}, nil
+		return urlMapping, nil
+	}
+	if s.isUnknown(slug, now) {
+		return domain.URLMapping{}, ErrNotFound
+	}
+
+	// Taken beforehand so that a change made while reading the persistent store is not overwritten by the stale URL mapping
+	generation := s.cache.generation(slug)
+	notFoundGeneration := s.notFoundCache.generation(slug)
+
+	// Expired URL mapping are not cached, the persistent store decides what to answer
+	urlMapping, err := s.persistentStore.Get(ctx, slug)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			s.notFoundCache.addIfUnchanged(domain.URLMapping{Slug: slug}, now, notFoundGeneration)
+		}
+		return domain.URLMapping{}, err
+	}
+	s.cache.addIfUnchanged(urlMapping, now, generation)
+	return urlMapping, nil
+}
+
+// GetIncludingExpired implements Store interface
+// The expired URL mappings are not cached, so it is always answered by the persistent store
+func (s *CacheStore) GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error) {
+	return s.persistentStore.GetIncludingExpired(ctx, slug)
+}
+
+// GetBatch implements Store interface
+// The slugs missing from the cache are retrieved from the persistent store at once
+func (s *CacheStore) GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error) {
+	now := time.Now()
+	urlMappings := make([]domain.URLMapping, len(slugs))
+	errs := make([]error, len(slugs))
+	var missedSlugs []string
+	var missedIndexes []int
+	// Taken beforehand so that a change made while reading the persistent store is not overwritten by the stale URL mappings
+	var generations, notFoundGenerations []uint64
+	for i, slug := range slugs {
+		urlMapping, exists := s.cache.get(slug, now)
+		if exists {
+			urlMappings[i] = urlMapping
+			continue
+		}
+		if s.isUnknown(slug, now) {
+			errs[i] = ErrNotFound
+			continue
+		}
+		missedSlugs = append(missedSlugs, slug)
+		missedIndexes = append(missedIndexes, i)
+		generations = append(generations, s.cache.generation(slug))
+		notFoundGenerations = append(notFoundGenerations, s.notFoundCache.generation(slug))
+	}
+	if len(missedSlugs) == 0 {
+		return urlMappings, errs, nil
+	}
+
+	missedURLMappings, missedErrs, err := s.persistentStore.GetBatch(ctx, missedSlugs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j, i := range missedIndexes {
+		urlMappings[i] = missedURLMappings[j]
+		errs[i] = missedErrs[j]
+		if missedErrs[j] == nil {
+			s.cache.addIfUnchanged(missedURLMappings[j], now, generations[j])
+		} else if errors.Is(missedErrs[j], ErrNotFound) {
+			s.notFoundCache.addIfUnchanged(domain.URLMapping{Slug: missedSlugs[j]}, now, notFoundGenerations[j])
+		}
+	}
+	return urlMappings, errs, nil
+}
+
+// Delete implements Store interface
+func (s *CacheStore) Delete(ctx context.Context, slug string) error {
+	err := s.persistentStore.Delete(ctx, slug)
+
+	// Evicted whatever the persistent store answered, as the cache can't be trusted anymore
+	s.cache.remove(slug)
+	if isStored(err) {
+		s.publish(ctx, slug)
 	}
-	return s.persistentStore.Get(ctx, slug)
+	return err
 }
 
-func (s *CacheStore) DeleteExpired(ctx context.Context, timeToExpire time.Duration) ([]string, error) {
-	slugsDeleted, err := s.persistentStore.DeleteExpired(ctx, timeToExpire)
+// DeleteExpired implements Store interface
+func (s *CacheStore) DeleteExpired(ctx context.Context) ([]string, error) {
+	slugsDeleted, err := s.persistentStore.DeleteExpired(ctx)
 	if err != nil {
 		return nil, err
 	}
 	for _, slug := range slugsDeleted {
-		s.cacheStore.Delete(slug)
+		s.cache.remove(slug)
+		// Kept within the bloom filter as their tombstone must still be told apart from an unknown slug
+		if s.bloomFilter != nil {
+			s.bloomFilter.add(slug)
+		}
 	}
+	s.publish(ctx, slugsDeleted...)
 	return slugsDeleted, nil
 }
+
+// GetByOriginalURL implements Store interface
+func (s *CacheStore) GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error) {
+	return s.persistentStore.GetByOriginalURL(ctx, originalURL)
+}
+
+// Purge implements Store interface
+func (s *CacheStore) Purge(ctx context.Context, slug string) error {
+	err := s.persistentStore.Purge(ctx, slug)
+
+	// Evicted whatever the persistent store answered, as the cache can't be trusted anymore
+	s.cache.remove(slug)
+	if isStored(err) {
+		s.publish(ctx, slug)
+	}
+	return err
+}
+
+// ScanSlugs implements Store interface
+func (s *CacheStore) ScanSlugs(ctx context.Context, fn func(slug string)) error {
+	return s.persistentStore.ScanSlugs(ctx, fn)
+}
+
+// List implements Store interface
+// The listing is always retrieved from the persistent store as the cache only holds part of the URL mappings
+func (s *CacheStore) List(ctx context.Context, filter ListFilter) (ListPage, error) {
+	return s.persistentStore.List(ctx, filter)
+}
+
+// SetDisabled implements Store interface
+func (s *CacheStore) SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+	urlMapping, err := s.persistentStore.SetDisabled(ctx, slug, disabled)
+	if err != nil {
+		s.cache.remove(slug)
+		if isStored(err) {
+			s.publish(ctx, slug)
+		}
+		return domain.URLMapping{}, err
+	}
+
+	s.cache.add(urlMapping, time.Now())
+	s.publish(ctx, slug)
+	return urlMapping, nil
+}
+
+// UpdateOriginalURL implements Store interface
+func (s *CacheStore) UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+	urlMapping, err := s.persistentStore.UpdateOriginalURL(ctx, slug, originalURL)
+	if err != nil {
+		s.cache.remove(slug)
+		if isStored(err) {
+			s.publish(ctx, slug)
+		}
+		return domain.URLMapping{}, err
+	}
+
+	s.cache.add(urlMapping, time.Now())
+	s.publish(ctx, slug)
+	return urlMapping, nil
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/invalidation"
+	"urlShortenerService/internal/infrastructure/psql"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,14 +21,36 @@ func TestCacheStore(t *testing.T) {
 	defer os.Unsetenv("env")
 	conf, err := config.Load()
 	require.NoError(t, err)
-	persistentStore, err := NewPSQLStore(conf.Database)
+	pool, err := psql.NewPool(context.Background(), conf.Database)
 	require.NoError(t, err)
+	defer pool.Close()
+	_, err = psql.MigrateUp(context.Background(), pool, 0)
+	require.NoError(t, err)
+	persistentStore := NewPSQLStore(pool)
 
-	store := NewCacheStore(persistentStore)
+	cacheConf := conf.Cache
+	cacheConf.BloomFilter.Enabled = true
+	store := NewCacheStore(persistentStore, cacheConf, nil)
+	require.NoError(t, store.LoadBloomFilter(context.Background()))
 
 	RunStoreTests(t, store)
 }
 
+// testCacheConfig is the configuration of the cache stores under test, without negative cache nor bloom filter
+var testCacheConfig = config.CacheConfig{Capacity: 10, TTL: time.Minute}
+
+// cached retrieves the URL mapping of a slug held by the cache, without updating its statistics
+func cached(store *CacheStore, slug string) (domain.URLMapping, bool) {
+	store.cache.mu.Lock()
+	defer store.cache.mu.Unlock()
+
+	element, exists := store.cache.entries[slug]
+	if !exists {
+		return domain.URLMapping{}, false
+	}
+	return element.Value.(*lruEntry).urlMapping, true
+}
+
 func TestCacheSet(t *testing.T) {
 	shortURL := domain.URLMapping{
 		Slug:        "example",
@@ -36,31 +60,74 @@ func TestCacheSet(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
 		persitentMockStore.On("Set", mock.Anything, shortURL).Return(nil)
-		store := NewCacheStore(persitentMockStore)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(domain.URLMapping{Slug: shortURL.Slug, OriginalURL: "https://example.com/previous"}, time.Now())
 
 		// When
 		err := store.Set(context.Background(), shortURL)
 		require.NoError(t, err)
 
 		// Then
-		originalURL, exists := store.cacheStore.Load(shortURL.Slug)
-		assert.True(t, exists)
-		assert.Equal(t, shortURL.OriginalURL, originalURL.(string))
+		_, exists := cached(store, shortURL.Slug)
+		assert.False(t, exists)
 	})
 	t.Run("with persistent store failed", func(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
 		persitentMockStore.On("Set", mock.Anything, shortURL).Return(assert.AnError)
-		store := NewCacheStore(persitentMockStore)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
 
 		// When
 		err := store.Set(context.Background(), shortURL)
 
 		// Then
 		assert.ErrorIs(t, err, assert.AnError)
-		originalURL, exists := store.cacheStore.Load(shortURL.Slug)
+		urlMapping, exists := cached(store, shortURL.Slug)
+		assert.False(t, exists)
+		assert.Empty(t, urlMapping)
+	})
+}
+
+func TestCacheSetBatch(t *testing.T) {
+	shortURLs := []domain.URLMapping{
+		{Slug: "example", OriginalURL: "https://example.com"},
+		{Slug: "conflict", OriginalURL: "https://example.com/conflict"},
+	}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("SetBatch", mock.Anything, shortURLs).Return([]error{nil, ErrSlugAlreadyExists}, nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		conflictURL := domain.URLMapping{Slug: "conflict", OriginalURL: "https://example.com/other"}
+		store.cache.add(domain.URLMapping{Slug: "example", OriginalURL: "https://example.com/previous"}, time.Now())
+		store.cache.add(conflictURL, time.Now())
+
+		// When
+		errs, err := store.SetBatch(context.Background(), shortURLs)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []error{nil, ErrSlugAlreadyExists}, errs)
+		_, exists := cached(store, shortURLs[0].Slug)
+		assert.False(t, exists)
+		urlMapping, exists := cached(store, shortURLs[1].Slug)
+		assert.True(t, exists)
+		assert.Equal(t, conflictURL, urlMapping)
+	})
+	t.Run("with persistent store failed", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("SetBatch", mock.Anything, shortURLs).Return(nil, assert.AnError)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+
+		// When
+		errs, err := store.SetBatch(context.Background(), shortURLs)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, errs)
+		_, exists := cached(store, shortURLs[0].Slug)
 		assert.False(t, exists)
-		assert.Empty(t, originalURL)
 	})
 }
 
@@ -73,8 +140,8 @@ func TestCacheGet(t *testing.T) {
 	t.Run("found in cache", func(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
-		store := NewCacheStore(persitentMockStore)
-		store.cacheStore.Store(slug, shortURL.OriginalURL)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(shortURL, time.Now())
 
 		// When
 		urlMapping, err := store.Get(context.Background(), slug)
@@ -83,24 +150,45 @@ func TestCacheGet(t *testing.T) {
 		// Then
 		assert.Equal(t, shortURL, urlMapping)
 	})
+	t.Run("expired in cache", func(t *testing.T) {
+		// Given
+		expiresAt := time.Now().Add(-time.Minute)
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, ErrExpired)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: shortURL.OriginalURL, ExpiresAt: &expiresAt}, time.Now())
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrExpired)
+		assert.Empty(t, urlMapping)
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+	})
 	t.Run("found in persistent store", func(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
-		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil)
-		store := NewCacheStore(persitentMockStore)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil).Once()
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
 
 		// When
 		urlMapping, err := store.Get(context.Background(), slug)
 		require.NoError(t, err)
+		cachedURL, err := store.Get(context.Background(), slug)
+		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, shortURL, urlMapping)
+		assert.Equal(t, shortURL, cachedURL)
+		assert.Equal(t, CacheStats{Capacity: 10, Size: 1, Hits: 1, Misses: 1}, store.Stats().URLs)
 	})
 	t.Run("persistent store errored", func(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
 		persitentMockStore.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, assert.AnError)
-		store := NewCacheStore(persitentMockStore)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
 
 		// When
 		urlMapping, err := store.Get(context.Background(), slug)
@@ -109,51 +197,542 @@ func TestCacheGet(t *testing.T) {
 		assert.ErrorIs(t, err, assert.AnError)
 		assert.Empty(t, urlMapping)
 	})
+	t.Run("not found remembered", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, ErrNotFound).Once()
+		cacheConf := testCacheConfig
+		cacheConf.NegativeTTL = time.Minute
+		store := NewCacheStore(persitentMockStore, cacheConf, nil)
+
+		// When
+		_, err := store.Get(context.Background(), slug)
+		require.ErrorIs(t, err, ErrNotFound)
+		_, err = store.Get(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, int64(1), store.Stats().NotFound.Hits)
+	})
+	t.Run("not cached when changed while read from the persistent store", func(t *testing.T) {
+		// Given
+		var store *CacheStore
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil).Run(func(args mock.Arguments) {
+			// Retargeted by another replica once read
+			store.invalidate([]string{slug})
+		})
+		store = NewCacheStore(persitentMockStore, testCacheConfig, nil)
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+	})
+	t.Run("not found not remembered when set while read from the persistent store", func(t *testing.T) {
+		// Given
+		var store *CacheStore
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Set", mock.Anything, shortURL).Return(nil)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, ErrNotFound).Run(func(args mock.Arguments) {
+			// Created once read
+			require.NoError(t, store.Set(context.Background(), shortURL))
+		})
+		cacheConf := testCacheConfig
+		cacheConf.NegativeTTL = time.Minute
+		store = NewCacheStore(persitentMockStore, cacheConf, nil)
+
+		// When
+		_, err := store.Get(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 0, store.Stats().NotFound.Size)
+	})
+	t.Run("not found forgotten once set", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, ErrNotFound).Once()
+		persitentMockStore.On("Set", mock.Anything, shortURL).Return(nil)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil).Once()
+		cacheConf := testCacheConfig
+		cacheConf.NegativeTTL = time.Minute
+		store := NewCacheStore(persitentMockStore, cacheConf, nil)
+		_, err := store.Get(context.Background(), slug)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		// When
+		err = store.Set(context.Background(), shortURL)
+		require.NoError(t, err)
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+	})
+	t.Run("rejected by the bloom filter", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("ScanSlugs", mock.Anything, mock.Anything).Return(func(_ context.Context, fn func(string)) error {
+			fn("known")
+			return nil
+		})
+		cacheConf := testCacheConfig
+		cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 100, FalsePositiveRate: 0.01}
+		store := NewCacheStore(persitentMockStore, cacheConf, nil)
+		require.NoError(t, store.LoadBloomFilter(context.Background()))
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, urlMapping)
+		assert.Equal(t, int64(1), store.Stats().BloomFilterRejections)
+	})
+	t.Run("bloom filter not trusted once the publish failed", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("ScanSlugs", mock.Anything, mock.Anything).Return(nil)
+		persitentMockStore.On("Delete", mock.Anything, "other").Return(nil)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil)
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Publish", mock.Anything, []string{"other"}).Return(assert.AnError)
+		cacheConf := testCacheConfig
+		cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 100, FalsePositiveRate: 0.01}
+		store := NewCacheStore(persitentMockStore, cacheConf, mockBus)
+		require.NoError(t, store.LoadBloomFilter(context.Background()))
+		require.NoError(t, store.Delete(context.Background(), "other"))
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+		assert.Equal(t, int64(0), store.Stats().BloomFilterRejections)
+	})
+	t.Run("bloom filter not trusted while the subscription is lost", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("ScanSlugs", mock.Anything, mock.Anything).Return(nil)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil)
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(3).(func())()
+		})
+		cacheConf := testCacheConfig
+		cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 100, FalsePositiveRate: 0.01}
+		store := NewCacheStore(persitentMockStore, cacheConf, mockBus)
+		require.NoError(t, store.LoadBloomFilter(context.Background()))
+		store.ListenInvalidations(context.Background())
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+	})
+	t.Run("bloom filter not loaded yet", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil)
+		cacheConf := testCacheConfig
+		cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 100, FalsePositiveRate: 0.01}
+		store := NewCacheStore(persitentMockStore, cacheConf, nil)
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+	})
+}
+
+func TestCacheLoadBloomFilter(t *testing.T) {
+	cacheConf := testCacheConfig
+	cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 100, FalsePositiveRate: 0.01}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		shortURL := domain.URLMapping{Slug: "known", OriginalURL: "https://example.com"}
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("ScanSlugs", mock.Anything, mock.Anything).Return(func(_ context.Context, fn func(string)) error {
+			fn(shortURL.Slug)
+			return nil
+		})
+		persitentMockStore.On("Get", mock.Anything, shortURL.Slug).Return(shortURL, nil)
+		store := NewCacheStore(persitentMockStore, cacheConf, nil)
+
+		// When
+		err := store.LoadBloomFilter(context.Background())
+		require.NoError(t, err)
+
+		// Then
+		urlMapping, err := store.Get(context.Background(), shortURL.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+	})
+	t.Run("persistent store errored", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("ScanSlugs", mock.Anything, mock.Anything).Return(assert.AnError)
+		persitentMockStore.On("Get", mock.Anything, "unknown").Return(domain.URLMapping{}, ErrNotFound)
+		store := NewCacheStore(persitentMockStore, cacheConf, nil)
+
+		// When
+		err := store.LoadBloomFilter(context.Background())
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		_, err = store.Get(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrNotFound) // Answered by the persistent store
+	})
+}
+
+func TestCacheGetBatch(t *testing.T) {
+	cachedURL := domain.URLMapping{Slug: "cached", OriginalURL: "https://example.com/cached"}
+	missedURL := domain.URLMapping{Slug: "missed", OriginalURL: "https://example.com/missed"}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("GetBatch", mock.Anything, []string{"missed", "unknown"}).Return([]domain.URLMapping{missedURL, {}}, []error{nil, ErrNotFound}, nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(cachedURL, time.Now())
+
+		// When
+		urlMappings, errs, err := store.GetBatch(context.Background(), []string{"missed", "cached", "unknown"})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []domain.URLMapping{missedURL, cachedURL, {}}, urlMappings)
+		assert.Equal(t, []error{nil, nil, ErrNotFound}, errs)
+		_, exists := cached(store, missedURL.Slug)
+		assert.True(t, exists)
+		_, exists = cached(store, "unknown")
+		assert.False(t, exists)
+	})
+	t.Run("not cached when changed while read from the persistent store", func(t *testing.T) {
+		// Given
+		var store *CacheStore
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, "missed").Return(nil)
+		persitentMockStore.On("Set", mock.Anything, domain.URLMapping{Slug: "unknown", OriginalURL: "https://example.com/unknown"}).Return(nil)
+		persitentMockStore.On("GetBatch", mock.Anything, []string{"missed", "unknown"}).Return([]domain.URLMapping{missedURL, {}}, []error{nil, ErrNotFound}, nil).Run(func(args mock.Arguments) {
+			// Deleted and created once read
+			require.NoError(t, store.Delete(context.Background(), "missed"))
+			require.NoError(t, store.Set(context.Background(), domain.URLMapping{Slug: "unknown", OriginalURL: "https://example.com/unknown"}))
+		})
+		cacheConf := testCacheConfig
+		cacheConf.NegativeTTL = time.Minute
+		store = NewCacheStore(persitentMockStore, cacheConf, nil)
+
+		// When
+		_, errs, err := store.GetBatch(context.Background(), []string{"missed", "unknown"})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []error{nil, ErrNotFound}, errs)
+		_, exists := cached(store, missedURL.Slug)
+		assert.False(t, exists)
+		assert.Equal(t, 0, store.Stats().NotFound.Size)
+	})
+	t.Run("all in cache", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(cachedURL, time.Now())
+
+		// When
+		urlMappings, errs, err := store.GetBatch(context.Background(), []string{"cached"})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []domain.URLMapping{cachedURL}, urlMappings)
+		assert.Equal(t, []error{nil}, errs)
+	})
+	t.Run("with persistent store failed", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("GetBatch", mock.Anything, []string{"missed"}).Return(nil, nil, assert.AnError)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+
+		// When
+		urlMappings, errs, err := store.GetBatch(context.Background(), []string{"missed"})
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, urlMappings)
+		assert.Nil(t, errs)
+	})
 }
 
 func TestCacheDeleteExpired(t *testing.T) {
-	timeToExpire := 10 * time.Minute
 	slugsToDelete := []string{"2zv8a2Im", "1eJSWjFM", "UsIJeS1D", "K11q8dTj", "Sd7k2eDU"}
 	t.Run("nominal", func(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
-		persitentMockStore.On("DeleteExpired", mock.Anything, timeToExpire).Return(slugsToDelete, nil)
-		store := NewCacheStore(persitentMockStore)
+		persitentMockStore.On("DeleteExpired", mock.Anything).Return(slugsToDelete, nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
 		for i, slug := range slugsToDelete {
-			store.cacheStore.Store(slug, fmt.Sprintf("https://example.com/%d", i))
+			store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: fmt.Sprintf("https://example.com/%d", i)}, time.Now())
 		}
 
 		// When
-		slugsDeleted, err := store.DeleteExpired(context.Background(), timeToExpire)
+		slugsDeleted, err := store.DeleteExpired(context.Background())
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, slugsToDelete, slugsDeleted)
 		for _, slugDeleted := range slugsDeleted {
-			originalURL, exists := store.cacheStore.Load(slugDeleted)
+			urlMapping, exists := cached(store, slugDeleted)
 			assert.False(t, exists)
-			assert.Empty(t, originalURL)
+			assert.Empty(t, urlMapping)
 		}
 	})
 	t.Run("persistent store errored", func(t *testing.T) {
 		// Given
 		persitentMockStore := NewMock(t)
-		persitentMockStore.On("DeleteExpired", mock.Anything, timeToExpire).Return([]string{}, assert.AnError)
-		store := NewCacheStore(persitentMockStore)
+		persitentMockStore.On("DeleteExpired", mock.Anything).Return([]string{}, assert.AnError)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
 		for i, slug := range slugsToDelete {
-			store.cacheStore.Store(slug, fmt.Sprintf("https://example.com/%d", i))
+			store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: fmt.Sprintf("https://example.com/%d", i)}, time.Now())
 		}
 
 		// When
-		slugsDeleted, err := store.DeleteExpired(context.Background(), timeToExpire)
+		slugsDeleted, err := store.DeleteExpired(context.Background())
 
 		// Then
 		assert.ErrorIs(t, err, assert.AnError)
 		assert.Empty(t, slugsDeleted)
 		for _, slugDeleted := range slugsDeleted {
-			originalURL, exists := store.cacheStore.Load(slugDeleted)
+			urlMapping, exists := cached(store, slugDeleted)
 			assert.True(t, exists)
-			assert.NotEmpty(t, originalURL)
+			assert.NotEmpty(t, urlMapping)
 		}
 	})
 }
+
+func TestCacheDelete(t *testing.T) {
+	slug := "jV6gHv0o"
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: "https://example.com"}, time.Now())
+
+		// When
+		err := store.Delete(context.Background(), slug)
+		require.NoError(t, err)
+
+		// Then
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+	})
+	t.Run("persistent store errored", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(assert.AnError)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: "https://example.com"}, time.Now())
+
+		// When
+		err := store.Delete(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+	})
+}
+
+func TestCacheUpdateOriginalURL(t *testing.T) {
+	slug := "jV6gHv0o"
+	updatedURL := domain.URLMapping{
+		Slug:        slug,
+		OriginalURL: "https://example.com/updated",
+	}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("UpdateOriginalURL", mock.Anything, slug, updatedURL.OriginalURL).Return(updatedURL, nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: "https://example.com"}, time.Now())
+
+		// When
+		urlMapping, err := store.UpdateOriginalURL(context.Background(), slug, updatedURL.OriginalURL)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, updatedURL, urlMapping)
+		cachedURL, exists := cached(store, slug)
+		assert.True(t, exists)
+		assert.Equal(t, updatedURL, cachedURL)
+	})
+	t.Run("persistent store errored", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("UpdateOriginalURL", mock.Anything, slug, updatedURL.OriginalURL).Return(domain.URLMapping{}, assert.AnError)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+		store.cache.add(domain.URLMapping{Slug: slug, OriginalURL: "https://example.com"}, time.Now())
+
+		// When
+		urlMapping, err := store.UpdateOriginalURL(context.Background(), slug, updatedURL.OriginalURL)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, urlMapping)
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+	})
+}
+
+func TestCacheList(t *testing.T) {
+	// Given
+	filter := ListFilter{Domain: "example.com", Limit: 10}
+	page := ListPage{URLMappings: []domain.URLMapping{{Slug: "jV6gHv0o", OriginalURL: "https://example.com"}}}
+	persitentMockStore := NewMock(t)
+	persitentMockStore.On("List", mock.Anything, filter).Return(page, nil)
+	store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+
+	// When
+	listedPage, err := store.List(context.Background(), filter)
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, page, listedPage)
+}
+
+func TestCacheGetByOriginalURL(t *testing.T) {
+	// Given
+	urlMappings := []domain.URLMapping{{Slug: "jV6gHv0o", OriginalURL: "https://example.com"}}
+	persitentMockStore := NewMock(t)
+	persitentMockStore.On("GetByOriginalURL", mock.Anything, "https://example.com").Return(urlMappings, nil)
+	store := NewCacheStore(persitentMockStore, testCacheConfig, nil)
+
+	// When
+	retrievedURLMappings, err := store.GetByOriginalURL(context.Background(), "https://example.com")
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, urlMappings, retrievedURLMappings)
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	slug := "jV6gHv0o"
+	shortURL := domain.URLMapping{Slug: slug, OriginalURL: "https://example.com"}
+	t.Run("published on change", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Set", mock.Anything, shortURL).Return(nil)
+		persitentMockStore.On("DeleteExpired", mock.Anything).Return([]string{"expired-1", "expired-2"}, nil)
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Publish", mock.Anything, []string{slug}).Return(nil)
+		mockBus.On("Publish", mock.Anything, []string{"expired-1", "expired-2"}).Return(nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, mockBus)
+
+		// When
+		err := store.Set(context.Background(), shortURL)
+		require.NoError(t, err)
+		_, err = store.DeleteExpired(context.Background())
+		require.NoError(t, err)
+
+		// Then
+		mockBus.AssertNumberOfCalls(t, "Publish", 2)
+	})
+	t.Run("published when stored but not evicted from the shared cache", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Set", mock.Anything, shortURL).Return(ErrNotEvicted)
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Publish", mock.Anything, []string{slug}).Return(nil)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, mockBus)
+		store.cache.add(shortURL, time.Now())
+
+		// When
+		err := store.Set(context.Background(), shortURL)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotEvicted)
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+		mockBus.AssertNumberOfCalls(t, "Publish", 1)
+	})
+	t.Run("publish failed", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(nil)
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Publish", mock.Anything, []string{slug}).Return(assert.AnError)
+		store := NewCacheStore(persitentMockStore, testCacheConfig, mockBus)
+
+		// When
+		err := store.Delete(context.Background(), slug)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("evicted when changed by another replica", func(t *testing.T) {
+		// Given
+		cacheConf := testCacheConfig
+		cacheConf.NegativeTTL = time.Minute
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(func([]string))([]string{slug, "unknown"})
+		})
+		store := NewCacheStore(NewMock(t), cacheConf, mockBus)
+		store.cache.add(shortURL, time.Now())
+		store.notFoundCache.add(domain.URLMapping{Slug: "unknown"}, time.Now())
+
+		// When
+		store.ListenInvalidations(context.Background())
+
+		// Then
+		_, exists := cached(store, slug)
+		assert.False(t, exists)
+		assert.Equal(t, 0, store.Stats().NotFound.Size)
+	})
+	t.Run("purged when subscribed again", func(t *testing.T) {
+		// Given
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(2).(func())()
+		})
+		store := NewCacheStore(NewMock(t), testCacheConfig, mockBus)
+		store.cache.add(shortURL, time.Now())
+		store.cache.add(domain.URLMapping{Slug: "other", OriginalURL: "https://example.com/other"}, time.Now())
+
+		// When
+		store.ListenInvalidations(context.Background())
+
+		// Then
+		assert.Equal(t, 0, store.Stats().URLs.Size)
+	})
+	t.Run("bloom filter trusted again once subscribed again", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("ScanSlugs", mock.Anything, mock.Anything).Return(nil)
+		mockBus := invalidation.NewMockBus(t)
+		mockBus.On("Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(3).(func())()
+			args.Get(2).(func())()
+		})
+		cacheConf := testCacheConfig
+		cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 100, FalsePositiveRate: 0.01}
+		store := NewCacheStore(persitentMockStore, cacheConf, mockBus)
+
+		// When
+		store.ListenInvalidations(context.Background())
+
+		// Then
+		_, err := store.Get(context.Background(), slug)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, int64(1), store.Stats().BloomFilterRejections)
+	})
+}
@@ -0,0 +1,48 @@
+package shorturl
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCursor is the error when a listing cursor can't be decoded
+	ErrInvalidCursor error = errors.New("cursor is invalid")
+)
+
+// cursorSeparator separates the inserted date from the slug within a cursor
+const cursorSeparator string = "|"
+
+// listCursor represents the position of the last URL mapping of a listing page
+// As several URL mappings can share the same inserted date, the slug is used as a tie breaker
+type listCursor struct {
+	InsertedAt time.Time
+	Slug       string
+}
+
+// encodeCursor encodes a cursor into an opaque string
+func encodeCursor(cursor listCursor) string {
+	rawCursor := cursor.InsertedAt.UTC().Format(time.RFC3339Nano) + cursorSeparator + cursor.Slug
+	return base64.RawURLEncoding.EncodeToString([]byte(rawCursor))
+}
+
+// decodeCursor decodes an opaque string into a cursor
+func decodeCursor(encodedCursor string) (listCursor, error) {
+	rawCursor, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return listCursor{}, ErrInvalidCursor
+	}
+
+	insertedAtStr, slug, found := strings.Cut(string(rawCursor), cursorSeparator)
+	if !found || slug == "" {
+		return listCursor{}, ErrInvalidCursor
+	}
+	insertedAt, err := time.Parse(time.RFC3339Nano, insertedAtStr)
+	if err != nil {
+		return listCursor{}, ErrInvalidCursor
+	}
+
+	return listCursor{InsertedAt: insertedAt, Slug: slug}, nil
+}
@@ -0,0 +1,43 @@
+package shorturl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("encoded cursor can be decoded", func(t *testing.T) {
+		// Given
+		cursor := listCursor{InsertedAt: time.Now().UTC(), Slug: "spring-sale"}
+
+		// When
+		decodedCursor, err := decodeCursor(encodeCursor(cursor))
+		require.NoError(t, err)
+
+		// Then
+		assert.True(t, cursor.InsertedAt.Equal(decodedCursor.InsertedAt))
+		assert.Equal(t, cursor.Slug, decodedCursor.Slug)
+	})
+	t.Run("invalid cursor", func(t *testing.T) {
+		scenarios := []struct {
+			Name          string
+			EncodedCursor string
+		}{
+			{Name: "not base64", EncodedCursor: "not a base64 cursor!"},
+			{Name: "missing separator", EncodedCursor: "MjAyNC0xMC0wMVQxMjowMDowMFo"},
+			{Name: "invalid date", EncodedCursor: "bm90LWEtZGF0ZXxzbHVn"},
+		}
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				// When
+				_, err := decodeCursor(scenario.EncodedCursor)
+
+				// Then
+				assert.ErrorIs(t, err, ErrInvalidCursor)
+			})
+		}
+	})
+}
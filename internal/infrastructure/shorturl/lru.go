@@ -0,0 +1,168 @@
+package shorturl
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+	"time"
+	"urlShortenerService/domain"
+)
+
+// CacheStats represents the statistics of the cache of URL mappings
+type CacheStats struct {
+	Capacity  int   `json:"capacity"`
+	Size      int   `json:"size"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"` // The URL mappings dropped to make room or because their time to live has passed
+}
+
+// lruGenerationStripes is the number of generation counters the slugs are spread over, bounding their memory whatever the number of slugs
+const lruGenerationStripes = 1024
+
+// lruEntry represents an URL mapping held by the cache
+type lruEntry struct {
+	urlMapping domain.URLMapping
+	cachedAt   time.Time
+}
+
+// lruCache represents a cache of URL mappings bounded in size, the least recently used one being dropped first, and in time
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration // 0 means the URL mappings are kept until they are dropped to make room
+	entries  map[string]*list.Element
+	order    *list.List // From the most to the least recently used
+	stats    CacheStats
+	// Bumped on each change of a slug of the stripe, so that an URL mapping read before the change is not cached afterwards
+	generations [lruGenerationStripes]uint64
+}
+
+// newLRUCache creates a cache of at most capacity URL mappings, each kept up to ttl
+func newLRUCache(capacity int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+		stats:    CacheStats{Capacity: capacity},
+	}
+}
+
+// get retrieves the URL mapping of a slug, if cached and neither stale nor expired at the given time
+func (c *lruCache) get(slug string, now time.Time) (domain.URLMapping, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[slug]
+	if !exists {
+		c.stats.Misses++
+		return domain.URLMapping{}, false
+	}
+	entry := element.Value.(*lruEntry)
+	if (c.ttl > 0 && now.Sub(entry.cachedAt) >= c.ttl) || entry.urlMapping.IsExpired(now) {
+		c.removeElement(element)
+		c.stats.Evictions++
+		c.stats.Misses++
+		return domain.URLMapping{}, false
+	}
+
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+	return entry.urlMapping, true
+}
+
+// stripeOf returns the index of the generation counter of a slug
+func stripeOf(slug string) int {
+	h := fnv.New32a()
+	h.Write([]byte(slug))
+	return int(h.Sum32() % lruGenerationStripes)
+}
+
+// generation returns the current generation of a slug, to be given to addIfUnchanged once its URL mapping is read
+func (c *lruCache) generation(slug string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generations[stripeOf(slug)]
+}
+
+// add caches the URL mapping changed at the given time, dropping the least recently used one if the cache is full
+// The URL mappings read beforehand are not cached anymore by addIfUnchanged
+func (c *lruCache) add(urlMapping domain.URLMapping, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[stripeOf(urlMapping.Slug)]++
+	c.addLocked(urlMapping, now)
+}
+
+// addIfUnchanged caches the URL mapping read at the given time, unless its slug has changed since the given generation
+// It informs if the URL mapping has been cached
+func (c *lruCache) addIfUnchanged(urlMapping domain.URLMapping, now time.Time, generation uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[stripeOf(urlMapping.Slug)] != generation {
+		return false
+	}
+	return c.addLocked(urlMapping, now)
+}
+
+// addLocked caches the URL mapping, the lock must be held
+func (c *lruCache) addLocked(urlMapping domain.URLMapping, now time.Time) bool {
+	if c.capacity <= 0 {
+		return false
+	}
+	if element, exists := c.entries[urlMapping.Slug]; exists {
+		element.Value = &lruEntry{urlMapping: urlMapping, cachedAt: now}
+		c.order.MoveToFront(element)
+		return true
+	}
+
+	c.entries[urlMapping.Slug] = c.order.PushFront(&lruEntry{urlMapping: urlMapping, cachedAt: now})
+	if c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+	return true
+}
+
+// remove drops the URL mapping of a slug, if cached, as it has changed
+func (c *lruCache) remove(slug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[stripeOf(slug)]++
+	if element, exists := c.entries[slug]; exists {
+		c.removeElement(element)
+	}
+}
+
+// clear drops all the URL mappings, as any of them may have changed
+func (c *lruCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.generations {
+		c.generations[i]++
+	}
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// removeElement drops an element of the cache, the lock must be held
+func (c *lruCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).urlMapping.Slug)
+}
+
+// snapshot returns the current statistics of the cache
+func (c *lruCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.order.Len()
+	return stats
+}
@@ -0,0 +1,141 @@
+package shorturl
+
+import (
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	first := domain.URLMapping{Slug: "first", OriginalURL: "https://example.com/first", InsertedAt: now}
+	second := domain.URLMapping{Slug: "second", OriginalURL: "https://example.com/second", InsertedAt: now}
+	third := domain.URLMapping{Slug: "third", OriginalURL: "https://example.com/third", InsertedAt: now}
+	t.Run("evicts the least recently used", func(t *testing.T) {
+		// Given
+		cache := newLRUCache(2, time.Minute)
+		cache.add(first, now)
+		cache.add(second, now)
+		_, _ = cache.get(first.Slug, now)
+
+		// When
+		cache.add(third, now)
+
+		// Then
+		urlMapping, exists := cache.get(first.Slug, now)
+		assert.True(t, exists)
+		assert.Equal(t, first, urlMapping)
+		_, exists = cache.get(second.Slug, now)
+		assert.False(t, exists)
+		_, exists = cache.get(third.Slug, now)
+		assert.True(t, exists)
+		assert.Equal(t, CacheStats{Capacity: 2, Size: 2, Hits: 3, Misses: 1, Evictions: 1}, cache.snapshot())
+	})
+	t.Run("evicts after the time to live", func(t *testing.T) {
+		// Given
+		cache := newLRUCache(2, time.Minute)
+		cache.add(first, now)
+
+		// When
+		_, exists := cache.get(first.Slug, now.Add(time.Minute))
+
+		// Then
+		assert.False(t, exists)
+		assert.Equal(t, CacheStats{Capacity: 2, Size: 0, Misses: 1, Evictions: 1}, cache.snapshot())
+	})
+	t.Run("evicts the expired URL mappings", func(t *testing.T) {
+		// Given
+		expiresAt := now.Add(time.Second)
+		cache := newLRUCache(2, 0)
+		cache.add(domain.URLMapping{Slug: first.Slug, OriginalURL: first.OriginalURL, ExpiresAt: &expiresAt}, now)
+
+		// When
+		_, exists := cache.get(first.Slug, now.Add(time.Second))
+
+		// Then
+		assert.False(t, exists)
+		assert.Equal(t, 0, cache.snapshot().Size)
+	})
+	t.Run("replaces the URL mapping of a cached slug", func(t *testing.T) {
+		// Given
+		updated := domain.URLMapping{Slug: first.Slug, OriginalURL: "https://example.com/updated", InsertedAt: now}
+		cache := newLRUCache(2, time.Minute)
+		cache.add(first, now)
+
+		// When
+		cache.add(updated, now)
+
+		// Then
+		urlMapping, exists := cache.get(first.Slug, now)
+		assert.True(t, exists)
+		assert.Equal(t, updated, urlMapping)
+		assert.Equal(t, 1, cache.snapshot().Size)
+	})
+	t.Run("without capacity", func(t *testing.T) {
+		// Given
+		cache := newLRUCache(0, time.Minute)
+
+		// When
+		cache.add(first, now)
+
+		// Then
+		_, exists := cache.get(first.Slug, now)
+		assert.False(t, exists)
+	})
+	t.Run("remove", func(t *testing.T) {
+		// Given
+		cache := newLRUCache(2, time.Minute)
+		cache.add(first, now)
+
+		// When
+		cache.remove(first.Slug)
+		cache.remove(second.Slug)
+
+		// Then
+		_, exists := cache.get(first.Slug, now)
+		assert.False(t, exists)
+		assert.Equal(t, CacheStats{Capacity: 2, Size: 0, Misses: 1}, cache.snapshot())
+	})
+	t.Run("add if unchanged", func(t *testing.T) {
+		// Given
+		cache := newLRUCache(2, time.Minute)
+		generation := cache.generation(first.Slug)
+
+		// When
+		added := cache.addIfUnchanged(first, now, generation)
+
+		// Then
+		assert.True(t, added)
+		_, exists := cache.get(first.Slug, now)
+		assert.True(t, exists)
+	})
+	t.Run("not added once changed", func(t *testing.T) {
+		updated := domain.URLMapping{Slug: first.Slug, OriginalURL: "https://example.com/updated", InsertedAt: now}
+		scenarios := []struct {
+			Name   string
+			Change func(cache *lruCache)
+		}{
+			{Name: "removed", Change: func(cache *lruCache) { cache.remove(first.Slug) }},
+			{Name: "updated", Change: func(cache *lruCache) { cache.add(updated, now) }},
+			{Name: "cleared", Change: func(cache *lruCache) { cache.clear() }},
+		}
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				// Given
+				cache := newLRUCache(2, time.Minute)
+				generation := cache.generation(first.Slug)
+				scenario.Change(cache)
+
+				// When
+				added := cache.addIfUnchanged(first, now, generation)
+
+				// Then
+				assert.False(t, added)
+				urlMapping, _ := cache.get(first.Slug, now)
+				assert.NotEqual(t, first, urlMapping)
+			})
+		}
+	})
+}
@@ -0,0 +1,300 @@
+package shorturl
+
+import (
+	context "context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"urlShortenerService/domain"
+)
+
+// MemoryStore represents an in memory store, meant for development as nothing is persisted
+type MemoryStore struct {
+	mutex      sync.RWMutex
+	urls       map[string]domain.URLMapping
+	tombstones map[string]struct{}
+}
+
+// NewMemoryStore creates an empty in memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		urls:       map[string]domain.URLMapping{},
+		tombstones: map[string]struct{}{},
+	}
+}
+
+// Delete implements the Store interface
+func (s *MemoryStore) Delete(ctx context.Context, slug string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.urls[slug]; !exists {
+		return ErrNotFound
+	}
+	delete(s.urls, slug)
+	return nil
+}
+
+// DeleteExpired implements the Store interface
+func (s *MemoryStore) DeleteExpired(ctx context.Context) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	var deletedSlugs []string
+	for slug, url := range s.urls {
+		if url.IsExpired(now) {
+			delete(s.urls, slug)
+			s.tombstones[slug] = struct{}{}
+			deletedSlugs = append(deletedSlugs, slug)
+		}
+	}
+	return deletedSlugs, nil
+}
+
+// get retrieves the URL of a slug, the lock must be held
+func (s *MemoryStore) get(slug string, now time.Time) (domain.URLMapping, error) {
+	url, err := s.getIncludingExpired(slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	// The URL might be expired but not deleted yet
+	if url.IsExpired(now) {
+		return domain.URLMapping{}, ErrExpired
+	}
+	return url, nil
+}
+
+// getIncludingExpired retrieves the URL mapping of a slug as long as it is not deleted, the mutex being held by the caller
+func (s *MemoryStore) getIncludingExpired(slug string) (domain.URLMapping, error) {
+	url, exists := s.urls[slug]
+	if !exists {
+		if _, exists := s.tombstones[slug]; exists {
+			return domain.URLMapping{}, ErrExpired
+		}
+		return domain.URLMapping{}, ErrNotFound
+	}
+	return url, nil
+}
+
+// Get implements the Store interface
+func (s *MemoryStore) Get(ctx context.Context, slug string) (domain.URLMapping, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.get(slug, time.Now())
+}
+
+// GetIncludingExpired implements the Store interface
+func (s *MemoryStore) GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.getIncludingExpired(slug)
+}
+
+// GetBatch implements the Store interface
+func (s *MemoryStore) GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	now := time.Now()
+	urls := make([]domain.URLMapping, len(slugs))
+	errs := make([]error, len(slugs))
+	for i, slug := range slugs {
+		urls[i], errs[i] = s.get(slug, now)
+	}
+	return urls, errs, nil
+}
+
+// GetByOriginalURL implements the Store interface
+func (s *MemoryStore) GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var urls []domain.URLMapping
+	for _, url := range s.urls {
+		if url.OriginalURL == originalURL {
+			urls = append(urls, url)
+		}
+	}
+	sortURLMappings(urls, true)
+	return urls, nil
+}
+
+// sortURLMappings sorts URL mappings by inserted date then slug
+func sortURLMappings(urls []domain.URLMapping, ascending bool) {
+	sort.Slice(urls, func(i, j int) bool {
+		if ascending {
+			return isBefore(urls[i], urls[j].InsertedAt, urls[j].Slug)
+		}
+		return isBefore(urls[j], urls[i].InsertedAt, urls[i].Slug)
+	})
+}
+
+// isBefore informs if an URL mapping comes before the given inserted date and slug, ascending
+func isBefore(url domain.URLMapping, insertedAt time.Time, slug string) bool {
+	if !url.InsertedAt.Equal(insertedAt) {
+		return url.InsertedAt.Before(insertedAt)
+	}
+	return url.Slug < slug
+}
+
+// hostOf returns the host of a sanitized URL, which always has a path
+func hostOf(url string) string {
+	_, afterScheme, _ := strings.Cut(url, "://")
+	host, _, _ := strings.Cut(afterScheme, "/")
+	return host
+}
+
+// matchesFilter informs if an URL mapping matches the filters of a listing, the cursor aside
+func matchesFilter(url domain.URLMapping, filter ListFilter) bool {
+	switch {
+	case filter.Domain != "" && hostOf(url.OriginalURL) != filter.Domain,
+		filter.Contains != "" && !strings.Contains(url.OriginalURL, filter.Contains),
+		filter.InsertedFrom != nil && url.InsertedAt.Before(*filter.InsertedFrom),
+		filter.InsertedTo != nil && !url.InsertedAt.Before(*filter.InsertedTo):
+		return false
+	}
+	return true
+}
+
+// List implements the Store interface
+func (s *MemoryStore) List(ctx context.Context, filter ListFilter) (ListPage, error) {
+	var cursor *listCursor
+	if filter.Cursor != "" {
+		decodedCursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return ListPage{}, err
+		}
+		cursor = &decodedCursor
+	}
+
+	s.mutex.RLock()
+	var urls []domain.URLMapping
+	for _, url := range s.urls {
+		switch {
+		case !matchesFilter(url, filter),
+			cursor != nil && filter.Ascending && !isBefore(domain.URLMapping{InsertedAt: cursor.InsertedAt, Slug: cursor.Slug}, url.InsertedAt, url.Slug),
+			cursor != nil && !filter.Ascending && !isBefore(url, cursor.InsertedAt, cursor.Slug):
+			continue
+		}
+		urls = append(urls, url)
+	}
+	s.mutex.RUnlock()
+
+	sortURLMappings(urls, filter.Ascending)
+	page := ListPage{URLMappings: urls}
+	if len(urls) > filter.Limit {
+		page.URLMappings = urls[:filter.Limit]
+		lastURL := page.URLMappings[len(page.URLMappings)-1]
+		page.NextCursor = encodeCursor(listCursor{InsertedAt: lastURL.InsertedAt, Slug: lastURL.Slug})
+	}
+	return page, nil
+}
+
+// Purge implements the Store interface
+func (s *MemoryStore) Purge(ctx context.Context, slug string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, urlExists := s.urls[slug]
+	_, tombstoneExists := s.tombstones[slug]
+	if !urlExists && !tombstoneExists {
+		return ErrNotFound
+	}
+	delete(s.urls, slug)
+	delete(s.tombstones, slug)
+	return nil
+}
+
+// ScanSlugs implements the Store interface
+func (s *MemoryStore) ScanSlugs(ctx context.Context, fn func(slug string)) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for slug := range s.urls {
+		fn(slug)
+	}
+	for slug := range s.tombstones {
+		fn(slug)
+	}
+	return nil
+}
+
+// set stores an URL mapping, the lock must be held
+// An URL mapping only replaces one associated to the same URL and owner or expired, as the PSQL store does
+func (s *MemoryStore) set(shortURL domain.URLMapping, now time.Time) error {
+	existing, exists := s.urls[shortURL.Slug]
+	shortURL.Disabled = false
+	if exists && !existing.IsExpired(now) {
+		if existing.OriginalURL != shortURL.OriginalURL || existing.Owner != shortURL.Owner {
+			return ErrSlugAlreadyExists
+		}
+		// Already stored, its insertion date and disabling are kept and its expiration replaced
+		shortURL.InsertedAt = existing.InsertedAt
+		shortURL.Disabled = existing.Disabled
+	}
+
+	if shortURL.InsertedAt.IsZero() {
+		shortURL.InsertedAt = now
+	}
+	// Stored as the PSQL store would return it
+	shortURL.InsertedAt = shortURL.InsertedAt.UTC().Truncate(time.Microsecond)
+	if shortURL.ExpiresAt != nil {
+		expiresAt := shortURL.ExpiresAt.UTC().Truncate(time.Microsecond)
+		shortURL.ExpiresAt = &expiresAt
+	}
+	s.urls[shortURL.Slug] = shortURL
+	return nil
+}
+
+// Set implements the Store interface
+func (s *MemoryStore) Set(ctx context.Context, shortURL domain.URLMapping) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.set(shortURL, time.Now())
+}
+
+// SetBatch implements the Store interface
+func (s *MemoryStore) SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	errs := make([]error, len(shortURLs))
+	for i, shortURL := range shortURLs {
+		errs[i] = s.set(shortURL, now)
+	}
+	return errs, nil
+}
+
+// SetDisabled implements the Store interface
+func (s *MemoryStore) SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	url, exists := s.urls[slug]
+	if !exists || url.IsExpired(time.Now()) {
+		return domain.URLMapping{}, ErrNotFound
+	}
+	url.Disabled = disabled
+	s.urls[slug] = url
+	return url, nil
+}
+
+// UpdateOriginalURL implements the Store interface
+func (s *MemoryStore) UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	url, exists := s.urls[slug]
+	if !exists || url.IsExpired(time.Now()) {
+		return domain.URLMapping{}, ErrNotFound
+	}
+	url.OriginalURL = originalURL
+	s.urls[slug] = url
+	return url, nil
+}
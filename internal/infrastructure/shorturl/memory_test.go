@@ -0,0 +1,33 @@
+package shorturl
+
+import (
+	"context"
+	"testing"
+	"time"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	RunStoreTests(t, store)
+}
+
+// The cache stores are tested in front of an in memory store as well, so that they are tested without database
+func TestCacheStoreInMemory(t *testing.T) {
+	cacheConf := testCacheConfig
+	cacheConf.NegativeTTL = time.Minute
+	cacheConf.BloomFilter = config.BloomFilterConfig{Enabled: true, ExpectedSlugs: 1000, FalsePositiveRate: 0.01}
+	store := NewCacheStore(NewMemoryStore(), cacheConf, nil)
+	require.NoError(t, store.LoadBloomFilter(context.Background()))
+
+	RunStoreTests(t, store)
+}
+
+func TestRedisCacheStoreInMemory(t *testing.T) {
+	store, _ := newTestRedisCacheStore(t, NewMemoryStore())
+
+	RunStoreTests(t, store)
+}
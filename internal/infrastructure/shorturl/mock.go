@@ -7,8 +7,6 @@ import (
 	"urlShortenerService/domain"
 
 	mock "github.com/stretchr/testify/mock"
-
-	time "time"
 )
 
 // MockStore is an autogenerated mock type for the Store type
@@ -31,23 +29,37 @@ func NewMock(t interface {
 	return mock
 }
 
-// DeleteExpired provides a mock function with given fields: ctx, duration
-func (_m *MockStore) DeleteExpired(ctx context.Context, timeToExpire time.Duration) ([]string, error) {
-	ret := _m.Called(ctx, timeToExpire)
+// Delete provides a mock function with given fields: ctx, slug
+func (_m *MockStore) Delete(ctx context.Context, slug string) error {
+	ret := _m.Called(ctx, slug)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx
+func (_m *MockStore) DeleteExpired(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) ([]string, error)); ok {
-		return rf(ctx, timeToExpire)
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) []string); ok {
-		r0 = rf(ctx, timeToExpire)
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).([]string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
-		r1 = rf(ctx, timeToExpire)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,6 +91,143 @@ func (_m *MockStore) Get(ctx context.Context, slug string) (domain.URLMapping, e
 	return r0, r1
 }
 
+// GetBatch provides a mock function with given fields: ctx, slugs
+func (_m *MockStore) GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error) {
+	ret := _m.Called(ctx, slugs)
+
+	var r0 []domain.URLMapping
+	var r1 []error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.URLMapping, []error, error)); ok {
+		return rf(ctx, slugs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.URLMapping); ok {
+		r0 = rf(ctx, slugs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.URLMapping)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) []error); ok {
+		r1 = rf(ctx, slugs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []string) error); ok {
+		r2 = rf(ctx, slugs)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByOriginalURL provides a mock function with given fields: ctx, originalURL
+func (_m *MockStore) GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error) {
+	ret := _m.Called(ctx, originalURL)
+
+	var r0 []domain.URLMapping
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.URLMapping, error)); ok {
+		return rf(ctx, originalURL)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.URLMapping); ok {
+		r0 = rf(ctx, originalURL)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.URLMapping)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, originalURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncludingExpired provides a mock function with given fields: ctx, slug
+func (_m *MockStore) GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 domain.URLMapping
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.URLMapping, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.URLMapping); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.URLMapping)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *MockStore) List(ctx context.Context, filter ListFilter) (ListPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 ListPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ListFilter) (ListPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ListFilter) ListPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(ListPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ListFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, slug
+func (_m *MockStore) Purge(ctx context.Context, slug string) error {
+	ret := _m.Called(ctx, slug)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ScanSlugs provides a mock function with given fields: ctx, fn
+func (_m *MockStore) ScanSlugs(ctx context.Context, fn func(string)) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(string)) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Set provides a mock function with given fields: ctx, slug, fullURL
 func (_m *MockStore) Set(ctx context.Context, shortURL domain.URLMapping) error {
 	ret := _m.Called(ctx, shortURL)
@@ -91,4 +240,78 @@ func (_m *MockStore) Set(ctx context.Context, shortURL domain.URLMapping) error
 	}
 
 	return r0
-}
\ No newline at end of file
+}
+
+// SetBatch provides a mock function with given fields: ctx, shortURLs
+func (_m *MockStore) SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error) {
+	ret := _m.Called(ctx, shortURLs)
+
+	var r0 []error
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.URLMapping) ([]error, error)); ok {
+		return rf(ctx, shortURLs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.URLMapping) []error); ok {
+		r0 = rf(ctx, shortURLs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.URLMapping) error); ok {
+		r1 = rf(ctx, shortURLs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDisabled provides a mock function with given fields: ctx, slug, disabled
+func (_m *MockStore) SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+	ret := _m.Called(ctx, slug, disabled)
+
+	var r0 domain.URLMapping
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (domain.URLMapping, error)); ok {
+		return rf(ctx, slug, disabled)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) domain.URLMapping); ok {
+		r0 = rf(ctx, slug, disabled)
+	} else {
+		r0 = ret.Get(0).(domain.URLMapping)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, slug, disabled)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOriginalURL provides a mock function with given fields: ctx, slug, originalURL
+func (_m *MockStore) UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+	ret := _m.Called(ctx, slug, originalURL)
+
+	var r0 domain.URLMapping
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.URLMapping, error)); ok {
+		return rf(ctx, slug, originalURL)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.URLMapping); ok {
+		r0 = rf(ctx, slug, originalURL)
+	} else {
+		r0 = ret.Get(0).(domain.URLMapping)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, slug, originalURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
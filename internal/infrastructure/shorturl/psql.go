@@ -4,67 +4,79 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 	"urlShortenerService/domain"
-	"urlShortenerService/internal/infrastructure/config"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// tombstoneReasonExpired is the reason of a tombstone kept for an expired slug
+const tombstoneReasonExpired string = "expired"
+
 var (
-	// deleteExpiredStmt is the prepared statement to delete expired slug / url couple from the database
-	deleteExpiredStmt string = "DELETE FROM urls WHERE inserted_at < $1 RETURNING slug;"
+	// deleteStmt is the prepared statement to delete a slug / url couple from the database
+	deleteStmt string = "DELETE FROM urls WHERE slug=$1;"
+	// deleteExpiredStmt is the prepared statement to delete expired slug / url couple from the database and keep their tombstone
+	deleteExpiredStmt string = `WITH deleted AS (DELETE FROM urls WHERE expires_at <= $1 RETURNING slug, expires_at)
+	INSERT INTO url_tombstones (slug, expired_at, reason) SELECT slug, expires_at, $2::TEXT FROM deleted
+	ON CONFLICT (slug) DO UPDATE SET expired_at = EXCLUDED.expired_at, reason = EXCLUDED.reason RETURNING slug;`
 	// getStmt is the prepared statement to retrieve a url given a slug from the database
-	getStmt string = "SELECT slug, original_url, inserted_at FROM urls WHERE slug=$1;"
+	getStmt string = "SELECT slug, original_url, inserted_at, expires_at, COALESCE(owner, ''), disabled FROM urls WHERE slug=$1;"
+	// getBatchStmt is the prepared statement to retrieve the urls given several slugs from the database, along with the existence of their tombstone
+	getBatchStmt string = `SELECT u.original_url, u.inserted_at, u.expires_at, COALESCE(u.owner, ''), COALESCE(u.disabled, FALSE), EXISTS(SELECT 1 FROM url_tombstones t WHERE t.slug = s.slug)
+	FROM unnest($1::TEXT[]) WITH ORDINALITY AS s(slug, position) LEFT JOIN urls u ON u.slug = s.slug ORDER BY s.position;`
+	// getByOriginalURLStmt is the prepared statement to retrieve the slugs given a url from the database
+	getByOriginalURLStmt string = "SELECT slug, original_url, inserted_at, expires_at, COALESCE(owner, ''), disabled FROM urls WHERE original_url=$1 ORDER BY inserted_at, slug;"
+	// getTombstoneStmt is the prepared statement to check if a tombstone exists for a slug within the database
+	getTombstoneStmt string = "SELECT EXISTS(SELECT 1 FROM url_tombstones WHERE slug=$1);"
+	// updateOriginalURLStmt is the prepared statement to update the url of a non expired slug within the database
+	updateOriginalURLStmt string = "UPDATE urls SET original_url = $2 WHERE slug=$1 AND (expires_at IS NULL OR expires_at > $3) RETURNING slug, original_url, inserted_at, expires_at, COALESCE(owner, ''), disabled;"
+	// setDisabledStmt is the prepared statement to disable or enable a non expired slug within the database
+	setDisabledStmt string = "UPDATE urls SET disabled = $2 WHERE slug=$1 AND (expires_at IS NULL OR expires_at > $3) RETURNING slug, original_url, inserted_at, expires_at, COALESCE(owner, ''), disabled;"
+	// purgeStmt is the prepared statement to delete a slug / url couple along with its tombstone from the database, it returns the number of rows deleted
+	purgeStmt string = `WITH deleted_url AS (DELETE FROM urls WHERE slug=$1 RETURNING slug), deleted_tombstone AS (DELETE FROM url_tombstones WHERE slug=$1 RETURNING slug)
+	SELECT (SELECT COUNT(*) FROM deleted_url) + (SELECT COUNT(*) FROM deleted_tombstone);`
+	// listStmt is the prepared statement to list the urls from the database, the conditions and the order are filled in at runtime
+	listStmt string = "SELECT slug, original_url, inserted_at, expires_at, COALESCE(owner, ''), disabled FROM urls WHERE %s ORDER BY inserted_at %s, slug %s LIMIT %d;"
+	// scanSlugsStmt is the prepared statement to retrieve all the slugs from the database, tombstones included
+	scanSlugsStmt string = "SELECT slug FROM urls UNION ALL SELECT slug FROM url_tombstones;"
 	// setStmt is the prepared statement to insert a slug / url couple into the database
-	setStmt string = "INSERT INTO urls (slug, original_url, inserted_at) VALUES ($1, $2, $3) ON CONFLICT (slug) DO UPDATE SET inserted_at = $3;"
+	// The conflict update only applies if the slug is already associated to the same url and owner, whose insertion date and disabling are kept and expiration replaced, or is expired, otherwise no row is returned
+	setStmt string = `INSERT INTO urls (slug, original_url, inserted_at, expires_at, owner) VALUES ($1, $2, $3, $4, NULLIF($6::TEXT, ''))
+	ON CONFLICT (slug) DO UPDATE SET original_url = $2, owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at,
+	inserted_at = CASE WHEN urls.expires_at <= $5 THEN EXCLUDED.inserted_at ELSE urls.inserted_at END,
+	disabled = CASE WHEN urls.expires_at <= $5 THEN FALSE ELSE urls.disabled END
+	WHERE (urls.original_url = $2 AND urls.owner IS NOT DISTINCT FROM EXCLUDED.owner) OR urls.expires_at <= $5 RETURNING slug;`
 )
 
 // PSQLStore represents a postgres SQL store
 type PSQLStore struct {
-	conn *pgx.Conn
+	pool *pgxpool.Pool
 }
 
-// NewPSQLStore connects to a database and return it inside a PSQLStore
-func NewPSQLStore(connConf config.PSQLConnConfig) (*PSQLStore, error) {
-	ctx := context.Background()
-	conn, err := pgx.Connect(ctx, connConf.ToConnString())
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	store := &PSQLStore{conn: conn}
-
-	err = store.initTables(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	return store, nil
+// NewPSQLStore creates a PSQLStore on a pool of connections shared with the other PSQL stores, closed by its owner
+// The schema is expected to be up to date, see psql.Migrator
+func NewPSQLStore(pool *pgxpool.Pool) *PSQLStore {
+	return &PSQLStore{pool: pool}
 }
 
-// initTable initializes the PSQL tables
-func (s *PSQLStore) initTables(ctx context.Context) error {
-	createTableQuery := `
-	CREATE TABLE IF NOT EXISTS urls (
-		slug TEXT PRIMARY KEY,
-		original_url TEXT NOT NULL,
-		inserted_at TIMESTAMP NOT NULL
-	);`
-
-	_, err := s.conn.Exec(ctx, createTableQuery)
+// Delete implements the Store interface
+func (s *PSQLStore) Delete(ctx context.Context, slug string) error {
+	commandTag, err := s.pool.Exec(ctx, deleteStmt, slug)
 	if err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
 	}
-
 	return nil
 }
 
 // DeleteExpired implements the Store interface
-func (s *PSQLStore) DeleteExpired(ctx context.Context, timeToExpire time.Duration) ([]string, error) {
-	cutoff := time.Now().UTC().Add(-timeToExpire)
-
-	rows, err := s.conn.Query(ctx, deleteExpiredStmt, cutoff)
+func (s *PSQLStore) DeleteExpired(ctx context.Context) ([]string, error) {
+	rows, err := s.pool.Query(ctx, deleteExpiredStmt, time.Now().UTC(), tombstoneReasonExpired)
 	if err != nil {
 		return nil, err
 	}
@@ -90,27 +102,292 @@ func (s *PSQLStore) DeleteExpired(ctx context.Context, timeToExpire time.Duratio
 
 // Get implements the Store interface
 func (s *PSQLStore) Get(ctx context.Context, slug string) (domain.URLMapping, error) {
+	url, err := s.GetIncludingExpired(ctx, slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	// The URL might be expired but not deleted yet
+	if url.IsExpired(time.Now()) {
+		return domain.URLMapping{}, ErrExpired
+	}
+	return url, nil
+}
+
+// GetIncludingExpired implements the Store interface
+func (s *PSQLStore) GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error) {
 	var url domain.URLMapping
-	err := s.conn.QueryRow(ctx, getStmt, slug).Scan(&url.Slug, &url.OriginalURL, &url.InsertedAt)
+	err := s.pool.QueryRow(ctx, getStmt, slug).Scan(&url.Slug, &url.OriginalURL, &url.InsertedAt, &url.ExpiresAt, &url.Owner, &url.Disabled)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return url, ErrNotFound
+			return domain.URLMapping{}, s.getTombstone(ctx, slug)
 		}
-		return url, err
+		return domain.URLMapping{}, err
 	}
 	return url, nil
 }
 
+// GetBatch implements the Store interface
+// All the slugs are retrieved within a single query
+func (s *PSQLStore) GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error) {
+	rows, err := s.pool.Query(ctx, getBatchStmt, slugs)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	urls := make([]domain.URLMapping, len(slugs))
+	errs := make([]error, len(slugs))
+	for i := 0; rows.Next(); i++ {
+		var originalURL *string
+		var insertedAt *time.Time
+		var expiresAt *time.Time
+		var owner string
+		var disabled bool
+		var tombstoneExists bool
+		err := rows.Scan(&originalURL, &insertedAt, &expiresAt, &owner, &disabled, &tombstoneExists)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case originalURL == nil && tombstoneExists:
+			errs[i] = ErrExpired
+		case originalURL == nil:
+			errs[i] = ErrNotFound
+		default:
+			url := domain.URLMapping{Slug: slugs[i], OriginalURL: *originalURL, InsertedAt: *insertedAt, ExpiresAt: expiresAt, Owner: owner, Disabled: disabled}
+			// The URL might be expired but not deleted yet
+			if url.IsExpired(now) {
+				errs[i] = ErrExpired
+				continue
+			}
+			urls[i] = url
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return urls, errs, nil
+}
+
+// GetByOriginalURL implements the Store interface
+func (s *PSQLStore) GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error) {
+	rows, err := s.pool.Query(ctx, getByOriginalURLStmt, originalURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []domain.URLMapping
+	for rows.Next() {
+		var url domain.URLMapping
+		err := rows.Scan(&url.Slug, &url.OriginalURL, &url.InsertedAt, &url.ExpiresAt, &url.Owner, &url.Disabled)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// getTombstone returns ErrExpired if a tombstone exists for the slug, ErrNotFound otherwise
+func (s *PSQLStore) getTombstone(ctx context.Context, slug string) error {
+	var exists bool
+	err := s.pool.QueryRow(ctx, getTombstoneStmt, slug).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrExpired
+	}
+	return ErrNotFound
+}
+
+// List implements the Store interface
+func (s *PSQLStore) List(ctx context.Context, filter ListFilter) (ListPage, error) {
+	conditions := []string{"TRUE"}
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Domain != "" {
+		// Sanitized URLs always have a path, so the host is between the scheme separator and the first slash
+		addCondition("split_part(split_part(original_url, '://', 2), '/', 1) = $%d", filter.Domain)
+	}
+	if filter.Contains != "" {
+		addCondition("strpos(original_url, $%d) > 0", filter.Contains)
+	}
+	if filter.InsertedFrom != nil {
+		addCondition("inserted_at >= $%d", filter.InsertedFrom.UTC())
+	}
+	if filter.InsertedTo != nil {
+		addCondition("inserted_at < $%d", filter.InsertedTo.UTC())
+	}
+
+	order, comparator := "DESC", "<"
+	if filter.Ascending {
+		order, comparator = "ASC", ">"
+	}
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return ListPage{}, err
+		}
+		args = append(args, cursor.InsertedAt.UTC(), cursor.Slug)
+		conditions = append(conditions, fmt.Sprintf("(inserted_at, slug) %s ($%d, $%d)", comparator, len(args)-1, len(args)))
+	}
+
+	// One more URL mapping is retrieved to know if there is a next page
+	query := fmt.Sprintf(listStmt, strings.Join(conditions, " AND "), order, order, filter.Limit+1)
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return ListPage{}, err
+	}
+	defer rows.Close()
+
+	var page ListPage
+	for rows.Next() {
+		var url domain.URLMapping
+		err := rows.Scan(&url.Slug, &url.OriginalURL, &url.InsertedAt, &url.ExpiresAt, &url.Owner, &url.Disabled)
+		if err != nil {
+			return ListPage{}, err
+		}
+		page.URLMappings = append(page.URLMappings, url)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return ListPage{}, err
+	}
+
+	if len(page.URLMappings) > filter.Limit {
+		page.URLMappings = page.URLMappings[:filter.Limit]
+		lastURL := page.URLMappings[len(page.URLMappings)-1]
+		page.NextCursor = encodeCursor(listCursor{InsertedAt: lastURL.InsertedAt, Slug: lastURL.Slug})
+	}
+
+	return page, nil
+}
+
+// Purge implements the Store interface
+func (s *PSQLStore) Purge(ctx context.Context, slug string) error {
+	var deleted int64
+	err := s.pool.QueryRow(ctx, purgeStmt, slug).Scan(&deleted)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// setArgs returns the arguments of the set statement for an URL mapping
+func setArgs(shortURL domain.URLMapping, now time.Time) []any {
+	if shortURL.InsertedAt.IsZero() {
+		shortURL.InsertedAt = now
+	}
+	var expiresAt *time.Time
+	if shortURL.ExpiresAt != nil {
+		utcExpiresAt := shortURL.ExpiresAt.UTC()
+		expiresAt = &utcExpiresAt
+	}
+	return []any{shortURL.Slug, shortURL.OriginalURL, shortURL.InsertedAt.UTC(), expiresAt, now.UTC(), shortURL.Owner}
+}
+
+// ScanSlugs implements the Store interface
+func (s *PSQLStore) ScanSlugs(ctx context.Context, fn func(slug string)) error {
+	rows, err := s.pool.Query(ctx, scanSlugsStmt)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var slug string
+		err := rows.Scan(&slug)
+		if err != nil {
+			return err
+		}
+		fn(slug)
+	}
+	return rows.Err()
+}
+
 // Set implements the Store interface
 func (s *PSQLStore) Set(ctx context.Context, shortURL domain.URLMapping) error {
-	if shortURL.InsertedAt.IsZero() {
-		shortURL.InsertedAt = time.Now()
+	var slug string
+	err := s.pool.QueryRow(ctx, setStmt, setArgs(shortURL, time.Now())...).Scan(&slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSlugAlreadyExists
+		}
+		return err
 	}
-	_, err := s.conn.Exec(ctx, setStmt, shortURL.Slug, shortURL.OriginalURL, shortURL.InsertedAt.UTC())
-	return err
+	return nil
 }
 
-// Close closes the database connection
-func (s *PSQLStore) Close() error {
-	return s.conn.Close(context.Background())
+// SetBatch implements the Store interface
+// All the URL mappings are sent within a single round trip
+func (s *PSQLStore) SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error) {
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, shortURL := range shortURLs {
+		batch.Queue(setStmt, setArgs(shortURL, now)...)
+	}
+
+	results := s.pool.SendBatch(ctx, batch)
+	errs := make([]error, len(shortURLs))
+	for i := range shortURLs {
+		var slug string
+		err := results.QueryRow().Scan(&slug)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrSlugAlreadyExists
+		}
+		errs[i] = err
+	}
+
+	err := results.Close()
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// SetDisabled implements the Store interface
+func (s *PSQLStore) SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+	var url domain.URLMapping
+	err := s.pool.QueryRow(ctx, setDisabledStmt, slug, disabled, time.Now().UTC()).Scan(&url.Slug, &url.OriginalURL, &url.InsertedAt, &url.ExpiresAt, &url.Owner, &url.Disabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.URLMapping{}, ErrNotFound
+		}
+		return domain.URLMapping{}, err
+	}
+	return url, nil
+}
+
+// UpdateOriginalURL implements the Store interface
+func (s *PSQLStore) UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+	var url domain.URLMapping
+	err := s.pool.QueryRow(ctx, updateOriginalURLStmt, slug, originalURL, time.Now().UTC()).Scan(&url.Slug, &url.OriginalURL, &url.InsertedAt, &url.ExpiresAt, &url.Owner, &url.Disabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.URLMapping{}, ErrNotFound
+		}
+		return domain.URLMapping{}, err
+	}
+	return url, nil
 }
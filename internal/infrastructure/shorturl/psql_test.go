@@ -1,9 +1,11 @@
 package shorturl
 
 import (
+	"context"
 	"os"
 	"testing"
 	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/psql"
 
 	"github.com/stretchr/testify/require"
 )
@@ -13,8 +15,12 @@ func TestPSQLStore(t *testing.T) {
 	defer os.Unsetenv("env")
 	conf, err := config.Load()
 	require.NoError(t, err)
-	store, err := NewPSQLStore(conf.Database)
+	pool, err := psql.NewPool(context.Background(), conf.Database)
 	require.NoError(t, err)
+	defer pool.Close()
+	_, err = psql.MigrateUp(context.Background(), pool, 0)
+	require.NoError(t, err)
+	store := NewPSQLStore(pool)
 
 	RunStoreTests(t, store)
 }
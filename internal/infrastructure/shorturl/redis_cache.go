@@ -0,0 +1,412 @@
+package shorturl
+
+import (
+	context "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/glog"
+)
+
+const (
+	// redisCacheKeyPrefix prefixes the URL mapping keys within Redis
+	redisCacheKeyPrefix string = "shorturl:url:"
+	// redisChangedKeyPrefix prefixes the keys marking the slugs just changed, which are not cached meanwhile
+	redisChangedKeyPrefix string = "shorturl:changed:"
+	// changedMarkerTTL is the duration a slug is not cached once changed
+	// It must outlast a read of the persistent store, so that an URL mapping read before the change isn't cached after it
+	changedMarkerTTL = 10 * time.Second
+	// evictionAttempts is the number of times an eviction is tried, as a missed one leaves the previous URL mapping served up to the TTL
+	evictionAttempts = 3
+	// evictionRetryDelay is the delay before retrying an eviction, multiplied by the number of failed attempts
+	evictionRetryDelay = 50 * time.Millisecond
+)
+
+// addScript caches the URL mappings whose slug is not marked as just changed, checking and caching at once
+// KEYS holds the URL mapping key then the changed key of each slug, ARGV its value then its TTL in milliseconds
+var addScript = redis.NewScript(`
+for i = 1, #KEYS, 2 do
+	if redis.call('EXISTS', KEYS[i + 1]) == 0 then
+		redis.call('SET', KEYS[i], ARGV[i], 'PX', ARGV[i + 1])
+	end
+end
+return 0
+`)
+
+// SharedCacheStats represents the statistics of the cache of URL mappings shared through Redis
+type SharedCacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"` // The Redis failures, the persistent store being used instead
+}
+
+// redisURLMapping represents an URL mapping as stored within Redis
+type redisURLMapping struct {
+	Slug        string     `json:"slug"`
+	OriginalURL string     `json:"original_url"`
+	InsertedAt  time.Time  `json:"inserted_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	Disabled    bool       `json:"disabled,omitempty"`
+}
+
+// RedisCacheStore represents a cache store shared by all the replicas through Redis, in front of a persistent store
+// Redis failures on read are logged and the persistent store is used instead
+// Evictions are retried, then ErrNotEvicted is returned as the change is stored but may not be visible until the TTL
+// A changed slug is evicted and not cached for a while, so that another replica reading it meanwhile doesn't cache it as it was
+type RedisCacheStore struct {
+	persistentStore Store
+	client          *redis.Client
+	ttl             time.Duration
+	hits            atomic.Int64
+	misses          atomic.Int64
+	errors          atomic.Int64
+}
+
+// NewRedisCacheStore connects to a redis and return it inside a RedisCacheStore, each URL mapping being kept up to the configured TTL
+// The requests are neither retried by the client nor waited for longer than the configured timeout, as the persistent store answers on failure,
+// only the evictions being retried by the store
+func NewRedisCacheStore(persistentStore Store, cfg config.RedisConfig, cacheConf config.SharedCacheConfig) (*RedisCacheStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.ToAddr(),
+		DialTimeout:  cacheConf.Timeout,
+		ReadTimeout:  cacheConf.Timeout,
+		WriteTimeout: cacheConf.Timeout,
+		MaxRetries:   -1, // 0 would mean the default retries
+	})
+
+	_, err := client.Ping(context.Background()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &RedisCacheStore{
+		persistentStore: persistentStore,
+		client:          client,
+		ttl:             cacheConf.TTL,
+	}, nil
+}
+
+// Stats returns the statistics of the cache
+func (s *RedisCacheStore) Stats() SharedCacheStats {
+	return SharedCacheStats{
+		Hits:   s.hits.Load(),
+		Misses: s.misses.Load(),
+		Errors: s.errors.Load(),
+	}
+}
+
+// Close closes the redis connection
+func (s *RedisCacheStore) Close() error {
+	return s.client.Close()
+}
+
+// failed records and logs a Redis failure
+func (s *RedisCacheStore) failed(action string, err error) {
+	s.errors.Add(1)
+	glog.Warningf("failed to %s within the shared cache: %s", action, err)
+}
+
+// expiration returns how long an URL mapping can be cached at the given time, 0 if it can't
+func (s *RedisCacheStore) expiration(urlMapping domain.URLMapping, now time.Time) time.Duration {
+	if urlMapping.ExpiresAt == nil {
+		return s.ttl
+	}
+	return max(min(s.ttl, urlMapping.ExpiresAt.Sub(now)), 0)
+}
+
+// decode reads an URL mapping stored within Redis
+func decode(value string) (domain.URLMapping, error) {
+	var m redisURLMapping
+	err := json.Unmarshal([]byte(value), &m)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	return domain.URLMapping{
+		Slug:        m.Slug,
+		OriginalURL: m.OriginalURL,
+		InsertedAt:  m.InsertedAt,
+		ExpiresAt:   m.ExpiresAt,
+		Owner:       m.Owner,
+		Disabled:    m.Disabled,
+	}, nil
+}
+
+// add caches URL mappings within a single round trip, unless their slug has just changed
+func (s *RedisCacheStore) add(ctx context.Context, urlMappings ...domain.URLMapping) {
+	now := time.Now()
+	var keys []string
+	var args []interface{}
+	for _, urlMapping := range urlMappings {
+		expiration := s.expiration(urlMapping, now)
+		if expiration <= 0 {
+			continue
+		}
+		value, err := json.Marshal(redisURLMapping(urlMapping))
+		if err != nil {
+			s.failed("encode the URL mapping", err)
+			continue
+		}
+		keys = append(keys, redisCacheKeyPrefix+urlMapping.Slug, redisChangedKeyPrefix+urlMapping.Slug)
+		args = append(args, value, strconv.FormatInt(max(expiration.Milliseconds(), 1), 10))
+	}
+	if len(keys) == 0 {
+		return
+	}
+	err := addScript.Run(ctx, s.client, keys, args...).Err()
+	if err != nil {
+		s.failed("store the URL mappings", err)
+	}
+}
+
+// evict evicts slugs and marks them as just changed within a single round trip
+func (s *RedisCacheStore) evict(ctx context.Context, slugs []string) error {
+	pipe := s.client.Pipeline()
+	for _, slug := range slugs {
+		pipe.Set(ctx, redisChangedKeyPrefix+slug, 1, changedMarkerTTL)
+		pipe.Del(ctx, redisCacheKeyPrefix+slug)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// remove evicts the changed slugs, retrying as the other replicas would serve them as they were until their TTL otherwise
+// It returns ErrNotEvicted once all the attempts failed
+func (s *RedisCacheStore) remove(ctx context.Context, slugs ...string) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+	var err error
+	for attempt := 1; attempt <= evictionAttempts; attempt++ {
+		err = s.evict(ctx, slugs)
+		if err == nil {
+			return nil
+		}
+		s.failed("evict the slugs", err)
+		if attempt == evictionAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrNotEvicted, ctx.Err())
+		case <-time.After(time.Duration(attempt) * evictionRetryDelay):
+		}
+	}
+	return fmt.Errorf("%w: %w", ErrNotEvicted, err)
+}
+
+// Set implements Store interface
+// The slug is evicted rather than cached, as only the persistent store knows the full URL mapping (e.g. its inserted date)
+func (s *RedisCacheStore) Set(ctx context.Context, shortURL domain.URLMapping) error {
+	err := s.persistentStore.Set(ctx, shortURL)
+	if err != nil {
+		return err
+	}
+
+	return s.remove(ctx, shortURL.Slug)
+}
+
+// SetBatch implements Store interface
+func (s *RedisCacheStore) SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error) {
+	errs, err := s.persistentStore.SetBatch(ctx, shortURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	var storedSlugs []string
+	var storedIndexes []int
+	for i, shortURL := range shortURLs {
+		if errs[i] == nil {
+			storedSlugs = append(storedSlugs, shortURL.Slug)
+			storedIndexes = append(storedIndexes, i)
+		}
+	}
+	err = s.remove(ctx, storedSlugs...)
+	if err != nil {
+		// Stored nevertheless, each of them tells the change may not be visible
+		for _, i := range storedIndexes {
+			errs[i] = err
+		}
+	}
+	return errs, nil
+}
+
+// Get implements Store interface
+func (s *RedisCacheStore) Get(ctx context.Context, slug string) (domain.URLMapping, error) {
+	value, err := s.client.Get(ctx, redisCacheKeyPrefix+slug).Result()
+	switch {
+	case err == nil:
+		urlMapping, err := decode(value)
+		if err == nil && !urlMapping.IsExpired(time.Now()) {
+			s.hits.Add(1)
+			return urlMapping, nil
+		}
+		s.misses.Add(1)
+	case errors.Is(err, redis.Nil):
+		s.misses.Add(1)
+	default:
+		s.failed("retrieve the slug", err)
+	}
+
+	// Expired URL mapping are not cached, the persistent store decides what to answer
+	urlMapping, err := s.persistentStore.Get(ctx, slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	s.add(ctx, urlMapping)
+	return urlMapping, nil
+}
+
+// GetIncludingExpired implements Store interface
+// The expired URL mappings are not cached, so it is always answered by the persistent store
+func (s *RedisCacheStore) GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error) {
+	return s.persistentStore.GetIncludingExpired(ctx, slug)
+}
+
+// GetBatch implements Store interface
+// The slugs are retrieved from Redis at once, then the missing ones from the persistent store at once
+func (s *RedisCacheStore) GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error) {
+	now := time.Now()
+	urlMappings := make([]domain.URLMapping, len(slugs))
+	errs := make([]error, len(slugs))
+	missedSlugs := slugs
+	missedIndexes := make([]int, len(slugs))
+	for i := range slugs {
+		missedIndexes[i] = i
+	}
+
+	keys := make([]string, len(slugs))
+	for i, slug := range slugs {
+		keys[i] = redisCacheKeyPrefix + slug
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		s.failed("retrieve the slugs", err)
+	} else {
+		missedSlugs, missedIndexes = nil, nil
+		for i, value := range values {
+			if str, ok := value.(string); ok {
+				urlMapping, err := decode(str)
+				if err == nil && !urlMapping.IsExpired(now) {
+					s.hits.Add(1)
+					urlMappings[i] = urlMapping
+					continue
+				}
+			}
+			s.misses.Add(1)
+			missedSlugs = append(missedSlugs, slugs[i])
+			missedIndexes = append(missedIndexes, i)
+		}
+	}
+	if len(missedSlugs) == 0 {
+		return urlMappings, errs, nil
+	}
+
+	missedURLMappings, missedErrs, err := s.persistentStore.GetBatch(ctx, missedSlugs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var found []domain.URLMapping
+	for j, i := range missedIndexes {
+		urlMappings[i] = missedURLMappings[j]
+		errs[i] = missedErrs[j]
+		if missedErrs[j] == nil {
+			found = append(found, missedURLMappings[j])
+		}
+	}
+	if len(found) > 0 {
+		s.add(ctx, found...)
+	}
+	return urlMappings, errs, nil
+}
+
+// Delete implements Store interface
+func (s *RedisCacheStore) Delete(ctx context.Context, slug string) error {
+	err := s.persistentStore.Delete(ctx, slug)
+
+	// Evicted whatever the persistent store answered, as the cache can't be trusted anymore
+	removeErr := s.remove(ctx, slug)
+	if err != nil {
+		return err
+	}
+	return removeErr
+}
+
+// DeleteExpired implements Store interface
+func (s *RedisCacheStore) DeleteExpired(ctx context.Context) ([]string, error) {
+	slugsDeleted, err := s.persistentStore.DeleteExpired(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The expired slugs should have left Redis along with their TTL already and are never served once expired,
+	// so a failed eviction is only logged
+	_ = s.remove(ctx, slugsDeleted...)
+	return slugsDeleted, nil
+}
+
+// GetByOriginalURL implements Store interface
+func (s *RedisCacheStore) GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error) {
+	return s.persistentStore.GetByOriginalURL(ctx, originalURL)
+}
+
+// Purge implements Store interface
+func (s *RedisCacheStore) Purge(ctx context.Context, slug string) error {
+	err := s.persistentStore.Purge(ctx, slug)
+
+	// Evicted whatever the persistent store answered, as the cache can't be trusted anymore
+	removeErr := s.remove(ctx, slug)
+	if err != nil {
+		return err
+	}
+	return removeErr
+}
+
+// ScanSlugs implements Store interface
+func (s *RedisCacheStore) ScanSlugs(ctx context.Context, fn func(slug string)) error {
+	return s.persistentStore.ScanSlugs(ctx, fn)
+}
+
+// List implements Store interface
+// The listing is always retrieved from the persistent store as the cache only holds part of the URL mappings
+func (s *RedisCacheStore) List(ctx context.Context, filter ListFilter) (ListPage, error) {
+	return s.persistentStore.List(ctx, filter)
+}
+
+// SetDisabled implements Store interface
+// The slug is evicted rather than cached, as another replica may update it concurrently
+func (s *RedisCacheStore) SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+	urlMapping, err := s.persistentStore.SetDisabled(ctx, slug, disabled)
+
+	// Evicted whatever the persistent store answered, as the cache can't be trusted anymore
+	removeErr := s.remove(ctx, slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	if removeErr != nil {
+		return domain.URLMapping{}, removeErr
+	}
+	return urlMapping, nil
+}
+
+// UpdateOriginalURL implements Store interface
+// The slug is evicted rather than cached, as another replica may update it concurrently
+func (s *RedisCacheStore) UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+	urlMapping, err := s.persistentStore.UpdateOriginalURL(ctx, slug, originalURL)
+
+	// Evicted whatever the persistent store answered, as the cache can't be trusted anymore
+	removeErr := s.remove(ctx, slug)
+	if err != nil {
+		return domain.URLMapping{}, err
+	}
+	if removeErr != nil {
+		return domain.URLMapping{}, removeErr
+	}
+	return urlMapping, nil
+}
@@ -0,0 +1,353 @@
+package shorturl
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/psql"
+
+	"github.com/alicebob/miniredis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedisCacheStore creates a redis cache store relying on a miniredis
+func newTestRedisCacheStore(t *testing.T, persistentStore Store) (*RedisCacheStore, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+	store, err := NewRedisCacheStore(persistentStore, config.RedisConfig{Host: mr.Host(), Port: port}, config.SharedCacheConfig{TTL: time.Hour, Timeout: time.Second})
+	require.NoError(t, err)
+	return store, mr
+}
+
+func TestRedisCacheStore(t *testing.T) {
+	os.Setenv("env", "test")
+	defer os.Unsetenv("env")
+	conf, err := config.Load()
+	require.NoError(t, err)
+	pool, err := psql.NewPool(context.Background(), conf.Database)
+	require.NoError(t, err)
+	defer pool.Close()
+	_, err = psql.MigrateUp(context.Background(), pool, 0)
+	require.NoError(t, err)
+	persistentStore := NewPSQLStore(pool)
+
+	store, _ := newTestRedisCacheStore(t, persistentStore)
+
+	RunStoreTests(t, store)
+}
+
+func TestRedisCacheGet(t *testing.T) {
+	slug := "jV6gHv0o"
+	expiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	shortURL := domain.URLMapping{
+		Slug:        slug,
+		OriginalURL: "https://example.com",
+		InsertedAt:  time.Now().UTC().Truncate(time.Second),
+		ExpiresAt:   &expiresAt,
+		Owner:       "owner",
+	}
+	t.Run("read through", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil).Once()
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+		require.NoError(t, err)
+		cachedURL, err := store.Get(context.Background(), slug)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, shortURL, urlMapping)
+		assert.Equal(t, shortURL, cachedURL)
+		assert.Equal(t, SharedCacheStats{Hits: 1, Misses: 1}, store.Stats())
+		ttl := mr.TTL(redisCacheKeyPrefix + slug)
+		assert.True(t, ttl > 0 && ttl <= time.Minute, "the TTL should follow the expiration, got %s", ttl)
+	})
+	t.Run("disabled kept once cached", func(t *testing.T) {
+		// Given
+		disabledURL := shortURL
+		disabledURL.Disabled = true
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(disabledURL, nil).Once()
+		store, _ := newTestRedisCacheStore(t, persitentMockStore)
+		_, err := store.Get(context.Background(), slug)
+		require.NoError(t, err)
+
+		// When
+		cachedURL, err := store.Get(context.Background(), slug)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, disabledURL, cachedURL)
+		assert.Equal(t, int64(1), store.Stats().Hits)
+	})
+	t.Run("not read through when changed meanwhile", func(t *testing.T) {
+		// Given
+		otherMockStore := NewMock(t)
+		otherMockStore.On("Delete", mock.Anything, slug).Return(nil)
+		var otherReplica *RedisCacheStore
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil).Run(func(args mock.Arguments) {
+			// Deleted by another replica once read from the persistent store, before being cached
+			require.NoError(t, otherReplica.Delete(context.Background(), slug))
+		})
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		port, err := strconv.Atoi(mr.Port())
+		require.NoError(t, err)
+		otherReplica, err = NewRedisCacheStore(otherMockStore, config.RedisConfig{Host: mr.Host(), Port: port}, config.SharedCacheConfig{TTL: time.Hour, Timeout: time.Second})
+		require.NoError(t, err)
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+slug))
+		assert.Equal(t, int64(0), store.Stats().Errors)
+	})
+	t.Run("persistent store errored", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, ErrNotFound)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, urlMapping)
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+slug))
+	})
+	t.Run("redis unavailable", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, slug).Return(shortURL, nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		urlMapping, err := store.Get(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, shortURL, urlMapping)
+		assert.Equal(t, int64(2), store.Stats().Errors) // Retrieving then storing
+	})
+}
+
+func TestRedisCacheGetBatch(t *testing.T) {
+	cachedURL := domain.URLMapping{Slug: "cached", OriginalURL: "https://example.com/cached", InsertedAt: time.Now().UTC().Truncate(time.Second)}
+	missedURL := domain.URLMapping{Slug: "missed", OriginalURL: "https://example.com/missed", InsertedAt: time.Now().UTC().Truncate(time.Second)}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Get", mock.Anything, cachedURL.Slug).Return(cachedURL, nil)
+		persitentMockStore.On("GetBatch", mock.Anything, []string{"missed", "unknown"}).Return([]domain.URLMapping{missedURL, {}}, []error{nil, ErrNotFound}, nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		_, err := store.Get(context.Background(), cachedURL.Slug)
+		require.NoError(t, err)
+
+		// When
+		urlMappings, errs, err := store.GetBatch(context.Background(), []string{"missed", "cached", "unknown"})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []domain.URLMapping{missedURL, cachedURL, {}}, urlMappings)
+		assert.Equal(t, []error{nil, nil, ErrNotFound}, errs)
+		assert.True(t, mr.Exists(redisCacheKeyPrefix+missedURL.Slug))
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+"unknown"))
+	})
+	t.Run("redis unavailable", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("GetBatch", mock.Anything, []string{"missed", "cached"}).Return([]domain.URLMapping{missedURL, cachedURL}, []error{nil, nil}, nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		urlMappings, errs, err := store.GetBatch(context.Background(), []string{"missed", "cached"})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []domain.URLMapping{missedURL, cachedURL}, urlMappings)
+		assert.Equal(t, []error{nil, nil}, errs)
+	})
+}
+
+func TestRedisCacheSet(t *testing.T) {
+	shortURL := domain.URLMapping{Slug: "example", OriginalURL: "https://example.com"}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Set", mock.Anything, shortURL).Return(nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		require.NoError(t, mr.Set(redisCacheKeyPrefix+shortURL.Slug, `{"slug":"example","original_url":"https://example.com/previous"}`))
+
+		// When
+		err := store.Set(context.Background(), shortURL)
+		require.NoError(t, err)
+
+		// Then
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+shortURL.Slug))
+		assert.Equal(t, changedMarkerTTL, mr.TTL(redisChangedKeyPrefix+shortURL.Slug))
+	})
+	t.Run("redis unavailable", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Set", mock.Anything, shortURL).Return(nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		err := store.Set(context.Background(), shortURL)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotEvicted)
+		assert.Equal(t, int64(evictionAttempts), store.Stats().Errors)
+	})
+	t.Run("batch with redis unavailable", func(t *testing.T) {
+		// Given
+		other := domain.URLMapping{Slug: "other", OriginalURL: "https://example.com/other"}
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("SetBatch", mock.Anything, []domain.URLMapping{shortURL, other}).Return([]error{nil, ErrSlugAlreadyExists}, nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		errs, err := store.SetBatch(context.Background(), []domain.URLMapping{shortURL, other})
+		require.NoError(t, err)
+
+		// Then
+		assert.ErrorIs(t, errs[0], ErrNotEvicted)
+		assert.ErrorIs(t, errs[1], ErrSlugAlreadyExists)
+	})
+}
+
+func TestRedisCacheUpdateOriginalURL(t *testing.T) {
+	slug := "jV6gHv0o"
+	updatedURL := domain.URLMapping{Slug: slug, OriginalURL: "https://example.com/updated", InsertedAt: time.Now().UTC().Truncate(time.Second)}
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("UpdateOriginalURL", mock.Anything, slug, updatedURL.OriginalURL).Return(updatedURL, nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+
+		// When
+		urlMapping, err := store.UpdateOriginalURL(context.Background(), slug, updatedURL.OriginalURL)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, updatedURL, urlMapping)
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+slug))
+		assert.True(t, mr.Exists(redisChangedKeyPrefix+slug))
+	})
+	t.Run("redis unavailable", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("UpdateOriginalURL", mock.Anything, slug, updatedURL.OriginalURL).Return(updatedURL, nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		_, err := store.UpdateOriginalURL(context.Background(), slug, updatedURL.OriginalURL)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotEvicted)
+	})
+}
+
+func TestRedisCacheDelete(t *testing.T) {
+	slug := "jV6gHv0o"
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		require.NoError(t, mr.Set(redisCacheKeyPrefix+slug, `{"slug":"jV6gHv0o","original_url":"https://example.com"}`))
+
+		// When
+		err := store.Delete(context.Background(), slug)
+		require.NoError(t, err)
+
+		// Then
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+slug))
+		assert.True(t, mr.Exists(redisChangedKeyPrefix+slug))
+	})
+	t.Run("redis unavailable", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		err := store.Delete(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotEvicted)
+	})
+	t.Run("persistent store errored first", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(ErrNotFound)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		mr.Close()
+
+		// When
+		err := store.Delete(context.Background(), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("eviction retried", func(t *testing.T) {
+		// Given
+		persitentMockStore := NewMock(t)
+		persitentMockStore.On("Delete", mock.Anything, slug).Return(nil)
+		store, mr := newTestRedisCacheStore(t, persitentMockStore)
+		require.NoError(t, mr.Set(redisCacheKeyPrefix+slug, `{"slug":"jV6gHv0o","original_url":"https://example.com"}`))
+		mr.Close()
+		restarted := make(chan error, 1)
+		time.AfterFunc(evictionRetryDelay/2, func() { restarted <- mr.Restart() })
+
+		// When
+		err := store.Delete(context.Background(), slug)
+		require.NoError(t, err)
+
+		// Then
+		require.NoError(t, <-restarted)
+		assert.False(t, mr.Exists(redisCacheKeyPrefix+slug))
+		assert.Equal(t, int64(1), store.Stats().Errors)
+	})
+}
+
+func TestNewRedisCacheStore(t *testing.T) {
+	// Given
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+
+	// When
+	store, err := NewRedisCacheStore(NewMock(t), config.RedisConfig{Host: mr.Host(), Port: port}, config.SharedCacheConfig{TTL: time.Hour, Timeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	// Then
+	options := store.client.Options()
+	assert.Equal(t, 50*time.Millisecond, options.DialTimeout)
+	assert.Equal(t, 50*time.Millisecond, options.ReadTimeout)
+	assert.Equal(t, 0, options.MaxRetries) // Not retried, the -1 given being normalized
+}
@@ -10,14 +10,74 @@ import (
 var (
 	// ErrNotFound is the error when a slug is not found within the database
 	ErrNotFound error = errors.New("url not found")
+	// ErrExpired is the error when a slug has expired (its tombstone is kept within the database)
+	ErrExpired error = errors.New("url expired")
+	// ErrSlugAlreadyExists is the error when a slug is already associated to a different URL within the database
+	ErrSlugAlreadyExists error = errors.New("slug already associated to a different url")
+	// ErrNotEvicted is the error when a change is stored but the slug could not be evicted from the shared cache
+	// The previous URL mapping may then be served by all the replicas until its TTL
+	ErrNotEvicted error = errors.New("url changed but not evicted from the shared cache")
 )
 
+// isStored informs if the change answered by err has been stored, whether the shared cache has been evicted or not
+func isStored(err error) bool {
+	return err == nil || errors.Is(err, ErrNotEvicted)
+}
+
+// ListFilter represents the filters and the pagination of an URL mappings listing
+type ListFilter struct {
+	Domain       string     // Optional, the host of the original URL
+	Contains     string     // Optional, a substring of the original URL
+	InsertedFrom *time.Time // Optional, the inclusive lower bound of the inserted date
+	InsertedTo   *time.Time // Optional, the exclusive upper bound of the inserted date
+	Ascending    bool       // Sorts by inserted date ascending, descending otherwise
+	Cursor       string     // Optional, the cursor of the page to retrieve as returned by the previous page
+	Limit        int        // The maximal number of URL mappings within the page
+}
+
+// ListPage represents a page of an URL mappings listing
+type ListPage struct {
+	URLMappings []domain.URLMapping
+	NextCursor  string // Empty if there is no next page
+}
+
 // Store represents operations on shorturl Store
 type Store interface {
-	// DeleteExpired deletes the slug / URL couples that are expired
-	DeleteExpired(ctx context.Context, timeToExpire time.Duration) ([]string, error)
+	// Delete deletes the slug / URL couple
+	// It returns ErrNotFound if the slug does not exist
+	Delete(ctx context.Context, slug string) error
+	// DeleteExpired deletes the slug / URL couples that are expired and keeps a tombstone of them
+	DeleteExpired(ctx context.Context) ([]string, error)
 	// Get retrieves the URL associated to a specific slug
+	// It returns ErrNotFound if the slug does not exist or ErrExpired if it has expired
 	Get(ctx context.Context, slug string) (domain.URLMapping, error)
+	// GetIncludingExpired retrieves the URL mapping of a specific slug, even expired as long as it is not deleted yet
+	// It returns ErrNotFound if the slug does not exist or ErrExpired if it has expired and been deleted
+	GetIncludingExpired(ctx context.Context, slug string) (domain.URLMapping, error)
+	// GetBatch retrieves the URLs associated to several slugs at once
+	// It returns an URL mapping and an error per slug, ErrNotFound if the slug does not exist or ErrExpired if it has expired
+	GetBatch(ctx context.Context, slugs []string) ([]domain.URLMapping, []error, error)
+	// GetByOriginalURL retrieves all the URL mappings associated to a specific URL sorted by inserted date
+	GetByOriginalURL(ctx context.Context, originalURL string) ([]domain.URLMapping, error)
+	// List retrieves a page of the URL mappings matching the filter sorted by inserted date
+	// It returns ErrInvalidCursor if the filter cursor can't be decoded
+	List(ctx context.Context, filter ListFilter) (ListPage, error)
+	// Purge deletes the slug / URL couple along with its tombstone, so that the slug is forgotten whatever its state
+	// It returns ErrNotFound if neither the slug nor its tombstone exist
+	Purge(ctx context.Context, slug string) error
+	// ScanSlugs calls fn with each known slug, those of the expired URL mappings whose tombstone is kept included
+	ScanSlugs(ctx context.Context, fn func(slug string)) error
 	// Set stores the slug and the URL associated
+	// If the slug is already associated to the same URL and owner, its insertion date is kept and its expiration replaced
+	// It returns ErrSlugAlreadyExists if the slug is already associated to a different URL
 	Set(ctx context.Context, shortURL domain.URLMapping) error
+	// SetBatch stores several slug and URL associated at once
+	// It returns an error per URL mapping, ErrSlugAlreadyExists if the slug is already associated to a different URL
+	SetBatch(ctx context.Context, shortURLs []domain.URLMapping) ([]error, error)
+	// SetDisabled disables or enables again a specific slug and returns the updated URL mapping
+	// It returns ErrNotFound if the slug does not exist or has expired
+	SetDisabled(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error)
+	// UpdateOriginalURL changes the URL associated to a specific slug and returns the updated URL mapping
+	// It returns ErrNotFound if the slug does not exist or has expired
+	UpdateOriginalURL(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error)
 }
@@ -19,8 +19,20 @@ func RunStoreTests(t *testing.T, store Store) {
 
 	t.Run("TestSet", suite.TestSet)
 	t.Run("TestGet", suite.TestGet)
+	t.Run("TestGetIncludingExpired", suite.TestGetIncludingExpired)
 	t.Run("TestSetDuplicateSlug", suite.TestSetDuplicateSlug)
+	t.Run("TestSetConflictingSlug", suite.TestSetConflictingSlug)
 	t.Run("TestDeleteExpired", suite.TestDeleteExpired)
+	t.Run("TestDelete", suite.TestDelete)
+	t.Run("TestUpdateOriginalURL", suite.TestUpdateOriginalURL)
+	t.Run("TestSetDisabled", suite.TestSetDisabled)
+	t.Run("TestPurge", suite.TestPurge)
+	t.Run("TestList", suite.TestList)
+	t.Run("TestGetByOriginalURL", suite.TestGetByOriginalURL)
+	t.Run("TestSetBatch", suite.TestSetBatch)
+	t.Run("TestGetBatch", suite.TestGetBatch)
+	t.Run("TestSetOwnedSlug", suite.TestSetOwnedSlug)
+	t.Run("TestScanSlugs", suite.TestScanSlugs)
 }
 
 func (suite *StoreTestSuite) TestSet(t *testing.T) {
@@ -67,6 +79,25 @@ func (suite *StoreTestSuite) TestGet(t *testing.T) {
 			assert.NotEmpty(t, retrievedURL.InsertedAt)
 		}
 	})
+	t.Run("slug expired", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		expiredAt := time.Now().UTC().Add(-1 * time.Minute)
+		shortURL := domain.URLMapping{
+			Slug:        "expired-not-deleted",
+			OriginalURL: "https://example.com/expired-not-deleted",
+			ExpiresAt:   &expiredAt,
+		}
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// When
+		retrievedURL, err := suite.Store.Get(ctx, shortURL.Slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrExpired)
+		assert.Empty(t, retrievedURL)
+	})
 	t.Run("slug not found", func(t *testing.T) {
 		// Given
 		ctx := context.Background()
@@ -80,18 +111,58 @@ func (suite *StoreTestSuite) TestGet(t *testing.T) {
 	})
 }
 
+func (suite *StoreTestSuite) TestGetIncludingExpired(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	expiredAt := time.Now().UTC().Add(-1 * time.Hour).Truncate(time.Second)
+	shortURL := domain.URLMapping{
+		Slug:        "expired-metadata",
+		OriginalURL: "https://example.com/expired-metadata",
+		ExpiresAt:   &expiredAt,
+	}
+	err := suite.Store.Set(ctx, shortURL)
+	require.NoError(t, err)
+
+	// When
+	retrievedURL, err := suite.Store.GetIncludingExpired(ctx, shortURL.Slug)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, shortURL.OriginalURL, retrievedURL.OriginalURL)
+	require.NotNil(t, retrievedURL.ExpiresAt)
+	assert.True(t, expiredAt.Equal(*retrievedURL.ExpiresAt))
+	_, err = suite.Store.Get(ctx, shortURL.Slug)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	// Once deleted, only its tombstone is kept
+	_, err = suite.Store.DeleteExpired(ctx)
+	require.NoError(t, err)
+	_, err = suite.Store.GetIncludingExpired(ctx, shortURL.Slug)
+	assert.ErrorIs(t, err, ErrExpired)
+	_, err = suite.Store.GetIncludingExpired(ctx, "unknown-metadata")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
 func (suite *StoreTestSuite) TestSetDuplicateSlug(t *testing.T) {
 	// Given
 	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	newExpiresAt := time.Now().Add(2 * time.Hour).UTC()
 	shortURL1 := domain.URLMapping{
 		Slug:        "duplicate",
 		OriginalURL: "https://example.com/duplicate",
 		InsertedAt:  time.Now().Add(-2 * time.Hour).UTC(),
+		ExpiresAt:   &expiresAt,
 	}
 	shortURL2 := domain.URLMapping{
 		Slug:        "duplicate",
 		OriginalURL: "https://example.com/duplicate",
 		InsertedAt:  time.Now().Add(-1 * time.Hour).UTC(),
+		ExpiresAt:   &newExpiresAt,
+	}
+	shortURL3 := domain.URLMapping{
+		Slug:        "duplicate",
+		OriginalURL: "https://example.com/duplicate",
 	}
 
 	// When
@@ -106,38 +177,487 @@ func (suite *StoreTestSuite) TestSetDuplicateSlug(t *testing.T) {
 	assert.Equal(t, shortURL1.Slug, retrievedURL.Slug)
 	assert.Equal(t, shortURL1.OriginalURL, retrievedURL.OriginalURL)
 	if _, ok := suite.Store.(*CacheStore); !ok { // This assertion shouldn't be tested for cache
-		assert.Equal(t, shortURL2.InsertedAt.Truncate(time.Second), retrievedURL.InsertedAt.Truncate(time.Second))
+		// The insertion date is kept while the expiration is replaced
+		assert.Equal(t, shortURL1.InsertedAt.Truncate(time.Second), retrievedURL.InsertedAt.Truncate(time.Second))
+		require.NotNil(t, retrievedURL.ExpiresAt)
+		assert.Equal(t, newExpiresAt.Truncate(time.Second), retrievedURL.ExpiresAt.Truncate(time.Second))
 	}
+
+	// When
+	err = suite.Store.Set(ctx, shortURL3)
+	require.NoError(t, err)
+
+	// Then
+	retrievedURL, err = suite.Store.Get(ctx, shortURL1.Slug)
+	require.NoError(t, err)
+	if _, ok := suite.Store.(*CacheStore); !ok { // This assertion shouldn't be tested for cache
+		// The URL mapping now never expires
+		assert.Equal(t, shortURL1.InsertedAt.Truncate(time.Second), retrievedURL.InsertedAt.Truncate(time.Second))
+		assert.Nil(t, retrievedURL.ExpiresAt)
+	}
+}
+
+func (suite *StoreTestSuite) TestSetConflictingSlug(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	shortURL1 := domain.URLMapping{
+		Slug:        "conflict",
+		OriginalURL: "https://example.com/conflict-1",
+	}
+	shortURL2 := domain.URLMapping{
+		Slug:        "conflict",
+		OriginalURL: "https://example.com/conflict-2",
+	}
+	err := suite.Store.Set(ctx, shortURL1)
+	require.NoError(t, err)
+
+	// When
+	err = suite.Store.Set(ctx, shortURL2)
+
+	// Then
+	assert.ErrorIs(t, err, ErrSlugAlreadyExists)
+	retrievedURL, err := suite.Store.Get(ctx, shortURL1.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, shortURL1.OriginalURL, retrievedURL.OriginalURL)
 }
 
 func (suite *StoreTestSuite) TestDeleteExpired(t *testing.T) {
 	// Given
 	ctx := context.Background()
+	expiredAt := time.Now().UTC().Add(-1 * time.Hour)
+	expiresAt := time.Now().UTC().Add(1 * time.Hour)
 	shortURLExpired := domain.URLMapping{
 		Slug:        "expired",
 		OriginalURL: "https://example.com/expired",
 		InsertedAt:  time.Now().UTC().Add(-24 * time.Hour),
+		ExpiresAt:   &expiredAt,
 	}
 	shortURL := domain.URLMapping{
 		Slug:        "active",
 		OriginalURL: "https://example.com/active",
 		InsertedAt:  time.Now().UTC(),
+		ExpiresAt:   &expiresAt,
+	}
+	shortURLNeverExpires := domain.URLMapping{
+		Slug:        "never-expires",
+		OriginalURL: "https://example.com/never-expires",
+		InsertedAt:  time.Now().UTC().Add(-24 * time.Hour),
 	}
 
 	err := suite.Store.Set(ctx, shortURLExpired)
 	require.NoError(t, err)
 	err = suite.Store.Set(ctx, shortURL)
 	require.NoError(t, err)
+	err = suite.Store.Set(ctx, shortURLNeverExpires)
+	require.NoError(t, err)
 
 	// When
-	slugsDeleted, err := suite.Store.DeleteExpired(ctx, 10*time.Hour)
+	slugsDeleted, err := suite.Store.DeleteExpired(ctx)
 	require.NoError(t, err)
 
 	// Then
-	require.Len(t, slugsDeleted, 1)
-	assert.Equal(t, shortURLExpired.Slug, slugsDeleted[0])
+	assert.Contains(t, slugsDeleted, shortURLExpired.Slug)
+	assert.NotContains(t, slugsDeleted, shortURL.Slug)
+	assert.NotContains(t, slugsDeleted, shortURLNeverExpires.Slug)
 	_, err = suite.Store.Get(ctx, shortURLExpired.Slug)
-	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, ErrExpired)
 	_, err = suite.Store.Get(ctx, shortURL.Slug)
 	assert.NoError(t, err)
+	_, err = suite.Store.Get(ctx, shortURLNeverExpires.Slug)
+	assert.NoError(t, err)
+}
+
+func (suite *StoreTestSuite) TestDelete(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		shortURL := domain.URLMapping{
+			Slug:        "to-delete",
+			OriginalURL: "https://example.com/to-delete",
+		}
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// When
+		err = suite.Store.Delete(ctx, shortURL.Slug)
+		require.NoError(t, err)
+
+		// Then
+		_, err = suite.Store.Get(ctx, shortURL.Slug)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("slug not found", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+
+		// When
+		err := suite.Store.Delete(ctx, "unknown-slug")
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func (suite *StoreTestSuite) TestUpdateOriginalURL(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		shortURL := domain.URLMapping{
+			Slug:        "to-update",
+			OriginalURL: "https://example.com/to-update",
+		}
+		newOriginalURL := "https://example.com/updated"
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// When
+		updatedURL, err := suite.Store.UpdateOriginalURL(ctx, shortURL.Slug, newOriginalURL)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, shortURL.Slug, updatedURL.Slug)
+		assert.Equal(t, newOriginalURL, updatedURL.OriginalURL)
+		retrievedURL, err := suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, newOriginalURL, retrievedURL.OriginalURL)
+	})
+	t.Run("slug not found", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+
+		// When
+		updatedURL, err := suite.Store.UpdateOriginalURL(ctx, "unknown-slug", "https://example.com/updated")
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, updatedURL)
+	})
+}
+
+func (suite *StoreTestSuite) TestSetDisabled(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		shortURL := domain.URLMapping{
+			Slug:        "to-disable",
+			OriginalURL: "https://example.com/to-disable",
+		}
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// When
+		disabledURL, err := suite.Store.SetDisabled(ctx, shortURL.Slug, true)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, shortURL.OriginalURL, disabledURL.OriginalURL)
+		assert.True(t, disabledURL.Disabled)
+		retrievedURL, err := suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.True(t, retrievedURL.Disabled)
+
+		// When shortened again by its owner, it stays disabled
+		err = suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// Then
+		retrievedURL, err = suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.True(t, retrievedURL.Disabled)
+
+		// When enabled again
+		enabledURL, err := suite.Store.SetDisabled(ctx, shortURL.Slug, false)
+		require.NoError(t, err)
+
+		// Then
+		assert.False(t, enabledURL.Disabled)
+		retrievedURL, err = suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.False(t, retrievedURL.Disabled)
+	})
+	t.Run("slug not found", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+
+		// When
+		disabledURL, err := suite.Store.SetDisabled(ctx, "unknown-slug", true)
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, disabledURL)
+	})
+}
+
+func (suite *StoreTestSuite) TestPurge(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		shortURL := domain.URLMapping{
+			Slug:        "to-purge",
+			OriginalURL: "https://example.com/to-purge",
+		}
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// When
+		err = suite.Store.Purge(ctx, shortURL.Slug)
+		require.NoError(t, err)
+
+		// Then
+		_, err = suite.Store.Get(ctx, shortURL.Slug)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("tombstone", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		expiredAt := time.Now().UTC().Add(-1 * time.Hour)
+		shortURL := domain.URLMapping{
+			Slug:        "expired-to-purge",
+			OriginalURL: "https://example.com/expired-to-purge",
+			InsertedAt:  time.Now().UTC().Add(-24 * time.Hour),
+			ExpiresAt:   &expiredAt,
+		}
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+		_, err = suite.Store.DeleteExpired(ctx)
+		require.NoError(t, err)
+
+		// When
+		err = suite.Store.Purge(ctx, shortURL.Slug)
+		require.NoError(t, err)
+
+		// Then the slug is forgotten rather than expired
+		_, err = suite.Store.Get(ctx, shortURL.Slug)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("slug not found", func(t *testing.T) {
+		// When
+		err := suite.Store.Purge(context.Background(), "unknown-slug")
+
+		// Then
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func (suite *StoreTestSuite) TestList(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	shortURLs := []domain.URLMapping{
+		{Slug: "list-1", OriginalURL: "https://list.example.com/campaign/1", InsertedAt: now.Add(-4 * time.Hour)},
+		{Slug: "list-2", OriginalURL: "https://list.example.com/campaign/2", InsertedAt: now.Add(-3 * time.Hour)},
+		{Slug: "list-3", OriginalURL: "https://list.example.com/other/3", InsertedAt: now.Add(-2 * time.Hour)},
+		{Slug: "list-4", OriginalURL: "https://list.example.com/campaign/4", InsertedAt: now.Add(-1 * time.Hour)},
+		{Slug: "list-5", OriginalURL: "https://other-list.example.com/campaign/5", InsertedAt: now.Add(-1 * time.Hour)},
+	}
+	for _, shortURL := range shortURLs {
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+	}
+	slugsOf := func(urlMappings []domain.URLMapping) []string {
+		var slugs []string
+		for _, urlMapping := range urlMappings {
+			slugs = append(slugs, urlMapping.Slug)
+		}
+		return slugs
+	}
+
+	t.Run("paginated by domain", func(t *testing.T) {
+		// When
+		firstPage, err := suite.Store.List(ctx, ListFilter{Domain: "list.example.com", Limit: 3})
+		require.NoError(t, err)
+		secondPage, err := suite.Store.List(ctx, ListFilter{Domain: "list.example.com", Limit: 3, Cursor: firstPage.NextCursor})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []string{"list-4", "list-3", "list-2"}, slugsOf(firstPage.URLMappings))
+		assert.NotEmpty(t, firstPage.NextCursor)
+		assert.Equal(t, []string{"list-1"}, slugsOf(secondPage.URLMappings))
+		assert.Empty(t, secondPage.NextCursor)
+	})
+	t.Run("ascending with substring", func(t *testing.T) {
+		// When
+		page, err := suite.Store.List(ctx, ListFilter{Domain: "list.example.com", Contains: "/campaign/", Ascending: true, Limit: 10})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []string{"list-1", "list-2", "list-4"}, slugsOf(page.URLMappings))
+		assert.Empty(t, page.NextCursor)
+	})
+	t.Run("within a date range", func(t *testing.T) {
+		// Given
+		from := now.Add(-3 * time.Hour)
+		to := now.Add(-1 * time.Hour)
+
+		// When
+		page, err := suite.Store.List(ctx, ListFilter{Domain: "list.example.com", InsertedFrom: &from, InsertedTo: &to, Limit: 10})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []string{"list-3", "list-2"}, slugsOf(page.URLMappings))
+	})
+	t.Run("invalid cursor", func(t *testing.T) {
+		// When
+		page, err := suite.Store.List(ctx, ListFilter{Limit: 10, Cursor: "invalid"})
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Empty(t, page)
+	})
+}
+
+func (suite *StoreTestSuite) TestGetByOriginalURL(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	shortURLs := []domain.URLMapping{
+		{Slug: "reverse-1", OriginalURL: "https://reverse.example.com/", InsertedAt: now.Add(-2 * time.Hour)},
+		{Slug: "reverse-2", OriginalURL: "https://reverse.example.com/", InsertedAt: now.Add(-1 * time.Hour)},
+		{Slug: "reverse-3", OriginalURL: "https://reverse.example.com/other", InsertedAt: now},
+	}
+	for _, shortURL := range shortURLs {
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+	}
+
+	t.Run("found", func(t *testing.T) {
+		// When
+		urlMappings, err := suite.Store.GetByOriginalURL(ctx, "https://reverse.example.com/")
+		require.NoError(t, err)
+
+		// Then
+		require.Len(t, urlMappings, 2)
+		assert.Equal(t, "reverse-1", urlMappings[0].Slug)
+		assert.Equal(t, "reverse-2", urlMappings[1].Slug)
+		assert.Equal(t, "https://reverse.example.com/", urlMappings[0].OriginalURL)
+	})
+	t.Run("not found", func(t *testing.T) {
+		// When
+		urlMappings, err := suite.Store.GetByOriginalURL(ctx, "https://reverse.example.com/unknown")
+		require.NoError(t, err)
+
+		// Then
+		assert.Empty(t, urlMappings)
+	})
+}
+
+func (suite *StoreTestSuite) TestSetBatch(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	err := suite.Store.Set(ctx, domain.URLMapping{Slug: "batch-conflict", OriginalURL: "https://example.com/batch-conflict-1"})
+	require.NoError(t, err)
+	shortURLs := []domain.URLMapping{
+		{Slug: "batch-1", OriginalURL: "https://example.com/batch-1"},
+		{Slug: "batch-conflict", OriginalURL: "https://example.com/batch-conflict-2"},
+		{Slug: "batch-2", OriginalURL: "https://example.com/batch-2"},
+	}
+
+	// When
+	errs, err := suite.Store.SetBatch(ctx, shortURLs)
+	require.NoError(t, err)
+
+	// Then
+	require.Len(t, errs, len(shortURLs))
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], ErrSlugAlreadyExists)
+	assert.NoError(t, errs[2])
+	for _, shortURL := range []domain.URLMapping{shortURLs[0], shortURLs[2]} {
+		retrievedURL, err := suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, shortURL.OriginalURL, retrievedURL.OriginalURL)
+	}
+	retrievedURL, err := suite.Store.Get(ctx, "batch-conflict")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/batch-conflict-1", retrievedURL.OriginalURL)
+}
+
+func (suite *StoreTestSuite) TestGetBatch(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	expiredAt := time.Now().UTC().Add(-1 * time.Hour)
+	shortURLs := []domain.URLMapping{
+		{Slug: "getbatch-1", OriginalURL: "https://example.com/getbatch-1"},
+		{Slug: "getbatch-2", OriginalURL: "https://example.com/getbatch-2"},
+		{Slug: "getbatch-expired", OriginalURL: "https://example.com/getbatch-expired", ExpiresAt: &expiredAt},
+	}
+	for _, shortURL := range shortURLs {
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+	}
+
+	// When
+	urlMappings, errs, err := suite.Store.GetBatch(ctx, []string{"getbatch-2", "getbatch-unknown", "getbatch-expired", "getbatch-1"})
+	require.NoError(t, err)
+
+	// Then
+	require.Len(t, urlMappings, 4)
+	require.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.Equal(t, shortURLs[1].OriginalURL, urlMappings[0].OriginalURL)
+	assert.ErrorIs(t, errs[1], ErrNotFound)
+	assert.ErrorIs(t, errs[2], ErrExpired)
+	assert.NoError(t, errs[3])
+	assert.Equal(t, shortURLs[0].Slug, urlMappings[3].Slug)
+	assert.Equal(t, shortURLs[0].OriginalURL, urlMappings[3].OriginalURL)
+}
+
+func (suite *StoreTestSuite) TestSetOwnedSlug(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	shortURL := domain.URLMapping{
+		Slug:        "owned",
+		OriginalURL: "https://example.com/owned",
+		Owner:       "key-1",
+	}
+	err := suite.Store.Set(ctx, shortURL)
+	require.NoError(t, err)
+
+	t.Run("same owner", func(t *testing.T) {
+		// When
+		err := suite.Store.Set(ctx, shortURL)
+		require.NoError(t, err)
+
+		// Then
+		retrievedURL, err := suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, shortURL.Owner, retrievedURL.Owner)
+	})
+	t.Run("different owner", func(t *testing.T) {
+		// When
+		err := suite.Store.Set(ctx, domain.URLMapping{Slug: shortURL.Slug, OriginalURL: shortURL.OriginalURL, Owner: "key-2"})
+
+		// Then
+		assert.ErrorIs(t, err, ErrSlugAlreadyExists)
+		retrievedURL, err := suite.Store.Get(ctx, shortURL.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, shortURL.Owner, retrievedURL.Owner)
+	})
+	t.Run("without owner", func(t *testing.T) {
+		// When
+		err := suite.Store.Set(ctx, domain.URLMapping{Slug: shortURL.Slug, OriginalURL: shortURL.OriginalURL})
+
+		// Then
+		assert.ErrorIs(t, err, ErrSlugAlreadyExists)
+	})
+}
+
+func (suite *StoreTestSuite) TestScanSlugs(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	expiredAt := time.Now().Add(-time.Minute)
+	shortURL := domain.URLMapping{Slug: "scanned", OriginalURL: "https://example.com/scanned"}
+	shortURLExpired := domain.URLMapping{Slug: "scanned-expired", OriginalURL: "https://example.com/scanned-expired", ExpiresAt: &expiredAt}
+	require.NoError(t, suite.Store.Set(ctx, shortURL))
+	require.NoError(t, suite.Store.Set(ctx, shortURLExpired))
+	_, err := suite.Store.DeleteExpired(ctx)
+	require.NoError(t, err)
+
+	// When
+	var slugs []string
+	err = suite.Store.ScanSlugs(ctx, func(slug string) { slugs = append(slugs, slug) })
+	require.NoError(t, err)
+
+	// Then
+	assert.Contains(t, slugs, shortURL.Slug)
+	assert.Contains(t, slugs, shortURLExpired.Slug)
 }
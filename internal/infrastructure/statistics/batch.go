@@ -0,0 +1,75 @@
+package statistics
+
+import (
+	"urlShortenerService/domain"
+)
+
+// Batch represents statistics coalesced to be stored at once, the increments of a same member being summed
+// It is not thread proof
+type Batch struct {
+	urls         map[StatisticType]map[string]int // By URL
+	slugs        map[StatisticType]map[string]int // By slug
+	breakdowns   map[string]map[string]int        // By breakdown key, see breakdownKey
+	visitors     map[string]map[string]struct{}   // By URL
+	slugVisitors map[string]map[string]struct{}   // By slug
+	events       int
+}
+
+// NewBatch creates an empty batch
+func NewBatch() *Batch {
+	return &Batch{
+		urls:         map[StatisticType]map[string]int{},
+		slugs:        map[StatisticType]map[string]int{},
+		breakdowns:   map[string]map[string]int{},
+		visitors:     map[string]map[string]struct{}{},
+		slugVisitors: map[string]map[string]struct{}{},
+	}
+}
+
+// addVisitor adds the visitor to the visitors by key, creating them if needed
+func addVisitor(visitors map[string]map[string]struct{}, key string, visitor string) {
+	if visitors[key] == nil {
+		visitors[key] = map[string]struct{}{}
+	}
+	visitors[key][visitor] = struct{}{}
+}
+
+// incr increments the member of the counters by key, creating them if needed
+func incr[K comparable](counters map[K]map[string]int, key K, member string, increment int) {
+	if counters[key] == nil {
+		counters[key] = map[string]int{}
+	}
+	counters[key][member] += increment
+}
+
+// AddURLs adds a statistic of the choosen type for each URL mapping, as SetURLs stores them
+func (b *Batch) AddURLs(urlMappings []domain.URLMapping, statType StatisticType) {
+	for _, urlMapping := range urlMappings {
+		incr(b.urls, statType, urlMapping.OriginalURL, 1)
+		if urlMapping.Slug != "" {
+			incr(b.slugs, statType, urlMapping.Slug, 1)
+		}
+	}
+	b.events += len(urlMappings)
+}
+
+// AddClick adds an accessed statistic for the URL mapping along with its click, as SetClick stores them
+func (b *Batch) AddClick(urlMapping domain.URLMapping, click domain.ClickEvent) {
+	b.AddURLs([]domain.URLMapping{urlMapping}, StatisticTypeAccessed)
+	if click.Visitor != "" {
+		addVisitor(b.visitors, urlMapping.OriginalURL, click.Visitor)
+	}
+	if urlMapping.Slug != "" {
+		if click.Visitor != "" {
+			addVisitor(b.slugVisitors, urlMapping.Slug, click.Visitor)
+		}
+		for _, dimension := range dimensions {
+			incr(b.breakdowns, breakdownKey(urlMapping.Slug, dimension), dimension.valueOf(click), 1)
+		}
+	}
+}
+
+// Len returns the number of statistics added, whatever they have been coalesced into
+func (b *Batch) Len() int {
+	return b.events
+}
@@ -0,0 +1,192 @@
+package statistics
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/golang/glog"
+)
+
+// flushTimeout is the maximal duration a worker waits for a batch to be stored
+const flushTimeout = 5 * time.Second
+
+// BufferedStoreStats represents the statistics of the buffer of a buffered store, counted in statistics rather than writes
+type BufferedStoreStats struct {
+	QueueDepth    int   `json:"queue_depth"` // The writes waiting for a worker
+	QueueCapacity int   `json:"queue_capacity"`
+	Queued        int64 `json:"queued"`
+	Dropped       int64 `json:"dropped"` // The statistics lost as the queue was full, the store closed or their batch failed to be stored
+	Flushed       int64 `json:"flushed"`
+	FlushErrors   int64 `json:"flush_errors"` // The batches that failed to be stored
+}
+
+// queuedWrite represents a write waiting for a worker to add it to its batch
+type queuedWrite struct {
+	add        func(batch *Batch)
+	statistics int
+}
+
+// BufferedStore represents a store in front of another one, queuing the writes so that a pool of workers coalesces them and stores them in batches
+// The writes never block: they are dropped once the queue is full. The reads are forwarded as is, without the statistics still queued
+// The buckets of the statistics are the ones current when their batch is stored, and the clicks are enriched by the workers, off the path of the caller
+type BufferedStore struct {
+	Store
+	cfg         config.StatisticsBufferConfig
+	enrich      ClickEnricher // nil if the clicks are stored as given
+	queue       chan queuedWrite
+	closeMutex  sync.RWMutex // Prevents from queuing while closing the queue
+	closed      bool
+	workers     sync.WaitGroup
+	queued      atomic.Int64
+	dropped     atomic.Int64
+	flushed     atomic.Int64
+	flushErrors atomic.Int64
+}
+
+// NewBufferedStore creates a buffered store in front of the given store and starts its workers, Close draining them
+// The enricher is optional, nil storing the clicks as given
+func NewBufferedStore(store Store, cfg config.StatisticsBufferConfig, enrich ClickEnricher) *BufferedStore {
+	cfg.Workers = max(cfg.Workers, 1)
+	cfg.FlushSize = max(cfg.FlushSize, 1)
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	s := &BufferedStore{
+		Store:  store,
+		cfg:    cfg,
+		enrich: enrich,
+		queue:  make(chan queuedWrite, max(cfg.QueueSize, 0)),
+	}
+	s.workers.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go s.work()
+	}
+	return s
+}
+
+// SetURL implements the Store interface, queuing the statistic
+func (s *BufferedStore) SetURL(ctx context.Context, urlMapping domain.URLMapping, statType StatisticType) error {
+	return s.SetURLs(ctx, []domain.URLMapping{urlMapping}, statType)
+}
+
+// SetURLs implements the Store interface, queuing the statistics
+func (s *BufferedStore) SetURLs(ctx context.Context, urlMappings []domain.URLMapping, statType StatisticType) error {
+	s.enqueue(queuedWrite{
+		add:        func(batch *Batch) { batch.AddURLs(urlMappings, statType) },
+		statistics: len(urlMappings),
+	})
+	return nil
+}
+
+// SetClick implements the Store interface, queuing the statistic along with the click to enrich
+func (s *BufferedStore) SetClick(ctx context.Context, urlMapping domain.URLMapping, click domain.ClickEvent) error {
+	s.enqueue(queuedWrite{
+		add: func(batch *Batch) {
+			if s.enrich != nil {
+				click = s.enrich(click)
+			}
+			batch.AddClick(urlMapping, click)
+		},
+		statistics: 1,
+	})
+	return nil
+}
+
+// SetBatch implements the Store interface, the batch being already coalesced it is stored right away
+func (s *BufferedStore) SetBatch(ctx context.Context, batch *Batch) error {
+	return s.Store.SetBatch(ctx, batch)
+}
+
+// Close stops queuing, then waits for the workers to store the queued statistics
+func (s *BufferedStore) Close() error {
+	s.closeMutex.Lock()
+	if s.closed {
+		s.closeMutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.closeMutex.Unlock()
+
+	s.workers.Wait()
+	return nil
+}
+
+// Stats returns the statistics of the buffer
+func (s *BufferedStore) Stats() BufferedStoreStats {
+	return BufferedStoreStats{
+		QueueDepth:    len(s.queue),
+		QueueCapacity: cap(s.queue),
+		Queued:        s.queued.Load(),
+		Dropped:       s.dropped.Load(),
+		Flushed:       s.flushed.Load(),
+		FlushErrors:   s.flushErrors.Load(),
+	}
+}
+
+// enqueue queues the write unless the queue is full or closed
+func (s *BufferedStore) enqueue(write queuedWrite) {
+	s.closeMutex.RLock()
+	defer s.closeMutex.RUnlock()
+
+	if !s.closed {
+		select {
+		case s.queue <- write:
+			s.queued.Add(int64(write.statistics))
+			return
+		default:
+		}
+	}
+	s.dropped.Add(int64(write.statistics))
+}
+
+// work coalesces the queued writes into a batch, stored once it is big enough or old enough, until the queue is closed and drained
+func (s *BufferedStore) work() {
+	defer s.workers.Done()
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := NewBatch()
+	for {
+		select {
+		case write, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			write.add(batch)
+			if batch.Len() >= s.cfg.FlushSize {
+				s.flush(batch)
+				batch = NewBatch()
+			}
+		case <-ticker.C:
+			if batch.Len() > 0 {
+				s.flush(batch)
+				batch = NewBatch()
+			}
+		}
+	}
+}
+
+// flush stores the batch, whose statistics are dropped on failure
+func (s *BufferedStore) flush(batch *Batch) {
+	if batch.Len() == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	err := s.Store.SetBatch(ctx, batch)
+	if err != nil {
+		s.flushErrors.Add(1)
+		s.dropped.Add(int64(batch.Len()))
+		glog.Errorf("failed to flush [%d] statistics: %s", batch.Len(), err)
+		return
+	}
+	s.flushed.Add(int64(batch.Len()))
+}
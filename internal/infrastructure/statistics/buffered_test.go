@@ -0,0 +1,137 @@
+package statistics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedStore(t *testing.T) {
+	ctx := context.Background()
+	urlMapping := domain.URLMapping{OriginalURL: "https://example.com", Slug: "example"}
+
+	t.Run("coalesced and flushed once the batch is big enough", func(t *testing.T) {
+		// Given
+		inner := NewMockStore(t)
+		batches := make(chan *Batch, 1)
+		inner.On("SetBatch", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			batches <- args.Get(1).(*Batch)
+		}).Once()
+		store := NewBufferedStore(inner, config.StatisticsBufferConfig{QueueSize: 10, Workers: 1, FlushSize: 3, FlushInterval: time.Hour}, nil)
+
+		// When
+		require.NoError(t, store.SetURL(ctx, urlMapping, StatisticTypeShortened))
+		require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{}))
+		require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{}))
+
+		// Then
+		batch := <-batches
+		assert.Equal(t, 3, batch.Len())
+		assert.Equal(t, 1, batch.urls[StatisticTypeShortened][urlMapping.OriginalURL])
+		assert.Equal(t, 2, batch.urls[StatisticTypeAccessed][urlMapping.OriginalURL])
+		require.NoError(t, store.Close())
+		assert.Equal(t, BufferedStoreStats{QueueCapacity: 10, Queued: 3, Flushed: 3}, store.Stats())
+	})
+
+	t.Run("flushed once the batch is old enough", func(t *testing.T) {
+		// Given
+		inner := NewMemoryStore(10, testStatisticsConfig)
+		store := NewBufferedStore(inner, config.StatisticsBufferConfig{QueueSize: 10, Workers: 1, FlushSize: 100, FlushInterval: 10 * time.Millisecond}, nil)
+		defer store.Close()
+
+		// When
+		require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{}))
+
+		// Then
+		assert.Eventually(t, func() bool {
+			stats, err := store.GetURL(ctx, urlMapping.OriginalURL)
+			return err == nil && stats.AccessedCounter == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("drained on close", func(t *testing.T) {
+		// Given
+		inner := NewMemoryStore(10, testStatisticsConfig)
+		store := NewBufferedStore(inner, config.StatisticsBufferConfig{QueueSize: 10, Workers: 2, FlushSize: 100, FlushInterval: time.Hour}, nil)
+		for i := 0; i < 5; i++ {
+			require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{Referrer: "example.org"}))
+		}
+
+		// When
+		require.NoError(t, store.Close())
+
+		// Then
+		stats, err := store.GetURL(ctx, urlMapping.OriginalURL)
+		require.NoError(t, err)
+		assert.Equal(t, 5, stats.AccessedCounter)
+		referrers, err := store.GetClickBreakdown(ctx, urlMapping.Slug, DimensionReferrer, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ClickBreakdown{{Value: "example.org", Counter: 5}}, referrers)
+		require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{}))
+		assert.Equal(t, int64(1), store.Stats().Dropped)
+	})
+
+	t.Run("clicks enriched by the workers", func(t *testing.T) {
+		// Given
+		inner := NewMemoryStore(10, testStatisticsConfig)
+		release := make(chan struct{})
+		store := NewBufferedStore(inner, config.StatisticsBufferConfig{QueueSize: 10, Workers: 1, FlushSize: 100, FlushInterval: time.Hour}, func(click domain.ClickEvent) domain.ClickEvent {
+			<-release
+			click.Country = "FR"
+			return click
+		})
+
+		// When
+		require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{IP: "82.64.1.1"})) // Not waiting for the enricher
+		close(release)
+		require.NoError(t, store.Close())
+
+		// Then
+		countries, err := store.GetClickBreakdown(ctx, urlMapping.Slug, DimensionCountry, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ClickBreakdown{{Value: "FR", Counter: 1}}, countries)
+	})
+	t.Run("dropped when the queue is full", func(t *testing.T) {
+		// Given
+		inner := NewMockStore(t)
+		release := make(chan struct{})
+		inner.On("SetBatch", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			<-release
+		}).Twice()
+		store := NewBufferedStore(inner, config.StatisticsBufferConfig{QueueSize: 1, Workers: 1, FlushSize: 1, FlushInterval: time.Hour}, nil)
+		require.NoError(t, store.SetURL(ctx, urlMapping, StatisticTypeShortened))
+		require.Eventually(t, func() bool { return store.Stats().QueueDepth == 0 }, time.Second, time.Millisecond)
+		require.NoError(t, store.SetURL(ctx, urlMapping, StatisticTypeShortened))
+
+		// When
+		err := store.SetURLs(ctx, []domain.URLMapping{urlMapping, urlMapping}, StatisticTypeShortened)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, BufferedStoreStats{QueueDepth: 1, QueueCapacity: 1, Queued: 2, Dropped: 2}, store.Stats())
+		close(release)
+		require.NoError(t, store.Close())
+		assert.Equal(t, int64(2), store.Stats().Flushed)
+	})
+
+	t.Run("flush error", func(t *testing.T) {
+		// Given
+		inner := NewMockStore(t)
+		inner.On("SetBatch", mock.Anything, mock.Anything).Return(errors.New("unavailable")).Once()
+		store := NewBufferedStore(inner, config.StatisticsBufferConfig{QueueSize: 10, Workers: 1, FlushSize: 100, FlushInterval: time.Hour}, nil)
+		require.NoError(t, store.SetClick(ctx, urlMapping, domain.ClickEvent{}))
+
+		// When
+		require.NoError(t, store.Close())
+
+		// Then
+		assert.Equal(t, BufferedStoreStats{QueueCapacity: 10, Queued: 1, Dropped: 1, FlushErrors: 1}, store.Stats())
+	})
+}
@@ -0,0 +1,23 @@
+package statistics
+
+import (
+	"context"
+	"urlShortenerService/domain"
+)
+
+// EnrichedStore represents a store in front of another one, enriching the clicks before storing them
+// The clicks are enriched by the caller, the buffered store enriches them within its workers instead
+type EnrichedStore struct {
+	Store
+	enrich ClickEnricher
+}
+
+// NewEnrichedStore creates an enriched store in front of the given store
+func NewEnrichedStore(store Store, enrich ClickEnricher) *EnrichedStore {
+	return &EnrichedStore{Store: store, enrich: enrich}
+}
+
+// SetClick implements the Store interface, enriching the click
+func (s *EnrichedStore) SetClick(ctx context.Context, urlMapping domain.URLMapping, click domain.ClickEvent) error {
+	return s.Store.SetClick(ctx, urlMapping, s.enrich(click))
+}
@@ -0,0 +1,27 @@
+package statistics
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichedStore(t *testing.T) {
+	// Given
+	urlMapping := domain.URLMapping{OriginalURL: "https://example.com", Slug: "example"}
+	inner := NewMockStore(t)
+	inner.On("SetClick", mock.Anything, urlMapping, domain.ClickEvent{IP: "82.64.1.1", Country: "FR"}).Return(nil)
+	store := NewEnrichedStore(inner, func(click domain.ClickEvent) domain.ClickEvent {
+		click.Country = "FR"
+		return click
+	})
+
+	// When
+	err := store.SetClick(context.Background(), urlMapping, domain.ClickEvent{IP: "82.64.1.1"})
+
+	// Then
+	require.NoError(t, err)
+}
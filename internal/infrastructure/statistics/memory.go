@@ -0,0 +1,260 @@
+package statistics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+)
+
+// MemoryStore represents an in memory store, meant for development as nothing is persisted
+type MemoryStore struct {
+	mutex             sync.RWMutex
+	counters          map[StatisticType]map[string]int
+	slugCounters      map[StatisticType]map[string]int
+	bucketCounters    map[string]map[string]int      // By bucket key, see bucketKey
+	bucketExpiries    map[string]time.Time           // The date each bucket is dropped, once its retention is over
+	breakdownCounters map[string]map[string]int      // By breakdown key, see breakdownKey
+	visitors          map[string]map[string]struct{} // By visitors key, the exact visitors in place of an HyperLogLog
+	maxResults        int64
+	statsConf         config.StatisticsConfig
+}
+
+// NewMemoryStore creates an empty in memory store, the top statistics holding up to maxResults URLs by default
+func NewMemoryStore(maxResults int, statsConf config.StatisticsConfig) *MemoryStore {
+	return &MemoryStore{
+		counters:          map[StatisticType]map[string]int{},
+		slugCounters:      map[StatisticType]map[string]int{},
+		bucketCounters:    map[string]map[string]int{},
+		bucketExpiries:    map[string]time.Time{},
+		breakdownCounters: map[string]map[string]int{},
+		visitors:          map[string]map[string]struct{}{},
+		maxResults:        int64(maxResults),
+		statsConf:         statsConf,
+	}
+}
+
+// GetURL implements the Store interface
+func (s *MemoryStore) GetURL(ctx context.Context, url string) (domain.URLStatistic, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return domain.URLStatistic{
+		URL:              url,
+		ShortenedCounter: s.counters[StatisticTypeShortened][url],
+		AccessedCounter:  s.counters[StatisticTypeAccessed][url],
+		UniqueVisitors:   len(s.visitors[urlVisitorsKey(url)]),
+	}, nil
+}
+
+// GetSlug implements the Store interface
+func (s *MemoryStore) GetSlug(ctx context.Context, slug string) (domain.URLStatistic, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return domain.URLStatistic{
+		Slug:             slug,
+		ShortenedCounter: s.slugCounters[StatisticTypeShortened][slug],
+		AccessedCounter:  s.slugCounters[StatisticTypeAccessed][slug],
+		UniqueVisitors:   len(s.visitors[slugVisitorsKey(slug)]),
+	}, nil
+}
+
+// GetURLTimeSeries implements the Store interface
+func (s *MemoryStore) GetURLTimeSeries(ctx context.Context, url string, granularity Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error) {
+	if granularity.Duration() == 0 {
+		return nil, ErrUnknownGranularity
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	points := []domain.URLStatisticPoint{}
+	for _, bucket := range granularity.Buckets(from, to) {
+		points = append(points, domain.URLStatisticPoint{
+			Time:             bucket,
+			ShortenedCounter: s.bucketCounters[bucketKey(StatisticTypeShortened, granularity, bucket)][url],
+			AccessedCounter:  s.bucketCounters[bucketKey(StatisticTypeAccessed, granularity, bucket)][url],
+			UniqueVisitors:   len(s.visitors[bucketVisitorsKey(url, granularity, bucket)]),
+		})
+	}
+	return points, nil
+}
+
+// GetTopURLs implements the Store interface
+// The URLs are sorted by counter then by URL, both descending, as within a Redis sorted set
+func (s *MemoryStore) GetTopURLs(ctx context.Context, statType StatisticType, limitOveride int64) ([]domain.URLStatistic, error) {
+	var limit = s.maxResults
+	if limitOveride != 0 {
+		limit = limitOveride
+	}
+
+	s.mutex.RLock()
+	var stats []domain.URLStatistic
+	for url, counter := range s.counters[statType] {
+		switch statType {
+		case StatisticTypeShortened:
+			stats = append(stats, domain.URLStatistic{URL: url, ShortenedCounter: counter})
+		case StatisticTypeAccessed:
+			stats = append(stats, domain.URLStatistic{URL: url, AccessedCounter: counter})
+		}
+	}
+	s.mutex.RUnlock()
+
+	counterOf := func(stat domain.URLStatistic) int { return stat.ShortenedCounter + stat.AccessedCounter }
+	sort.Slice(stats, func(i, j int) bool {
+		if counterOf(stats[i]) != counterOf(stats[j]) {
+			return counterOf(stats[i]) > counterOf(stats[j])
+		}
+		return stats[i].URL > stats[j].URL
+	})
+	if int64(len(stats)) > limit {
+		stats = stats[:limit]
+	}
+	return stats, nil
+}
+
+// SetURL implements the Store interface
+func (s *MemoryStore) SetURL(ctx context.Context, urlMapping domain.URLMapping, statType StatisticType) error {
+	return s.SetURLs(ctx, []domain.URLMapping{urlMapping}, statType)
+}
+
+// SetURLs implements the Store interface
+func (s *MemoryStore) SetURLs(ctx context.Context, urlMappings []domain.URLMapping, statType StatisticType) error {
+	batch := NewBatch()
+	batch.AddURLs(urlMappings, statType)
+	return s.SetBatch(ctx, batch)
+}
+
+// SetClick implements the Store interface
+func (s *MemoryStore) SetClick(ctx context.Context, urlMapping domain.URLMapping, click domain.ClickEvent) error {
+	batch := NewBatch()
+	batch.AddClick(urlMapping, click)
+	return s.SetBatch(ctx, batch)
+}
+
+// SetBatch implements the Store interface
+func (s *MemoryStore) SetBatch(ctx context.Context, batch *Batch) error {
+	now := time.Now()
+	buckets := currentBuckets(s.statsConf, now)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for statType, urls := range batch.urls {
+		for url, increment := range urls {
+			incr(s.counters, statType, url, increment)
+		}
+		for _, bucket := range buckets {
+			key := bucketKey(statType, bucket.granularity, bucket.start)
+			if s.bucketCounters[key] == nil {
+				// A new bucket starts, the ones whose retention is over are dropped meanwhile
+				s.dropExpiredBuckets(now)
+				s.bucketExpiries[key] = bucket.expiry
+			}
+			for url, increment := range urls {
+				incr(s.bucketCounters, key, url, increment)
+			}
+		}
+	}
+	for statType, slugs := range batch.slugs {
+		for slug, increment := range slugs {
+			incr(s.slugCounters, statType, slug, increment)
+		}
+	}
+	for key, values := range batch.breakdowns {
+		for value, increment := range values {
+			incr(s.breakdownCounters, key, value, increment)
+		}
+		s.trimBreakdown(key)
+	}
+	for url, visitors := range batch.visitors {
+		keys := []string{urlVisitorsKey(url)}
+		for _, bucket := range buckets {
+			key := bucketVisitorsKey(url, bucket.granularity, bucket.start)
+			if s.visitors[key] == nil {
+				s.bucketExpiries[key] = bucket.expiry
+			}
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			if s.visitors[key] == nil {
+				s.visitors[key] = map[string]struct{}{}
+			}
+			for visitor := range visitors {
+				s.visitors[key][visitor] = struct{}{}
+			}
+		}
+	}
+	for slug, visitors := range batch.slugVisitors {
+		for visitor := range visitors {
+			addVisitor(s.visitors, slugVisitorsKey(slug), visitor)
+		}
+	}
+	return nil
+}
+
+// trimBreakdown drops the least clicked values of the breakdown beyond the maximal number of values, as the Redis store does
+func (s *MemoryStore) trimBreakdown(key string) {
+	counters := s.breakdownCounters[key]
+	excess := len(counters) - s.statsConf.BreakdownMaxValues
+	if s.statsConf.BreakdownMaxValues <= 0 || excess <= 0 {
+		return
+	}
+	values := make([]string, 0, len(counters))
+	for value := range counters {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counters[values[i]] != counters[values[j]] {
+			return counters[values[i]] < counters[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	for _, value := range values[:excess] {
+		delete(counters, value)
+	}
+}
+
+// GetClickBreakdown implements the Store interface
+// The values are sorted by counter then by value, both descending, as within a Redis sorted set
+func (s *MemoryStore) GetClickBreakdown(ctx context.Context, slug string, dimension Dimension, limitOveride int64) ([]domain.ClickBreakdown, error) {
+	if !dimension.Valid() {
+		return nil, ErrUnknownDimension
+	}
+	var limit = s.maxResults
+	if limitOveride != 0 {
+		limit = limitOveride
+	}
+
+	s.mutex.RLock()
+	breakdowns := []domain.ClickBreakdown{}
+	for value, counter := range s.breakdownCounters[breakdownKey(slug, dimension)] {
+		breakdowns = append(breakdowns, domain.ClickBreakdown{Value: value, Counter: counter})
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(breakdowns, func(i, j int) bool {
+		if breakdowns[i].Counter != breakdowns[j].Counter {
+			return breakdowns[i].Counter > breakdowns[j].Counter
+		}
+		return breakdowns[i].Value > breakdowns[j].Value
+	})
+	if int64(len(breakdowns)) > limit {
+		breakdowns = breakdowns[:limit]
+	}
+	return breakdowns, nil
+}
+
+// dropExpiredBuckets drops the buckets whose retention is over, as Redis would expire them
+func (s *MemoryStore) dropExpiredBuckets(now time.Time) {
+	for key, expiry := range s.bucketExpiries {
+		if !now.Before(expiry) {
+			delete(s.bucketCounters, key)
+			delete(s.visitors, key)
+			delete(s.bucketExpiries, key)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package statistics
+
+import (
+	"context"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(10, testStatisticsConfig)
+
+	RunStoreTests(t, store)
+}
+
+func TestMemoryStoreDropExpiredBuckets(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	url := "https://example.com"
+	store := NewMemoryStore(10, testStatisticsConfig)
+	require.NoError(t, store.SetClick(ctx, domain.URLMapping{OriginalURL: url}, domain.ClickEvent{Visitor: "visitor-1"}))
+
+	// When
+	store.dropExpiredBuckets(time.Now().Add(testStatisticsConfig.HourlyRetention + time.Hour))
+
+	// Then
+	hours, err := store.GetURLTimeSeries(ctx, url, GranularityHour, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	for _, point := range hours {
+		assert.Zero(t, point.AccessedCounter)
+		assert.Zero(t, point.UniqueVisitors)
+	}
+	days, err := store.GetURLTimeSeries(ctx, url, GranularityDay, time.Now(), time.Now().Add(time.Nanosecond))
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, 1, days[0].AccessedCounter)
+	assert.Equal(t, 1, days[0].UniqueVisitors)
+	stats, err := store.GetURL(ctx, url)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.AccessedCounter)
+	assert.Equal(t, 1, stats.UniqueVisitors)
+}
@@ -6,6 +6,8 @@ import (
 	context "context"
 	domain "urlShortenerService/domain"
 
+	time "time"
+
 	mock "github.com/stretchr/testify/mock"
 
 )
@@ -53,6 +55,56 @@ func (_m *MockStore) GetURL(ctx context.Context, url string) (domain.URLStatisti
 	return r0, r1
 }
 
+// GetSlug provides a mock function with given fields: ctx, slug
+func (_m *MockStore) GetSlug(ctx context.Context, slug string) (domain.URLStatistic, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 domain.URLStatistic
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.URLStatistic, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.URLStatistic); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.URLStatistic)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetURLTimeSeries provides a mock function with given fields: ctx, url, granularity, from, to
+func (_m *MockStore) GetURLTimeSeries(ctx context.Context, url string, granularity Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error) {
+	ret := _m.Called(ctx, url, granularity, from, to)
+
+	var r0 []domain.URLStatisticPoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, Granularity, time.Time, time.Time) ([]domain.URLStatisticPoint, error)); ok {
+		return rf(ctx, url, granularity, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, Granularity, time.Time, time.Time) []domain.URLStatisticPoint); ok {
+		r0 = rf(ctx, url, granularity, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.URLStatisticPoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, Granularity, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, url, granularity, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTopURLs provides a mock function with given fields: ctx, statType
 func (_m *MockStore) GetTopURLs(ctx context.Context, statType StatisticType, limitOveride int64) ([]domain.URLStatistic, error) {
 	ret := _m.Called(ctx, statType, limitOveride)
@@ -79,13 +131,81 @@ func (_m *MockStore) GetTopURLs(ctx context.Context, statType StatisticType, lim
 	return r0, r1
 }
 
-// SetURL provides a mock function with given fields: ctx, url, statType
-func (_m *MockStore) SetURL(ctx context.Context, url string, statType StatisticType) error {
-	ret := _m.Called(ctx, url, statType)
+// SetURL provides a mock function with given fields: ctx, urlMapping, statType
+func (_m *MockStore) SetURL(ctx context.Context, urlMapping domain.URLMapping, statType StatisticType) error {
+	ret := _m.Called(ctx, urlMapping, statType)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.URLMapping, StatisticType) error); ok {
+		r0 = rf(ctx, urlMapping, statType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetURLs provides a mock function with given fields: ctx, urlMappings, statType
+func (_m *MockStore) SetURLs(ctx context.Context, urlMappings []domain.URLMapping, statType StatisticType) error {
+	ret := _m.Called(ctx, urlMappings, statType)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.URLMapping, StatisticType) error); ok {
+		r0 = rf(ctx, urlMappings, statType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetClickBreakdown provides a mock function with given fields: ctx, slug, dimension, limitOveride
+func (_m *MockStore) GetClickBreakdown(ctx context.Context, slug string, dimension Dimension, limitOveride int64) ([]domain.ClickBreakdown, error) {
+	ret := _m.Called(ctx, slug, dimension, limitOveride)
+
+	var r0 []domain.ClickBreakdown
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, Dimension, int64) ([]domain.ClickBreakdown, error)); ok {
+		return rf(ctx, slug, dimension, limitOveride)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, Dimension, int64) []domain.ClickBreakdown); ok {
+		r0 = rf(ctx, slug, dimension, limitOveride)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ClickBreakdown)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, Dimension, int64) error); ok {
+		r1 = rf(ctx, slug, dimension, limitOveride)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetClick provides a mock function with given fields: ctx, urlMapping, click
+func (_m *MockStore) SetClick(ctx context.Context, urlMapping domain.URLMapping, click domain.ClickEvent) error {
+	ret := _m.Called(ctx, urlMapping, click)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.URLMapping, domain.ClickEvent) error); ok {
+		r0 = rf(ctx, urlMapping, click)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetBatch provides a mock function with given fields: ctx, batch
+func (_m *MockStore) SetBatch(ctx context.Context, batch *Batch) error {
+	ret := _m.Called(ctx, batch)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, StatisticType) error); ok {
-		r0 = rf(ctx, url, statType)
+	if rf, ok := ret.Get(0).(func(context.Context, *Batch) error); ok {
+		r0 = rf(ctx, batch)
 	} else {
 		r0 = ret.Error(0)
 	}
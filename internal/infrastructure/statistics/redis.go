@@ -3,22 +3,22 @@ package statistics
 import (
 	"context"
 	"fmt"
-	"sync"
+	"time"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/infrastructure/config"
 
 	"github.com/go-redis/redis/v8"
 )
 
-// RedisStore represents a redis store thread proof
+// RedisStore represents a redis store thread proof, the client and its pipelines needing no lock
 type RedisStore struct {
 	client     *redis.Client
-	mutex      sync.RWMutex
 	maxResults int64
+	statsConf  config.StatisticsConfig
 }
 
-// NewRedisStore connects to a redis and return it inside a RedisStore
-func NewRedisStore(cfg config.RedisConfig) (*RedisStore, error) {
+// NewRedisStore connects to a redis and return it inside a RedisStore, the buckets of time expiring after their retention
+func NewRedisStore(cfg config.RedisConfig, statsConf config.StatisticsConfig) (*RedisStore, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr: cfg.ToAddr(),
 	})
@@ -30,16 +30,13 @@ func NewRedisStore(cfg config.RedisConfig) (*RedisStore, error) {
 
 	return &RedisStore{
 		client:     client,
-		mutex:      sync.RWMutex{},
 		maxResults: int64(cfg.MaxResults),
+		statsConf:  statsConf,
 	}, nil
 }
 
 // GetURL implements the Store interface
 func (s *RedisStore) GetURL(ctx context.Context, url string) (domain.URLStatistic, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
 	shortened, err := s.client.ZScore(ctx, string(StatisticTypeShortened), url).Result()
 	if err != nil && err != redis.Nil {
 		return domain.URLStatistic{}, fmt.Errorf("failed to get [%s] stats for URL [%s]: %w", StatisticTypeShortened, url, err)
@@ -50,13 +47,91 @@ func (s *RedisStore) GetURL(ctx context.Context, url string) (domain.URLStatisti
 		return domain.URLStatistic{}, fmt.Errorf("failed to get [%s] stats for URL [%s]: %w", StatisticTypeAccessed, url, err)
 	}
 
+	visitors, err := s.client.PFCount(ctx, urlVisitorsKey(url)).Result()
+	if err != nil {
+		return domain.URLStatistic{}, fmt.Errorf("failed to get unique visitors for URL [%s]: %w", url, err)
+	}
+
 	return domain.URLStatistic{
 		URL:              url,
 		ShortenedCounter: int(shortened),
 		AccessedCounter:  int(accessed),
+		UniqueVisitors:   int(visitors),
+	}, nil
+}
+
+// GetSlug implements the Store interface
+func (s *RedisStore) GetSlug(ctx context.Context, slug string) (domain.URLStatistic, error) {
+	shortened, err := s.client.ZScore(ctx, StatisticTypeShortened.slugKey(), slug).Result()
+	if err != nil && err != redis.Nil {
+		return domain.URLStatistic{}, fmt.Errorf("failed to get [%s] stats for slug [%s]: %w", StatisticTypeShortened, slug, err)
+	}
+
+	accessed, err := s.client.ZScore(ctx, StatisticTypeAccessed.slugKey(), slug).Result()
+	if err != nil && err != redis.Nil {
+		return domain.URLStatistic{}, fmt.Errorf("failed to get [%s] stats for slug [%s]: %w", StatisticTypeAccessed, slug, err)
+	}
+
+	visitors, err := s.client.PFCount(ctx, slugVisitorsKey(slug)).Result()
+	if err != nil {
+		return domain.URLStatistic{}, fmt.Errorf("failed to get unique visitors for slug [%s]: %w", slug, err)
+	}
+
+	return domain.URLStatistic{
+		Slug:             slug,
+		ShortenedCounter: int(shortened),
+		AccessedCounter:  int(accessed),
+		UniqueVisitors:   int(visitors),
 	}, nil
 }
 
+// GetURLTimeSeries implements the Store interface
+// The counters of all the buckets are retrieved within a single pipeline
+func (s *RedisStore) GetURLTimeSeries(ctx context.Context, url string, granularity Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error) {
+	if granularity.Duration() == 0 {
+		return nil, ErrUnknownGranularity
+	}
+	buckets := granularity.Buckets(from, to)
+	if len(buckets) == 0 {
+		return []domain.URLStatisticPoint{}, nil
+	}
+
+	shortenedCmds := make([]*redis.FloatCmd, len(buckets))
+	accessedCmds := make([]*redis.FloatCmd, len(buckets))
+	visitorsCmds := make([]*redis.IntCmd, len(buckets))
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, bucket := range buckets {
+			shortenedCmds[i] = pipe.ZScore(ctx, bucketKey(StatisticTypeShortened, granularity, bucket), url)
+			accessedCmds[i] = pipe.ZScore(ctx, bucketKey(StatisticTypeAccessed, granularity, bucket), url)
+			visitorsCmds[i] = pipe.PFCount(ctx, bucketVisitorsKey(url, granularity, bucket))
+		}
+		return nil
+	})
+	// The buckets without statistic for the URL answer redis.Nil
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get [%s] time series for URL [%s]: %w", granularity, url, err)
+	}
+
+	points := make([]domain.URLStatisticPoint, len(buckets))
+	for i, bucket := range buckets {
+		for _, cmd := range []*redis.FloatCmd{shortenedCmds[i], accessedCmds[i]} {
+			if cmd.Err() != nil && cmd.Err() != redis.Nil {
+				return nil, fmt.Errorf("failed to get [%s] time series for URL [%s]: %w", granularity, url, cmd.Err())
+			}
+		}
+		if visitorsCmds[i].Err() != nil {
+			return nil, fmt.Errorf("failed to get [%s] time series for URL [%s]: %w", granularity, url, visitorsCmds[i].Err())
+		}
+		points[i] = domain.URLStatisticPoint{
+			Time:             bucket,
+			ShortenedCounter: int(shortenedCmds[i].Val()),
+			AccessedCounter:  int(accessedCmds[i].Val()),
+			UniqueVisitors:   int(visitorsCmds[i].Val()),
+		}
+	}
+	return points, nil
+}
+
 // GetTopURLs implements the Store interface
 func (s *RedisStore) GetTopURLs(ctx context.Context, statType StatisticType, limitOveride int64) ([]domain.URLStatistic, error) {
 	var limit = s.maxResults
@@ -64,13 +139,10 @@ func (s *RedisStore) GetTopURLs(ctx context.Context, statType StatisticType, lim
 		limit = limitOveride
 	}
 
-	s.mutex.RLock()
 	zSlice, err := s.client.ZRevRangeWithScores(ctx, string(statType), 0, limit-1).Result()
 	if err != nil {
-		s.mutex.RUnlock()
 		return nil, fmt.Errorf("failed to get [%s] top stats: %w", statType, err)
 	}
-	s.mutex.RUnlock()
 
 	var stats []domain.URLStatistic
 	for _, z := range zSlice {
@@ -92,14 +164,111 @@ func (s *RedisStore) GetTopURLs(ctx context.Context, statType StatisticType, lim
 }
 
 // SetURL implements the Store interface
-func (s *RedisStore) SetURL(ctx context.Context, url string, statType StatisticType) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+func (s *RedisStore) SetURL(ctx context.Context, urlMapping domain.URLMapping, statType StatisticType) error {
+	return s.SetURLs(ctx, []domain.URLMapping{urlMapping}, statType)
+}
+
+// SetURLs implements the Store interface
+func (s *RedisStore) SetURLs(ctx context.Context, urlMappings []domain.URLMapping, statType StatisticType) error {
+	batch := NewBatch()
+	batch.AddURLs(urlMappings, statType)
+	return s.SetBatch(ctx, batch)
+}
+
+// SetClick implements the Store interface
+func (s *RedisStore) SetClick(ctx context.Context, urlMapping domain.URLMapping, click domain.ClickEvent) error {
+	batch := NewBatch()
+	batch.AddClick(urlMapping, click)
+	return s.SetBatch(ctx, batch)
+}
 
-	_, err := s.client.ZIncrBy(ctx, string(statType), 1, url).Result()
+// SetBatch implements the Store interface
+// All the increments are sent within a single pipeline, each bucket expiring once its retention is over
+func (s *RedisStore) SetBatch(ctx context.Context, batch *Batch) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+	buckets := currentBuckets(s.statsConf, time.Now())
+
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for statType, urls := range batch.urls {
+			for url, increment := range urls {
+				pipe.ZIncrBy(ctx, string(statType), float64(increment), url)
+			}
+			for _, bucket := range buckets {
+				key := bucketKey(statType, bucket.granularity, bucket.start)
+				for url, increment := range urls {
+					pipe.ZIncrBy(ctx, key, float64(increment), url)
+				}
+				pipe.ExpireAt(ctx, key, bucket.expiry)
+			}
+		}
+		for statType, slugs := range batch.slugs {
+			for slug, increment := range slugs {
+				pipe.ZIncrBy(ctx, statType.slugKey(), float64(increment), slug)
+			}
+		}
+		for key, values := range batch.breakdowns {
+			for value, increment := range values {
+				pipe.ZIncrBy(ctx, key, float64(increment), value)
+			}
+			// Only the most clicked values are kept, and the breakdowns of a slug not clicked anymore are dropped along with its daily buckets
+			if s.statsConf.BreakdownMaxValues > 0 {
+				pipe.ZRemRangeByRank(ctx, key, 0, -int64(s.statsConf.BreakdownMaxValues)-1)
+			}
+			if s.statsConf.DailyRetention > 0 {
+				pipe.Expire(ctx, key, s.statsConf.DailyRetention)
+			}
+		}
+		for url, visitors := range batch.visitors {
+			members := make([]interface{}, 0, len(visitors))
+			for visitor := range visitors {
+				members = append(members, visitor)
+			}
+			pipe.PFAdd(ctx, urlVisitorsKey(url), members...)
+			for _, bucket := range buckets {
+				key := bucketVisitorsKey(url, bucket.granularity, bucket.start)
+				pipe.PFAdd(ctx, key, members...)
+				pipe.ExpireAt(ctx, key, bucket.expiry)
+			}
+		}
+		for slug, visitors := range batch.slugVisitors {
+			members := make([]interface{}, 0, len(visitors))
+			for visitor := range visitors {
+				members = append(members, visitor)
+			}
+			pipe.PFAdd(ctx, slugVisitorsKey(slug), members...)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to set [%s] stat for URL [%s]: %w", statType, url, err)
+		return fmt.Errorf("failed to set [%d] stats: %w", batch.Len(), err)
 	}
 
 	return nil
 }
+
+// GetClickBreakdown implements the Store interface
+func (s *RedisStore) GetClickBreakdown(ctx context.Context, slug string, dimension Dimension, limitOveride int64) ([]domain.ClickBreakdown, error) {
+	if !dimension.Valid() {
+		return nil, ErrUnknownDimension
+	}
+	var limit = s.maxResults
+	if limitOveride != 0 {
+		limit = limitOveride
+	}
+
+	zSlice, err := s.client.ZRevRangeWithScores(ctx, breakdownKey(slug, dimension), 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get [%s] breakdown for slug [%s]: %w", dimension, slug, err)
+	}
+
+	breakdowns := []domain.ClickBreakdown{}
+	for _, z := range zSlice {
+		breakdowns = append(breakdowns, domain.ClickBreakdown{
+			Value:   z.Member.(string),
+			Counter: int(z.Score),
+		})
+	}
+	return breakdowns, nil
+}
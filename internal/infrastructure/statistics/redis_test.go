@@ -1,21 +1,84 @@
 package statistics
 
 import (
+	"context"
+	"reflect"
 	"strconv"
+	"sync"
 	"testing"
+	"unsafe"
+	"urlShortenerService/domain"
 	"urlShortenerService/internal/infrastructure/config"
 
 	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// registerHyperLogLogCommands registers PFADD and PFCOUNT, not supported by miniredis, counting the elements exactly
+// The HyperLogLog counts are exact as well at such low cardinalities
+func registerHyperLogLogCommands(t *testing.T, mr *miniredis.Miniredis) {
+	// The server of miniredis is not exposed by this version
+	srv := (*server.Server)(unsafe.Pointer(reflect.ValueOf(mr).Elem().FieldByName("srv").Pointer()))
+
+	var mutex sync.Mutex
+	sets := map[string]map[string]struct{}{}
+	require.NoError(t, srv.Register("PFADD", func(c *server.Peer, cmd string, args []string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if sets[args[0]] == nil {
+			sets[args[0]] = map[string]struct{}{}
+		}
+		added := 0
+		for _, element := range args[1:] {
+			if _, ok := sets[args[0]][element]; !ok {
+				sets[args[0]][element] = struct{}{}
+				added = 1
+			}
+		}
+		c.WriteInt(added)
+	}))
+	require.NoError(t, srv.Register("PFCOUNT", func(c *server.Peer, cmd string, args []string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		union := map[string]struct{}{}
+		for _, key := range args {
+			for element := range sets[key] {
+				union[element] = struct{}{}
+			}
+		}
+		c.WriteInt(len(union))
+	}))
+}
+
 func TestRedisStore(t *testing.T) {
 	mr, err := miniredis.Run()
 	require.NoError(t, err)
+	registerHyperLogLogCommands(t, mr)
 	port, err := strconv.Atoi(mr.Port())
 	require.NoError(t, err)
-	store, err := NewRedisStore(config.RedisConfig{Host: mr.Host(), Port: port, MaxResults: 10})
+	store, err := NewRedisStore(config.RedisConfig{Host: mr.Host(), Port: port, MaxResults: 10}, testStatisticsConfig)
 	require.NoError(t, err)
 
 	RunStoreTests(t, store)
 }
+
+func TestRedisStoreBreakdownRetention(t *testing.T) {
+	// Given
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+	store, err := NewRedisStore(config.RedisConfig{Host: mr.Host(), Port: port, MaxResults: 10}, testStatisticsConfig)
+	require.NoError(t, err)
+	urlMapping := domain.URLMapping{Slug: "retention", OriginalURL: "https://example.com/retention"}
+
+	// When
+	err = store.SetClick(context.Background(), urlMapping, domain.ClickEvent{Referrer: "example.org"})
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, testStatisticsConfig.DailyRetention, mr.TTL(breakdownKey(urlMapping.Slug, DimensionReferrer)))
+}
@@ -2,7 +2,19 @@ package statistics
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
+)
+
+var (
+	// ErrUnknownGranularity is the error when the granularity of a time series is unknown
+	ErrUnknownGranularity error = errors.New("unknown granularity")
+	// ErrUnknownDimension is the error when the dimension of a click breakdown is unknown
+	ErrUnknownDimension error = errors.New("unknown dimension")
 )
 
 type StatisticType string
@@ -12,12 +24,189 @@ var (
 	StatisticTypeAccessed  StatisticType = "urls-accessed"
 )
 
+// slugKey returns the key of the statistics of the choosen type by slug, the default keys holding them by URL
+func (t StatisticType) slugKey() string {
+	return string(t) + ":by-slug"
+}
+
+// Granularity is the duration of the buckets the statistics are recorded in
+type Granularity string
+
+var (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+// granularities are all the granularities the statistics are recorded with
+var granularities = []Granularity{GranularityHour, GranularityDay}
+
+// Duration returns the duration of a bucket, 0 if the granularity is unknown
+func (g Granularity) Duration() time.Duration {
+	switch g {
+	case GranularityHour:
+		return time.Hour
+	case GranularityDay:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Truncate returns the start of the bucket holding t, the days starting at midnight UTC
+func (g Granularity) Truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(g.Duration())
+}
+
+// Buckets returns the start of the buckets from the one holding from up to the one holding to excluded
+func (g Granularity) Buckets(from time.Time, to time.Time) []time.Time {
+	var buckets []time.Time
+	for bucket := g.Truncate(from); bucket.Before(to); bucket = bucket.Add(g.Duration()) {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// retentionOf returns the duration the buckets of the granularity are kept, 0 meaning not recorded
+func retentionOf(statsConf config.StatisticsConfig, granularity Granularity) time.Duration {
+	switch granularity {
+	case GranularityHour:
+		return statsConf.HourlyRetention
+	case GranularityDay:
+		return statsConf.DailyRetention
+	default:
+		return 0
+	}
+}
+
+// recordedBucket represents the bucket holding now of a granularity the statistics are recorded with
+type recordedBucket struct {
+	granularity Granularity
+	start       time.Time
+	expiry      time.Time // The date the bucket is dropped, once its retention is over
+}
+
+// currentBuckets returns the bucket holding now of each granularity whose retention is not 0
+func currentBuckets(statsConf config.StatisticsConfig, now time.Time) []recordedBucket {
+	var buckets []recordedBucket
+	for _, granularity := range granularities {
+		retention := retentionOf(statsConf, granularity)
+		if retention == 0 {
+			continue
+		}
+		start := granularity.Truncate(now)
+		buckets = append(buckets, recordedBucket{
+			granularity: granularity,
+			start:       start,
+			expiry:      start.Add(granularity.Duration() + retention),
+		})
+	}
+	return buckets
+}
+
+// bucketKey returns the key of the statistics of the choosen type recorded within a bucket
+func bucketKey(statType StatisticType, granularity Granularity, bucket time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", statType, granularity, bucket.Unix())
+}
+
+// visitorsKey prefixes the keys of the unique visitors of the URLs, a HyperLogLog being kept per URL
+const visitorsKey = "urls-visitors"
+
+// urlVisitorsKey returns the key of the all time unique visitors of the URL
+func urlVisitorsKey(url string) string {
+	return fmt.Sprintf("%s:%s", visitorsKey, url)
+}
+
+// slugVisitorsKey returns the key of the all time unique visitors of the slug
+func slugVisitorsKey(slug string) string {
+	return fmt.Sprintf("%s:by-slug:%s", visitorsKey, slug)
+}
+
+// bucketVisitorsKey returns the key of the unique visitors of the URL within a bucket
+func bucketVisitorsKey(url string, granularity Granularity, bucket time.Time) string {
+	return fmt.Sprintf("%s:%s:%d:%s", visitorsKey, granularity, bucket.Unix(), url)
+}
+
+// Dimension is a property of the clicks they are broken down by per slug
+type Dimension string
+
+var (
+	DimensionReferrer Dimension = "referrers"
+	DimensionCountry  Dimension = "countries"
+	DimensionBrowser  Dimension = "browsers"
+	DimensionOS       Dimension = "os"
+	DimensionDevice   Dimension = "devices"
+)
+
+// dimensions are all the dimensions the clicks are broken down by
+var dimensions = []Dimension{DimensionReferrer, DimensionCountry, DimensionBrowser, DimensionOS, DimensionDevice}
+
+const (
+	// directReferrer is the referrer of the clicks without one
+	directReferrer = "direct"
+	// unknownValue is the value of the other dimensions of the clicks without one
+	unknownValue = "unknown"
+)
+
+// Valid returns whether the dimension is known
+func (d Dimension) Valid() bool {
+	return slices.Contains(dimensions, d)
+}
+
+// valueOf returns the value of the dimension for the click, the missing values being grouped together
+func (d Dimension) valueOf(click domain.ClickEvent) string {
+	var value string
+	switch d {
+	case DimensionReferrer:
+		if click.Referrer == "" {
+			return directReferrer
+		}
+		return click.Referrer
+	case DimensionCountry:
+		value = click.Country
+	case DimensionBrowser:
+		value = click.Browser
+	case DimensionOS:
+		value = click.OS
+	case DimensionDevice:
+		value = string(click.Device)
+	}
+	if value == "" {
+		return unknownValue
+	}
+	return value
+}
+
+// breakdownKey returns the key of the clicks of the slug broken down by the dimension
+func breakdownKey(slug string, dimension Dimension) string {
+	return fmt.Sprintf("%s:%s:%s", StatisticTypeAccessed.slugKey(), slug, dimension)
+}
+
+// ClickEnricher fills the fields of a click derived from its request fields, such as its visitor or its country
+type ClickEnricher func(click domain.ClickEvent) domain.ClickEvent
+
 // Store represents operations on statistics Store
 type Store interface {
-	// GetURL retrieves the statistic for a single URL
+	// GetURL retrieves the statistic for a single URL, all its slugs together, along with its approximate unique visitors
 	GetURL(ctx context.Context, url string) (domain.URLStatistic, error)
+	// GetSlug retrieves the statistic for a single slug, whatever the URLs it pointed to, along with its approximate unique visitors
+	GetSlug(ctx context.Context, slug string) (domain.URLStatistic, error)
+	// GetURLTimeSeries retrieves the statistic for a single URL per bucket of the granularity, from the one holding from up to the one holding to excluded
+	// The buckets older than the retention of the granularity are empty, it returns ErrUnknownGranularity if the granularity is unknown
+	// The unique visitors are counted within each bucket, a visitor coming back in the next one being counted again
+	GetURLTimeSeries(ctx context.Context, url string, granularity Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error)
 	// GetTopURLs retrieves top statistic of the choosen type for the URLs
 	GetTopURLs(ctx context.Context, statType StatisticType, limitOveride int64) ([]domain.URLStatistic, error)
-	// SetURL stores the statistic of the choosen type for the associated URL
-	SetURL(ctx context.Context, url string, statType StatisticType) error
+	// SetURL stores the statistic of the choosen type for the URL of the mapping, all time and within the current bucket of each granularity
+	// It is stored all time for the slug of the mapping as well, if any
+	SetURL(ctx context.Context, urlMapping domain.URLMapping, statType StatisticType) error
+	// SetURLs stores the statistic of the choosen type for several URL mappings at once
+	SetURLs(ctx context.Context, urlMappings []domain.URLMapping, statType StatisticType) error
+	// GetClickBreakdown retrieves the top values of the dimension among the clicks of the slug
+	// It returns ErrUnknownDimension if the dimension is unknown
+	GetClickBreakdown(ctx context.Context, slug string, dimension Dimension, limitOveride int64) ([]domain.ClickBreakdown, error)
+	// SetClick stores an accessed statistic for the URL mapping as SetURL does, along with the click broken down by each dimension for the slug of the mapping
+	// The visitor of the click, if any, is counted as an unique visitor of the URL all time and within the current bucket of each granularity, and of the slug all time
+	SetClick(ctx context.Context, urlMapping domain.URLMapping, click domain.ClickEvent) error
+	// SetBatch stores the statistics coalesced within the batch at once, the buckets being the current ones whenever the statistics were added
+	SetBatch(ctx context.Context, batch *Batch) error
 }
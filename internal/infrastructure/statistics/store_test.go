@@ -3,12 +3,17 @@ package statistics
 import (
 	context "context"
 	"testing"
+	"time"
 	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/config"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// testStatisticsConfig records the statistics with every granularity
+var testStatisticsConfig = config.StatisticsConfig{HourlyRetention: 24 * time.Hour, DailyRetention: 30 * 24 * time.Hour, BreakdownMaxValues: 3}
+
 type StoreTestSuite struct {
 	Store
 }
@@ -19,6 +24,11 @@ func RunStoreTests(t *testing.T, store Store) {
 	t.Run("TestSetURL", suite.TestSetURL)
 	t.Run("TestGetURL", suite.TestGetURL)
 	t.Run("TestGetTopURLs", suite.TestGetTopURLs)
+	t.Run("TestSetURLs", suite.TestSetURLs)
+	t.Run("TestGetURLTimeSeries", suite.TestGetURLTimeSeries)
+	t.Run("TestGetSlug", suite.TestGetSlug)
+	t.Run("TestGetClickBreakdown", suite.TestGetClickBreakdown)
+	t.Run("TestUniqueVisitors", suite.TestUniqueVisitors)
 }
 
 func (suite *StoreTestSuite) TestSetURL(t *testing.T) {
@@ -27,11 +37,11 @@ func (suite *StoreTestSuite) TestSetURL(t *testing.T) {
 	url := "https://example.com/set-test"
 
 	// When
-	err := suite.Store.SetURL(ctx, url, StatisticTypeAccessed)
+	err := suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeAccessed)
 	require.NoError(t, err)
-	err = suite.Store.SetURL(ctx, url, StatisticTypeAccessed)
+	err = suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeAccessed)
 	require.NoError(t, err)
-	err = suite.Store.SetURL(ctx, url, StatisticTypeShortened)
+	err = suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeShortened)
 	require.NoError(t, err)
 
 	// Then
@@ -46,9 +56,9 @@ func (suite *StoreTestSuite) TestGetURL(t *testing.T) {
 	// Given
 	ctx := context.Background()
 	url := "https://example.com/getone-test"
-	err := suite.Store.SetURL(ctx, url, StatisticTypeAccessed)
+	err := suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeAccessed)
 	require.NoError(t, err)
-	err = suite.Store.SetURL(ctx, url, StatisticTypeShortened)
+	err = suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeShortened)
 	require.NoError(t, err)
 
 	// When
@@ -72,7 +82,7 @@ func (suite *StoreTestSuite) TestGetTopURLs(t *testing.T) {
 		}
 		for _, stat := range expectedStats {
 			for i := 0; i < stat.AccessedCounter; i++ {
-				err := suite.Store.SetURL(ctx, stat.URL, StatisticTypeAccessed)
+				err := suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: stat.URL}, StatisticTypeAccessed)
 				require.NoError(t, err)
 			}
 		}
@@ -85,3 +95,227 @@ func (suite *StoreTestSuite) TestGetTopURLs(t *testing.T) {
 		assert.Equal(t, expectedStats, stats)
 	})
 }
+
+func (suite *StoreTestSuite) TestSetURLs(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	urls := []string{"https://example.com/setmany-test-1", "https://example.com/setmany-test-2", "https://example.com/setmany-test-1"}
+	urlMappings := []domain.URLMapping{{OriginalURL: urls[0]}, {OriginalURL: urls[1]}, {OriginalURL: urls[2]}}
+
+	// When
+	err := suite.Store.SetURLs(ctx, urlMappings, StatisticTypeShortened)
+	require.NoError(t, err)
+
+	// Then
+	stats, err := suite.Store.GetURL(ctx, urls[0])
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.ShortenedCounter)
+	stats, err = suite.Store.GetURL(ctx, urls[1])
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.ShortenedCounter)
+}
+
+func (suite *StoreTestSuite) TestGetURLTimeSeries(t *testing.T) {
+	for _, granularity := range []Granularity{GranularityHour, GranularityDay} {
+		t.Run(string(granularity), func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			url := "https://example.com/timeseries-test-" + string(granularity)
+			from := time.Now().Add(-2 * granularity.Duration())
+			to := time.Now().Add(2 * granularity.Duration())
+			require.NoError(t, suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeAccessed))
+			require.NoError(t, suite.Store.SetURLs(ctx, []domain.URLMapping{{OriginalURL: url}, {OriginalURL: url}}, StatisticTypeAccessed))
+			require.NoError(t, suite.Store.SetURL(ctx, domain.URLMapping{OriginalURL: url}, StatisticTypeShortened))
+
+			// When
+			points, err := suite.Store.GetURLTimeSeries(ctx, url, granularity, from, to)
+			require.NoError(t, err)
+
+			// Then
+			buckets := granularity.Buckets(from, to)
+			require.Len(t, points, len(buckets))
+			var accessed, shortened int
+			for i, point := range points {
+				assert.Equal(t, buckets[i], point.Time)
+				accessed += point.AccessedCounter
+				shortened += point.ShortenedCounter
+			}
+			// The hour may have turned between the statistics, hence the sum
+			assert.Equal(t, 3, accessed)
+			assert.Equal(t, 1, shortened)
+		})
+	}
+	t.Run("unknown granularity", func(t *testing.T) {
+		// When
+		points, err := suite.Store.GetURLTimeSeries(context.Background(), "https://example.com", Granularity("week"), time.Now().Add(-time.Hour), time.Now())
+
+		// Then
+		require.ErrorIs(t, err, ErrUnknownGranularity)
+		assert.Empty(t, points)
+	})
+}
+
+func (suite *StoreTestSuite) TestGetSlug(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	url := "https://example.com/getslug-test"
+	err := suite.Store.SetURL(ctx, domain.URLMapping{Slug: "getslug-vanity", OriginalURL: url}, StatisticTypeShortened)
+	require.NoError(t, err)
+	err = suite.Store.SetURLs(ctx, []domain.URLMapping{
+		{Slug: "getslug-vanity", OriginalURL: url},
+		{Slug: "getslug-campaign", OriginalURL: url},
+		{Slug: "getslug-vanity", OriginalURL: url},
+	}, StatisticTypeAccessed)
+	require.NoError(t, err)
+
+	// When
+	vanityStats, err := suite.Store.GetSlug(ctx, "getslug-vanity")
+	require.NoError(t, err)
+	campaignStats, err := suite.Store.GetSlug(ctx, "getslug-campaign")
+	require.NoError(t, err)
+	unknownStats, err := suite.Store.GetSlug(ctx, "getslug-unknown")
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, domain.URLStatistic{Slug: "getslug-vanity", ShortenedCounter: 1, AccessedCounter: 2}, vanityStats)
+	assert.Equal(t, domain.URLStatistic{Slug: "getslug-campaign", AccessedCounter: 1}, campaignStats)
+	assert.Equal(t, domain.URLStatistic{Slug: "getslug-unknown"}, unknownStats)
+	urlStats, err := suite.Store.GetURL(ctx, url)
+	require.NoError(t, err)
+	assert.Equal(t, 1, urlStats.ShortenedCounter)
+	assert.Equal(t, 3, urlStats.AccessedCounter)
+}
+
+func (suite *StoreTestSuite) TestGetClickBreakdown(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		url := "https://example.com/breakdown-test"
+		urlMapping := domain.URLMapping{Slug: "breakdown-vanity", OriginalURL: url}
+		clicks := []domain.ClickEvent{
+			{Referrer: "news.ycombinator.com", Browser: "Firefox", OS: "Linux", Device: domain.DeviceDesktop, Country: "FR"},
+			{Referrer: "news.ycombinator.com", Browser: "Safari", OS: "iOS", Device: domain.DeviceMobile, Country: "FR"},
+			{Browser: "Safari", OS: "iOS", Device: domain.DeviceMobile, Country: "US"},
+			{Referrer: "t.co"},
+		}
+		for _, click := range clicks {
+			err := suite.Store.SetClick(ctx, urlMapping, click)
+			require.NoError(t, err)
+		}
+
+		// When
+		referrers, err := suite.Store.GetClickBreakdown(ctx, urlMapping.Slug, DimensionReferrer, 0)
+		require.NoError(t, err)
+		countries, err := suite.Store.GetClickBreakdown(ctx, urlMapping.Slug, DimensionCountry, 2)
+		require.NoError(t, err)
+		devices, err := suite.Store.GetClickBreakdown(ctx, urlMapping.Slug, DimensionDevice, 0)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []domain.ClickBreakdown{{Value: "news.ycombinator.com", Counter: 2}, {Value: "t.co", Counter: 1}, {Value: "direct", Counter: 1}}, referrers)
+		assert.Equal(t, []domain.ClickBreakdown{{Value: "FR", Counter: 2}, {Value: "unknown", Counter: 1}}, countries)
+		assert.Equal(t, []domain.ClickBreakdown{{Value: "mobile", Counter: 2}, {Value: "unknown", Counter: 1}, {Value: "desktop", Counter: 1}}, devices)
+		slugStats, err := suite.Store.GetSlug(ctx, urlMapping.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, len(clicks), slugStats.AccessedCounter)
+		urlStats, err := suite.Store.GetURL(ctx, url)
+		require.NoError(t, err)
+		assert.Equal(t, len(clicks), urlStats.AccessedCounter)
+	})
+	t.Run("least clicked values dropped", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+		urlMapping := domain.URLMapping{Slug: "breakdown-capped", OriginalURL: "https://example.com/breakdown-capped"}
+		for _, referrer := range []string{"a.example", "a.example", "a.example", "b.example", "b.example", "c.example", "d.example", "e.example"} {
+			err := suite.Store.SetClick(ctx, urlMapping, domain.ClickEvent{Referrer: referrer})
+			require.NoError(t, err)
+		}
+
+		// When
+		referrers, err := suite.Store.GetClickBreakdown(ctx, urlMapping.Slug, DimensionReferrer, 10)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ClickBreakdown{{Value: "a.example", Counter: 3}, {Value: "b.example", Counter: 2}, {Value: "e.example", Counter: 1}}, referrers)
+	})
+	t.Run("no click", func(t *testing.T) {
+		// When
+		breakdowns, err := suite.Store.GetClickBreakdown(context.Background(), "breakdown-unknown", DimensionBrowser, 0)
+
+		// Then
+		require.NoError(t, err)
+		assert.Empty(t, breakdowns)
+	})
+	t.Run("unknown dimension", func(t *testing.T) {
+		// When
+		breakdowns, err := suite.Store.GetClickBreakdown(context.Background(), "breakdown-vanity", Dimension("languages"), 0)
+
+		// Then
+		require.ErrorIs(t, err, ErrUnknownDimension)
+		assert.Empty(t, breakdowns)
+	})
+}
+
+func (suite *StoreTestSuite) TestUniqueVisitors(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	url := "https://example.com/visitors-test"
+	clicks := []struct {
+		Slug    string
+		Visitor string
+	}{
+		{Slug: "visitors-vanity", Visitor: "visitor-1"},
+		{Slug: "visitors-vanity", Visitor: "visitor-1"},
+		{Slug: "visitors-campaign", Visitor: "visitor-1"},
+		{Slug: "visitors-campaign", Visitor: "visitor-2"},
+		{Slug: "visitors-campaign"},
+	}
+	for _, click := range clicks {
+		err := suite.Store.SetClick(ctx, domain.URLMapping{Slug: click.Slug, OriginalURL: url}, domain.ClickEvent{Visitor: click.Visitor})
+		require.NoError(t, err)
+	}
+
+	// When
+	stats, err := suite.Store.GetURL(ctx, url)
+	require.NoError(t, err)
+	points, err := suite.Store.GetURLTimeSeries(ctx, url, GranularityDay, time.Now(), time.Now().Add(time.Nanosecond))
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, len(clicks), stats.AccessedCounter)
+	assert.Equal(t, 2, stats.UniqueVisitors)
+	require.Len(t, points, 1)
+	assert.Equal(t, len(clicks), points[0].AccessedCounter)
+	assert.Equal(t, 2, points[0].UniqueVisitors)
+	vanityStats, err := suite.Store.GetSlug(ctx, "visitors-vanity")
+	require.NoError(t, err)
+	assert.Equal(t, 1, vanityStats.UniqueVisitors)
+	campaignStats, err := suite.Store.GetSlug(ctx, "visitors-campaign")
+	require.NoError(t, err)
+	assert.Equal(t, 2, campaignStats.UniqueVisitors)
+	unknownStats, err := suite.Store.GetURL(ctx, "https://example.com/visitors-unknown")
+	require.NoError(t, err)
+	assert.Zero(t, unknownStats.UniqueVisitors)
+}
+
+func TestGranularityBuckets(t *testing.T) {
+	// Given
+	from := time.Date(2024, 3, 1, 22, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC)
+
+	// When
+	hours := GranularityHour.Buckets(from, to)
+	days := GranularityDay.Buckets(from, to)
+
+	// Then
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	}, hours)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	}, days)
+	assert.Empty(t, GranularityHour.Buckets(to, from))
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// bearerPrefix prefixes the API key within the Authorization header
+const bearerPrefix string = "Bearer "
+
+// forbiddenAPIError is the API error returned when the role of the API key does not allow an action
+var forbiddenAPIError ApiError = ApiError{
+	Name:        "forbidden",
+	Description: "the role of the API key does not allow this action",
+	Hint:        "viewers can only read statistics, editors can also create links and manage their own links, admins can do everything",
+}
+
+// WithAPIKeyAuthentication requires an API key on the routes registered afterwards as protected
+// It should be called before registering the handlers
+func (b *Builder) WithAPIKeyAuthentication(cmd usecase.AuthenticateAPIKeyCmd) *Builder {
+	b.authMiddleware = apiKeyAuthenticationMiddleware(cmd)
+	return b
+}
+
+// protected returns the handlers chain of a route requiring an API key, when the authentication is enabled
+func (b *Builder) protected(handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	if b.authMiddleware == nil {
+		return handlers
+	}
+	return append([]gin.HandlerFunc{b.authMiddleware}, handlers...)
+}
+
+// apiKeyAuthenticationMiddleware authenticates the API key of the Authorization header and stores it within the request context
+func apiKeyAuthenticationMiddleware(cmd usecase.AuthenticateAPIKeyCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		secret, hasBearerPrefix := strings.CutPrefix(authorization, bearerPrefix)
+		if !hasBearerPrefix {
+			secret = ""
+		}
+
+		apiKey, err := cmd(c.Request.Context(), strings.TrimSpace(secret))
+		switch err {
+		case nil:
+			c.Request = c.Request.WithContext(domain.ContextWithAPIKey(c.Request.Context(), apiKey))
+			c.Next()
+			return
+		case usecase.ErrInvalidAPIKey:
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, CreateAPIError(ApiError{
+				Name:        "unauthorized",
+				Description: "missing or invalid API key",
+				Hint:        "set the Authorization header to 'Bearer <API key>'",
+			}, err))
+			return
+		default:
+			glog.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAPIKeyAuthentication(t *testing.T) {
+	apiKey := domain.APIKey{ID: "key-1", Name: "marketing"}
+	mockCmd := func(err error) usecase.AuthenticateAPIKeyCmd {
+		return func(ctx context.Context, secret string) (domain.APIKey, error) {
+			if err != nil {
+				return domain.APIKey{}, err
+			}
+			if secret != "usk_secret" {
+				return domain.APIKey{}, usecase.ErrInvalidAPIKey
+			}
+			return apiKey, nil
+		}
+	}
+	buildRouter := func(cmd usecase.AuthenticateAPIKeyCmd) *gin.Engine {
+		b := NewBuilder(domain.EnvTest)
+		if cmd != nil {
+			b = b.WithAPIKeyAuthentication(cmd)
+		}
+		b.router.GET("/protected", b.protected(func(c *gin.Context) {
+			authenticatedAPIKey, _ := domain.APIKeyFromContext(c.Request.Context())
+			c.String(http.StatusOK, authenticatedAPIKey.ID)
+		})...)
+		return b.router
+	}
+
+	t.Run("authenticated", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer usk_secret")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Equal(t, apiKey.ID, record.Body.String())
+	})
+	t.Run("unauthorized without API key", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/protected", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, record.Code)
+		assert.Equal(t, "Bearer", record.Header().Get("WWW-Authenticate"))
+	})
+	t.Run("unauthorized without bearer prefix", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "usk_secret")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, record.Code)
+	})
+	t.Run("unauthorized with unknown API key", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer usk_unknown")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(assert.AnError))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer usk_secret")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+	t.Run("authentication disabled", func(t *testing.T) {
+		// Given
+		router := buildRouter(nil)
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/protected", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+	})
+}
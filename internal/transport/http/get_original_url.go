@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 	"strconv"
+	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
 	"urlShortenerService/internal/infrastructure/malwarescanner"
 	"urlShortenerService/internal/infrastructure/shorturl"
@@ -19,7 +20,7 @@ type GetOriginalURLResponse struct {
 
 // WithGetOriginalURLHandler register the get original URL API in the router of the HTTP builder
 func (b *Builder) WithGetOriginalURLHandler(cmd usecase.GetOriginalURLCmd) *Builder {
-	b.router.GET("/:slug", getOriginalURLHandler(cmd))
+	b.router.GET("/:slug", b.rateLimited(usecase.RateLimitRouteRedirect, getOriginalURLHandler(cmd))...)
 	return b
 }
 
@@ -37,7 +38,14 @@ func getOriginalURLHandler(cmd usecase.GetOriginalURLCmd) gin.HandlerFunc {
 			}
 		}
 
-		originalURL, err := cmd(c.Request.Context(), slug)
+		// Capture the click, enriched and recorded within the statistics
+		click := domain.ClickEvent{
+			Referrer:  c.Request.Referer(),
+			UserAgent: c.Request.UserAgent(),
+			IP:        c.ClientIP(),
+		}
+
+		originalURL, err := cmd(c.Request.Context(), slug, click)
 		switch err {
 		case nil:
 			if redirect {
@@ -57,14 +65,28 @@ func getOriginalURLHandler(cmd usecase.GetOriginalURLCmd) gin.HandlerFunc {
 			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
 				Name:        "not_found",
 				Description: "no URL found associated to the given slug",
-				Hint:        "the slug might be incorrect or expired",
+				Hint:        "the slug might be incorrect",
+			}, err))
+			return
+		case shorturl.ErrExpired:
+			c.JSON(http.StatusGone, CreateAPIError(ApiError{
+				Name:        "gone",
+				Description: "the URL associated to the given slug has expired",
+				Hint:        "ask the owner of the link for a new one",
+			}, err))
+			return
+		case usecase.ErrLinkDisabled:
+			c.JSON(http.StatusGone, CreateAPIError(ApiError{
+				Name:        "disabled",
+				Description: "the URL associated to the given slug has been disabled",
+				Hint:        "ask the owner of the link for a new one",
 			}, err))
 			return
 		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
 			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
 				Name:        "unprocessable_entity",
 				Description: "the given slug is invalid",
-				Hint:        "the slug should be alpha numeric and less than the configuration setted maximal lenght",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
 			}, err))
 			return
 		default:
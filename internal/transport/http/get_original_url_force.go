@@ -9,7 +9,7 @@ import (
 
 // WithGetOriginalURLForceHandler register the force get original URL API in the router of the HTTP builder
 func (b *Builder) WithGetOriginalURLForceHandler(cmd usecase.GetOriginalURLCmd) *Builder {
-	b.router.GET("/:slug/force", cleanForceURLPath(), getOriginalURLHandler(cmd))
+	b.router.GET("/:slug/force", b.rateLimited(usecase.RateLimitRouteRedirect, cleanForceURLPath(), getOriginalURLHandler(cmd))...)
 	return b
 }
 
@@ -21,7 +21,7 @@ func TestWithGetOriginalURLForceHandler(t *testing.T) {
 	originalURL := "https://my-very-long-url.com/needs-to-be-shortened"
 	slug := "zTw34enA"
 	mockCmd := func(err error) usecase.GetOriginalURLCmd {
-		return func(ctx context.Context, s string) (string, error) {
+		return func(ctx context.Context, s string, click domain.ClickEvent) (string, error) {
 			assert.Equal(t, slug, s)
 			return originalURL, err
 		}
@@ -21,7 +21,7 @@ func TestWithGetOriginalURLHandler(t *testing.T) {
 	originalURL := "https://my-very-long-url.com/needs-to-be-shortened"
 	slug := "zTw34enA"
 	mockCmd := func(err error) usecase.GetOriginalURLCmd {
-		return func(ctx context.Context, s string) (string, error) {
+		return func(ctx context.Context, s string, click domain.ClickEvent) (string, error) {
 			assert.Equal(t, slug, s)
 			return originalURL, err
 		}
@@ -44,6 +44,30 @@ func TestWithGetOriginalURLHandler(t *testing.T) {
 		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
 		assert.Equal(t, originalURL, bodyResponse.OriginalURL)
 	})
+	t.Run("click captured", func(t *testing.T) {
+		// Given
+		var capturedClick domain.ClickEvent
+		cmd := func(ctx context.Context, s string, click domain.ClickEvent) (string, error) {
+			capturedClick = click
+			return originalURL, nil
+		}
+		router := NewBuilder(domain.EnvTest).WithGetOriginalURLHandler(cmd).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", fmt.Sprintf("/%s", slug), nil)
+		req.Header.Set("Referer", "https://news.ycombinator.com/item?id=1")
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Equal(t, domain.ClickEvent{
+			Referrer:  "https://news.ycombinator.com/item?id=1",
+			UserAgent: "curl/8.4.0",
+			IP:        "192.0.2.1",
+		}, capturedClick)
+	})
 	t.Run("redirection asked", func(t *testing.T) {
 		// Given
 		router := NewBuilder(domain.EnvTest).WithGetOriginalURLHandler(mockCmd(nil)).router
@@ -109,6 +133,38 @@ func TestWithGetOriginalURLHandler(t *testing.T) {
 		// Then
 		assert.Equal(t, http.StatusNotFound, record.Code)
 	})
+	t.Run("gone", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithGetOriginalURLHandler(mockCmd(shorturl.ErrExpired)).router
+		u, err := url.Parse(fmt.Sprintf("/%s?redirect=true", slug))
+		require.NoError(t, err)
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusGone, record.Code)
+		bodyResponse := ApiError{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, "gone", bodyResponse.Name)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithGetOriginalURLHandler(mockCmd(usecase.ErrLinkDisabled)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", fmt.Sprintf("/%s?redirect=true", slug), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusGone, record.Code)
+		bodyResponse := ApiError{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, "disabled", bodyResponse.Name)
+	})
 	t.Run("unprocessable entity", func(t *testing.T) {
 		t.Run("invalid slug lenght", func(t *testing.T) {
 			// Given
@@ -11,7 +11,9 @@ const pathPrefixV1 = "/api/url-shortener/v1"
 
 // Builder holds the gin Engine
 type Builder struct {
-	router *gin.Engine
+	router            *gin.Engine
+	authMiddleware    gin.HandlerFunc           // Nil when the authentication is disabled
+	checkRateLimitCmd usecase.CheckRateLimitCmd // Nil when the rate limiting is disabled
 }
 
 // NewBuilder creates a Builder
@@ -25,22 +27,60 @@ func NewBuilder(env domain.Environment) *Builder {
 		gin.SetMode(gin.DebugMode)
 	}
 
+	// No proxy is trusted until WithTrustedProxies, so that a client can't spoof its IP with the X-Forwarded-For header
+	router := gin.Default()
+	_ = router.SetTrustedProxies(nil) // Can't fail without proxy
+
 	return &Builder{
-		router: gin.Default(),
+		router: router,
+	}
+}
+
+// WithTrustedProxies trusts the X-Forwarded-For header set by the given proxies (IPs or CIDRs) to get the client IP
+// It returns an error if a proxy can't be parsed
+func (b *Builder) WithTrustedProxies(proxies []string) (*Builder, error) {
+	err := b.router.SetTrustedProxies(proxies)
+	if err != nil {
+		return nil, err
 	}
+	return b, nil
 }
 
 // BuildRouter builds the gin Engine router
-func (b *Builder) BuildRouter(createShortenURLCmd usecase.CreateShortenURLCmd, getOriginalURLCmd usecase.GetOriginalURLCmd,
+// The API key authentication is disabled when authenticateAPIKeyCmd is nil and the rate limiting when checkRateLimitCmd is nil
+func (b *Builder) BuildRouter(authenticateAPIKeyCmd usecase.AuthenticateAPIKeyCmd, checkRateLimitCmd usecase.CheckRateLimitCmd, authorizeCmd usecase.AuthorizeCmd, createAPIKeyCmd usecase.CreateAPIKeyCmd,
+	createShortenURLCmd usecase.CreateShortenURLCmd, createShortenURLsCmd usecase.CreateShortenURLsCmd, getOriginalURLCmd usecase.GetOriginalURLCmd,
 	forceGetOriginalURLCmd usecase.GetOriginalURLCmd, getStatisticsForURLCmd usecase.GetStatisticsForURLCmd,
-	getTopStatisticsCmd usecase.GetTopStatisticsCmd) *gin.Engine {
+	getStatisticsForSlugCmd usecase.GetStatisticsForSlugCmd, getClickBreakdownCmd usecase.GetClickBreakdownCmd, getStatisticsTimeSeriesCmd usecase.GetStatisticsTimeSeriesCmd, getTopStatisticsCmd usecase.GetTopStatisticsCmd, getLinkCmd usecase.GetLinkCmd, updateLinkCmd usecase.UpdateLinkCmd,
+	deleteLinkCmd usecase.DeleteLinkCmd, disableLinkCmd usecase.DisableLinkCmd, purgeLinkCmd usecase.PurgeLinkCmd, listLinksCmd usecase.ListLinksCmd,
+	lookupLinksCmd usecase.LookupLinksCmd, resolveSlugsCmd usecase.ResolveSlugsCmd) *gin.Engine {
+	if authenticateAPIKeyCmd != nil {
+		b.WithAPIKeyAuthentication(authenticateAPIKeyCmd)
+	}
+	if checkRateLimitCmd != nil {
+		b.WithRateLimiting(checkRateLimitCmd)
+	}
 	return b.
 		WithSwaggerHandler().
 		WithV1HealthHandler().
+		WithV1MetricsHandler(authorizeCmd).
+		WithV1CreateAPIKeyHandler(createAPIKeyCmd).
 		WithV1CreateShortenURLHandler(createShortenURLCmd).
+		WithV1CreateShortenURLsHandler(createShortenURLsCmd).
 		WithGetOriginalURLHandler(getOriginalURLCmd).
 		WithGetOriginalURLForceHandler(forceGetOriginalURLCmd).
+		WithV1ResolveSlugsHandler(resolveSlugsCmd).
 		WithGetStatisticsForURLHandler(getStatisticsForURLCmd).
+		WithGetStatisticsForSlugHandler(getStatisticsForSlugCmd).
+		WithGetClickBreakdownHandler(getClickBreakdownCmd).
+		WithGetStatisticsTimeSeriesHandler(getStatisticsTimeSeriesCmd).
 		WithGetTopStatisticsHandler(getTopStatisticsCmd).
+		WithV1GetLinkHandler(getLinkCmd).
+		WithV1UpdateLinkHandler(updateLinkCmd).
+		WithV1DeleteLinkHandler(deleteLinkCmd).
+		WithV1DisableLinkHandler(disableLinkCmd).
+		WithV1PurgeLinkHandler(purgeLinkCmd).
+		WithV1ListLinksHandler(listLinksCmd).
+		WithV1LookupLinksHandler(lookupLinksCmd).
 		router
 }
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"urlShortenerService/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTrustedProxies(t *testing.T) {
+	clientIPOf := func(b *Builder, forwardedFor string) string {
+		var clientIP string
+		b.router.GET("/client-ip", func(c *gin.Context) {
+			clientIP = c.ClientIP()
+			c.Status(http.StatusOK)
+		})
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/client-ip", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		b.router.ServeHTTP(record, req)
+		return clientIP
+	}
+
+	t.Run("none by default", func(t *testing.T) {
+		// Given
+		b := NewBuilder(domain.EnvTest)
+
+		// When
+		clientIP := clientIPOf(b, "203.0.113.9")
+
+		// Then
+		assert.Equal(t, "192.0.2.1", clientIP)
+	})
+	t.Run("forwarded by a trusted proxy", func(t *testing.T) {
+		// Given
+		b, err := NewBuilder(domain.EnvTest).WithTrustedProxies([]string{"192.0.2.0/24"})
+		require.NoError(t, err)
+
+		// When
+		clientIP := clientIPOf(b, "203.0.113.9")
+
+		// Then
+		assert.Equal(t, "203.0.113.9", clientIP)
+	})
+	t.Run("invalid proxy", func(t *testing.T) {
+		// When
+		_, err := NewBuilder(domain.EnvTest).WithTrustedProxies([]string{"not-an-ip"})
+
+		// Then
+		assert.Error(t, err)
+	})
+}
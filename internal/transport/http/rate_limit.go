@@ -0,0 +1,83 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"urlShortenerService/internal/infrastructure/ratelimit"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// WithRateLimiting limits the requests of the routes registered afterwards as rate limited
+// It should be called before registering the handlers
+func (b *Builder) WithRateLimiting(cmd usecase.CheckRateLimitCmd) *Builder {
+	b.checkRateLimitCmd = cmd
+	return b
+}
+
+// rateLimited returns the handlers chain of a public route limited per client IP by the rate limit of the given route group, when the rate limiting is enabled
+func (b *Builder) rateLimited(route usecase.RateLimitRoute, handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	return b.rateLimitedBy(route, usecase.RateLimitScopeIP, handlers...)
+}
+
+// protectedRateLimited returns the handlers chain of a protected route limited by the rate limit of the given route group, when the rate limiting is enabled
+// The client IP is limited before the authentication, so that the requests with a missing or invalid API key are throttled too, and the API key after it
+func (b *Builder) protectedRateLimited(route usecase.RateLimitRoute, handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	return b.rateLimitedBy(route, usecase.RateLimitScopeIP, b.protected(b.rateLimitedBy(route, usecase.RateLimitScopeAPIKey, handlers...)...)...)
+}
+
+// rateLimitedBy returns the handlers chain of a route limited within the given scope, when the rate limiting is enabled
+func (b *Builder) rateLimitedBy(route usecase.RateLimitRoute, scope usecase.RateLimitScope, handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	if b.checkRateLimitCmd == nil {
+		return handlers
+	}
+	return append([]gin.HandlerFunc{rateLimitMiddleware(route, scope, b.checkRateLimitCmd)}, handlers...)
+}
+
+// seconds rounds up a duration in seconds as expected by the rate limit headers
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// setRateLimitHeaders sets the RateLimit-* headers informing the client of its rate limit
+// When the request is limited within several scopes, the headers tell the most restrictive one
+func setRateLimitHeaders(c *gin.Context, result ratelimit.Result) {
+	remaining, err := strconv.Atoi(c.Writer.Header().Get("RateLimit-Remaining"))
+	if err == nil && remaining < result.Remaining {
+		return
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", seconds(result.ResetAfter))
+}
+
+// rateLimitMiddleware consumes one request of the client against the rate limit of the route within the scope and rejects it once throttled
+// If the rate limit can't be checked, the error is logged and the request is let through
+func rateLimitMiddleware(route usecase.RateLimitRoute, scope usecase.RateLimitScope, cmd usecase.CheckRateLimitCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := cmd(c.Request.Context(), route, scope, c.ClientIP())
+		if err != nil {
+			glog.Warningf("failed to check [%s] rate limit by %s, request let through: %s", route, scope, err)
+			c.Next()
+			return
+		}
+
+		if result.Limit > 0 {
+			setRateLimitHeaders(c, result)
+		}
+		if !result.Allowed {
+			c.Header("Retry-After", seconds(result.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, CreateAPIError(ApiError{
+				Name:        "too_many_requests",
+				Description: "too many requests, the rate limit is exceeded",
+				Hint:        "wait for the number of seconds given by the Retry-After header before retrying",
+			}, nil))
+			return
+		}
+		c.Next()
+	}
+}
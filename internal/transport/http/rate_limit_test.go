@@ -0,0 +1,216 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/ratelimit"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRateLimiting(t *testing.T) {
+	mockCmd := func(result ratelimit.Result, err error) usecase.CheckRateLimitCmd {
+		return func(ctx context.Context, route usecase.RateLimitRoute, scope usecase.RateLimitScope, clientIP string) (ratelimit.Result, error) {
+			assert.Equal(t, usecase.RateLimitRouteShorten, route)
+			assert.Equal(t, usecase.RateLimitScopeIP, scope)
+			assert.Equal(t, "192.0.2.1", clientIP)
+			return result, err
+		}
+	}
+	buildRouter := func(cmd usecase.CheckRateLimitCmd) *gin.Engine {
+		b := NewBuilder(domain.EnvTest)
+		if cmd != nil {
+			b = b.WithRateLimiting(cmd)
+		}
+		b.router.GET("/limited", b.rateLimited(usecase.RateLimitRouteShorten, func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})...)
+		return b.router
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(ratelimit.Result{Allowed: true, Limit: 60, Remaining: 59, ResetAfter: 1500 * time.Millisecond}, nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Equal(t, "60", record.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "59", record.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", record.Header().Get("RateLimit-Reset"))
+		assert.Empty(t, record.Header().Get("Retry-After"))
+	})
+	t.Run("too many requests", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(ratelimit.Result{Allowed: false, Limit: 60, RetryAfter: 800 * time.Millisecond, ResetAfter: time.Minute}, nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusTooManyRequests, record.Code)
+		assert.Equal(t, "1", record.Header().Get("Retry-After"))
+		assert.Equal(t, "0", record.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", record.Header().Get("RateLimit-Reset"))
+	})
+	t.Run("route without rule", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(ratelimit.Result{Allowed: true}, nil))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Empty(t, record.Header().Get("RateLimit-Limit"))
+	})
+	t.Run("let through when the rate limit can't be checked", func(t *testing.T) {
+		// Given
+		router := buildRouter(mockCmd(ratelimit.Result{}, assert.AnError))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		// Given
+		router := buildRouter(nil)
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Empty(t, record.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestProtectedRateLimited(t *testing.T) {
+	apiKey := domain.APIKey{ID: "key-1", Role: domain.RoleEditor}
+	authenticateCmd := func(calls *int) usecase.AuthenticateAPIKeyCmd {
+		return func(ctx context.Context, secret string) (domain.APIKey, error) {
+			*calls++
+			if secret != "usk_secret" {
+				return domain.APIKey{}, usecase.ErrInvalidAPIKey
+			}
+			return apiKey, nil
+		}
+	}
+	checkRateLimitCmd := func(results map[usecase.RateLimitScope]ratelimit.Result, scopes *[]usecase.RateLimitScope) usecase.CheckRateLimitCmd {
+		return func(ctx context.Context, route usecase.RateLimitRoute, scope usecase.RateLimitScope, clientIP string) (ratelimit.Result, error) {
+			*scopes = append(*scopes, scope)
+			if scope == usecase.RateLimitScopeAPIKey {
+				authenticatedAPIKey, authenticated := domain.APIKeyFromContext(ctx)
+				assert.True(t, authenticated)
+				assert.Equal(t, apiKey.ID, authenticatedAPIKey.ID)
+			}
+			return results[scope], nil
+		}
+	}
+	buildRouter := func(authenticateCmd usecase.AuthenticateAPIKeyCmd, checkRateLimitCmd usecase.CheckRateLimitCmd) *gin.Engine {
+		b := NewBuilder(domain.EnvTest).WithAPIKeyAuthentication(authenticateCmd).WithRateLimiting(checkRateLimitCmd)
+		b.router.GET("/limited", b.protectedRateLimited(usecase.RateLimitRouteShorten, func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})...)
+		return b.router
+	}
+
+	t.Run("limited by client IP then by API key", func(t *testing.T) {
+		// Given
+		authenticateCalls := 0
+		scopes := []usecase.RateLimitScope{}
+		router := buildRouter(authenticateCmd(&authenticateCalls), checkRateLimitCmd(map[usecase.RateLimitScope]ratelimit.Result{
+			usecase.RateLimitScopeIP:     {Allowed: true, Limit: 60, Remaining: 50, ResetAfter: time.Minute},
+			usecase.RateLimitScopeAPIKey: {Allowed: true, Limit: 60, Remaining: 10, ResetAfter: time.Minute},
+		}, &scopes))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		req.Header.Set("Authorization", "Bearer usk_secret")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Equal(t, []usecase.RateLimitScope{usecase.RateLimitScopeIP, usecase.RateLimitScopeAPIKey}, scopes)
+		assert.Equal(t, 1, authenticateCalls)
+		assert.Equal(t, "10", record.Header().Get("RateLimit-Remaining"))
+	})
+	t.Run("most restrictive headers", func(t *testing.T) {
+		// Given
+		authenticateCalls := 0
+		scopes := []usecase.RateLimitScope{}
+		router := buildRouter(authenticateCmd(&authenticateCalls), checkRateLimitCmd(map[usecase.RateLimitScope]ratelimit.Result{
+			usecase.RateLimitScopeIP:     {Allowed: true, Limit: 60, Remaining: 5, ResetAfter: time.Minute},
+			usecase.RateLimitScopeAPIKey: {Allowed: true, Limit: 60, Remaining: 30, ResetAfter: time.Minute},
+		}, &scopes))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		req.Header.Set("Authorization", "Bearer usk_secret")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.Equal(t, "5", record.Header().Get("RateLimit-Remaining"))
+	})
+	t.Run("invalid API key throttled before authentication", func(t *testing.T) {
+		// Given
+		authenticateCalls := 0
+		scopes := []usecase.RateLimitScope{}
+		router := buildRouter(authenticateCmd(&authenticateCalls), checkRateLimitCmd(map[usecase.RateLimitScope]ratelimit.Result{
+			usecase.RateLimitScopeIP: {Allowed: false, Limit: 60, RetryAfter: time.Second, ResetAfter: time.Minute},
+		}, &scopes))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		req.Header.Set("Authorization", "Bearer usk_invalid")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusTooManyRequests, record.Code)
+		assert.Equal(t, []usecase.RateLimitScope{usecase.RateLimitScopeIP}, scopes)
+		assert.Zero(t, authenticateCalls)
+	})
+	t.Run("API key throttled", func(t *testing.T) {
+		// Given
+		authenticateCalls := 0
+		scopes := []usecase.RateLimitScope{}
+		router := buildRouter(authenticateCmd(&authenticateCalls), checkRateLimitCmd(map[usecase.RateLimitScope]ratelimit.Result{
+			usecase.RateLimitScopeIP:     {Allowed: true, Limit: 60, Remaining: 59, ResetAfter: time.Minute},
+			usecase.RateLimitScopeAPIKey: {Allowed: false, Limit: 60, RetryAfter: time.Second, ResetAfter: time.Minute},
+		}, &scopes))
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", nil)
+		req.Header.Set("Authorization", "Bearer usk_secret")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusTooManyRequests, record.Code)
+		assert.Equal(t, "0", record.Header().Get("RateLimit-Remaining"))
+	})
+}
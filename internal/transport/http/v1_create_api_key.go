@@ -0,0 +1,86 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// CreateAPIKeyRequest holds the JSON body request structure
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+	Role string `json:"role"` // admin, editor or viewer, editor by default
+}
+
+// CreateAPIKeyResponse holds the JSON body response structure
+type CreateAPIKeyResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+	Key  string `json:"key"` // Only returned once, it can't be retrieved afterwards
+}
+
+// WithV1CreateAPIKeyHandler register the create API key API in the router of the HTTP builder
+func (b *Builder) WithV1CreateAPIKeyHandler(cmd usecase.CreateAPIKeyCmd) *Builder {
+	b.router.POST(fmt.Sprintf("%s/api-keys", pathPrefixV1), b.protected(v1CreateAPIKeyHandler(cmd))...)
+	return b
+}
+
+// v1CreateAPIKeyHandler creates an API key
+func v1CreateAPIKeyHandler(cmd usecase.CreateAPIKeyCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var createAPIKeyRequest CreateAPIKeyRequest
+		err := c.ShouldBindJSON(&createAPIKeyRequest)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "can't parse JSON body",
+				Hint:        "the body should be JSON with application/json and required fields",
+			}, err))
+			return
+		}
+
+		role := domain.RoleEditor
+		if createAPIKeyRequest.Role != "" {
+			role = domain.Role(createAPIKeyRequest.Role)
+		}
+
+		apiKey, secret, err := cmd(c.Request.Context(), createAPIKeyRequest.Name, role)
+		switch err {
+		case nil:
+			c.JSON(http.StatusCreated, CreateAPIKeyResponse{
+				ID:   apiKey.ID,
+				Name: apiKey.Name,
+				Role: string(apiKey.Role),
+				Key:  secret,
+			})
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(ApiError{
+				Name:        "forbidden",
+				Description: "only an admin API key can create API keys",
+				Hint:        "authenticate with an admin API key",
+			}, err))
+			return
+		case usecase.ErrInvalidRole:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given role is invalid",
+				Hint:        "the role should be admin, editor or viewer",
+			}, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
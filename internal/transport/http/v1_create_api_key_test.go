@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1CreateAPIKeyHandler(t *testing.T) {
+	name := "marketing"
+	secret := "usk_4Xq9mZ2pL7vB1nC8dF3gH6jK0wR5tY"
+	u, err := url.Parse(fmt.Sprintf("%s/api-keys", pathPrefixV1))
+	require.NoError(t, err)
+	mockCmd := func(err error) usecase.CreateAPIKeyCmd {
+		return func(ctx context.Context, n string, role domain.Role) (domain.APIKey, string, error) {
+			assert.Equal(t, name, n)
+			assert.Equal(t, domain.RoleViewer, role)
+			return domain.APIKey{ID: "3f9a1c0e7b2d4a65", Name: n, Role: role}, secret, err
+		}
+	}
+	body := fmt.Sprintf(`{"name": "%s", "role": "viewer"}`, name)
+
+	t.Run("created", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateAPIKeyHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusCreated, record.Code)
+		bodyResponse := CreateAPIKeyResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, CreateAPIKeyResponse{ID: "3f9a1c0e7b2d4a65", Name: name, Role: "viewer", Key: secret}, bodyResponse)
+	})
+	t.Run("created with the default role", func(t *testing.T) {
+		// Given
+		cmd := func(ctx context.Context, n string, role domain.Role) (domain.APIKey, string, error) {
+			assert.Equal(t, domain.RoleEditor, role)
+			return domain.APIKey{ID: "3f9a1c0e7b2d4a65", Name: n, Role: role}, secret, nil
+		}
+		router := NewBuilder(domain.EnvTest).WithV1CreateAPIKeyHandler(cmd).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"name": "%s"}`, name)))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusCreated, record.Code)
+	})
+	t.Run("bad request", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateAPIKeyHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(`{"role": "viewer"}`))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateAPIKeyHandler(mockCmd(usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("unprocessable entity", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateAPIKeyHandler(mockCmd(usecase.ErrInvalidRole)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateAPIKeyHandler(mockCmd(errors.New("unknown error"))).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
 	"urlShortenerService/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -12,7 +15,11 @@ import (
 
 // CreateShortenURLRequest holds the JSON body request structure
 type CreateShortenURLRequest struct {
-	OriginalURL string `json:"original_url" binding:"required"`
+	OriginalURL  string     `json:"original_url" binding:"required"`
+	CustomSlug   string     `json:"custom_slug"`
+	TTL          int        `json:"ttl"`        // In seconds
+	ExpiresAt    *time.Time `json:"expires_at"` // RFC 3339 formatted
+	NeverExpires bool       `json:"never_expires"`
 }
 
 // CreateShortenURLResponse holds the JSON body response structure
@@ -22,10 +29,60 @@ type CreateShortenURLResponse struct {
 
 // WithV1CreateShortenURLHandler register the create shorten URL API in the router of the HTTP builder
 func (b *Builder) WithV1CreateShortenURLHandler(cmd usecase.CreateShortenURLCmd) *Builder {
-	b.router.POST(fmt.Sprintf("%s/shorten", pathPrefixV1), v1CreateShortenURLHandler(cmd))
+	b.router.POST(fmt.Sprintf("%s/shorten", pathPrefixV1), b.protectedRateLimited(usecase.RateLimitRouteShorten, v1CreateShortenURLHandler(cmd))...)
 	return b
 }
 
+// toParams converts the request into the parameters of the create shorten URL command
+func (r CreateShortenURLRequest) toParams() usecase.CreateShortenURLParams {
+	return usecase.CreateShortenURLParams{
+		URLToShorten: r.OriginalURL,
+		CustomSlug:   r.CustomSlug,
+		TTL:          time.Duration(r.TTL) * time.Second,
+		ExpiresAt:    r.ExpiresAt,
+		NeverExpires: r.NeverExpires,
+	}
+}
+
+// createShortenURLAPIError returns the HTTP status and the API error of a failed shorten URL creation
+func createShortenURLAPIError(err error) (int, ApiError) {
+	switch {
+	case errors.Is(err, command.ErrInvalidURL):
+		return http.StatusUnprocessableEntity, ApiError{
+			Name:        "unprocessable_entity",
+			Description: "the given original_url is invalid",
+			Hint:        "the URL should respect the RFC: https://datatracker.ietf.org/doc/html/rfc1738 ",
+		}
+	case errors.Is(err, command.ErrInvalidSlugLenght), errors.Is(err, command.ErrInvalidSlugNonAlphanumeric):
+		return http.StatusUnprocessableEntity, ApiError{
+			Name:        "unprocessable_entity",
+			Description: "the given custom_slug is invalid",
+			Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+		}
+	case errors.Is(err, usecase.ErrInvalidExpiration):
+		return http.StatusUnprocessableEntity, ApiError{
+			Name:        "unprocessable_entity",
+			Description: "the given expiration is invalid",
+			Hint:        "use only one of ttl (positive number of seconds), expires_at (future RFC 3339 date) or never_expires",
+		}
+	case errors.Is(err, shorturl.ErrSlugAlreadyExists):
+		return http.StatusConflict, ApiError{
+			Name:        "conflict",
+			Description: "the given custom_slug is already associated to a different URL",
+			Hint:        "choose another custom_slug",
+		}
+	case errors.Is(err, usecase.ErrForbidden):
+		return http.StatusForbidden, forbiddenAPIError
+	default:
+		glog.Error(err)
+		return http.StatusInternalServerError, ApiError{
+			Name:        "internal_server_error",
+			Description: "unknown error",
+			Hint:        "if you are the application owner, please check the logs for more details",
+		}
+	}
+}
+
 // v1CreateShortenURLHandler creates a shorten URL of the given one
 func v1CreateShortenURLHandler(cmd usecase.CreateShortenURLCmd) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -40,26 +97,12 @@ func v1CreateShortenURLHandler(cmd usecase.CreateShortenURLCmd) gin.HandlerFunc
 			return
 		}
 
-		shortenedURL, err := cmd(c.Request.Context(), createShortenURLRequest.OriginalURL)
-		switch err {
-		case nil:
-			c.JSON(http.StatusCreated, CreateShortenURLResponse{ShortURL: shortenedURL})
-			return
-		case command.ErrInvalidURL:
-			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
-				Name:        "unprocessable_entity",
-				Description: "the given original_url is invalid",
-				Hint:        "the URL should respect the RFC: https://datatracker.ietf.org/doc/html/rfc1738 ",
-			}, err))
-			return
-		default:
-			glog.Error(err)
-			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
-				Name:        "internal_server_error",
-				Description: "unknown error",
-				Hint:        "if you are the application owner, please check the logs for more details",
-			}, err))
+		shortenedURL, err := cmd(c.Request.Context(), createShortenURLRequest.toParams())
+		if err != nil {
+			status, apiError := createShortenURLAPIError(err)
+			c.JSON(status, CreateAPIError(apiError, err))
 			return
 		}
+		c.JSON(http.StatusCreated, CreateShortenURLResponse{ShortURL: shortenedURL})
 	}
 }
@@ -9,8 +9,10 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
 	"urlShortenerService/internal/usecase"
 
 	"github.com/stretchr/testify/assert"
@@ -23,8 +25,8 @@ func TestWithV1CreateShortenURLHandler(t *testing.T) {
 	u, err := url.Parse(fmt.Sprintf("%s/shorten", pathPrefixV1))
 	require.NoError(t, err)
 	mockCmd := func(err error) usecase.CreateShortenURLCmd {
-		return func(ctx context.Context, urlToShorten string) (string, error) {
-			assert.Equal(t, originalURL, urlToShorten)
+		return func(ctx context.Context, params usecase.CreateShortenURLParams) (string, error) {
+			assert.Equal(t, originalURL, params.URLToShorten)
 			return shortURL, err
 		}
 	}
@@ -44,6 +46,48 @@ func TestWithV1CreateShortenURLHandler(t *testing.T) {
 		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
 		assert.Equal(t, shortURL, bodyResponse.ShortURL)
 	})
+	t.Run("created with a custom slug", func(t *testing.T) {
+		// Given
+		customSlug := "spring-sale"
+		cmd := func(ctx context.Context, params usecase.CreateShortenURLParams) (string, error) {
+			assert.Equal(t, originalURL, params.URLToShorten)
+			assert.Equal(t, customSlug, params.CustomSlug)
+			return fmt.Sprintf("https://localhost:8080/%s", params.CustomSlug), nil
+		}
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(cmd).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s", "custom_slug": "%s"}`, originalURL, customSlug)))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusCreated, record.Code)
+		bodyResponse := CreateShortenURLResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, fmt.Sprintf("https://localhost:8080/%s", customSlug), bodyResponse.ShortURL)
+	})
+	t.Run("created with an expiration", func(t *testing.T) {
+		// Given
+		expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		cmd := func(ctx context.Context, params usecase.CreateShortenURLParams) (string, error) {
+			assert.Equal(t, originalURL, params.URLToShorten)
+			assert.Equal(t, time.Hour, params.TTL)
+			require.NotNil(t, params.ExpiresAt)
+			assert.True(t, expiresAt.Equal(*params.ExpiresAt))
+			assert.True(t, params.NeverExpires)
+			return shortURL, nil
+		}
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(cmd).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s", "ttl": 3600, "expires_at": "2030-01-01T00:00:00Z", "never_expires": true}`, originalURL)))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusCreated, record.Code)
+	})
 	t.Run("bad request", func(t *testing.T) {
 		t.Run("not a valid JSON body", func(t *testing.T) {
 			// Given
@@ -71,17 +115,67 @@ func TestWithV1CreateShortenURLHandler(t *testing.T) {
 		})
 	})
 	t.Run("unprocessable entity", func(t *testing.T) {
+		t.Run("invalid URL", func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(command.ErrInvalidURL)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s"}`, originalURL)))
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+		})
+		t.Run("invalid custom slug", func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(command.ErrInvalidSlugNonAlphanumeric)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s", "custom_slug": "spring_sale"}`, originalURL)))
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+		})
+	})
+	t.Run("unprocessable entity for invalid expiration", func(t *testing.T) {
 		// Given
-		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(command.ErrInvalidURL)).router
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(usecase.ErrInvalidExpiration)).router
 
 		// When
 		record := httptest.NewRecorder()
-		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s"}`, originalURL)))
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s", "ttl": -1}`, originalURL)))
 		router.ServeHTTP(record, req)
 
 		// Then
 		assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
 	})
+	t.Run("conflict", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(shorturl.ErrSlugAlreadyExists)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s", "custom_slug": "spring-sale"}`, originalURL)))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusConflict, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", u.String(), strings.NewReader(fmt.Sprintf(`{"original_url": "%s"}`, originalURL)))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
 	t.Run("internal server error", func(t *testing.T) {
 		// Given
 		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLHandler(mockCmd(assert.AnError)).router
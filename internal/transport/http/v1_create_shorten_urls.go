@@ -0,0 +1,176 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/golang/glog"
+)
+
+// maxCreateShortenURLsBodySize is the maximal size in bytes of a batch body, so that a single request can't exhaust the memory
+const maxCreateShortenURLsBodySize int64 = 8 << 20
+
+// errBatchTooLarge is the error when a batch body holds more items than a batch can
+var errBatchTooLarge error = fmt.Errorf("batch holds more than %d items", usecase.MaxShortenURLsBatchSize)
+
+// CreateShortenURLsResponse holds the JSON body response structure of a batch creation
+type CreateShortenURLsResponse struct {
+	Results   []CreateShortenURLsResultResponse `json:"results"`
+	Succeeded int                               `json:"succeeded"`
+	Failed    int                               `json:"failed"`
+}
+
+// CreateShortenURLsResultResponse holds the result of an item of a batch creation, in the same order as the request
+type CreateShortenURLsResultResponse struct {
+	Status   int       `json:"status"`
+	ShortURL string    `json:"short_url,omitempty"`
+	Error    *ApiError `json:"error,omitempty"`
+}
+
+// WithV1CreateShortenURLsHandler register the create shorten URLs batch API in the router of the HTTP builder
+func (b *Builder) WithV1CreateShortenURLsHandler(cmd usecase.CreateShortenURLsCmd) *Builder {
+	b.router.POST(fmt.Sprintf("%s/shorten/batch", pathPrefixV1), b.protectedRateLimited(usecase.RateLimitRouteShortenBatch, v1CreateShortenURLsHandler(cmd))...)
+	return b
+}
+
+// decodeCreateShortenURLsRequest decodes a batch body, either a JSON array or a NDJSON stream of create shorten URL requests
+// The decoding stops as soon as the body holds more items than a batch can
+func decodeCreateShortenURLsRequest(c *gin.Context) ([]CreateShortenURLRequest, error) {
+	var requests []CreateShortenURLRequest
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	decoder := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, maxCreateShortenURLsBodySize))
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson":
+		for {
+			var request CreateShortenURLRequest
+			err := decoder.Decode(&request)
+			if errors.Is(err, io.EOF) {
+				return requests, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(requests) == usecase.MaxShortenURLsBatchSize {
+				return nil, errBatchTooLarge
+			}
+			requests = append(requests, request)
+		}
+	default:
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if token != json.Delim('[') {
+			return nil, fmt.Errorf("expected a JSON array, got %v", token)
+		}
+		for decoder.More() {
+			var request CreateShortenURLRequest
+			err := decoder.Decode(&request)
+			if err != nil {
+				return nil, err
+			}
+			if len(requests) == usecase.MaxShortenURLsBatchSize {
+				return nil, errBatchTooLarge
+			}
+			requests = append(requests, request)
+		}
+		_, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		return requests, nil
+	}
+}
+
+// v1CreateShortenURLsHandler creates shorten URLs of the given ones, an invalid item only fails its own result
+func v1CreateShortenURLsHandler(cmd usecase.CreateShortenURLsCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requests, err := decodeCreateShortenURLsRequest(c)
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, errBatchTooLarge) || errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, CreateAPIError(ApiError{
+				Name:        "request_entity_too_large",
+				Description: "batch too large",
+				Hint:        fmt.Sprintf("a batch should hold at most %d items within %d bytes", usecase.MaxShortenURLsBatchSize, maxCreateShortenURLsBodySize),
+			}, err))
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "can't parse batch body",
+				Hint:        "the body should be a JSON array with application/json or one JSON object per line with application/x-ndjson",
+			}, err))
+			return
+		}
+
+		// Items missing required fields are answered without being sent to the command
+		response := CreateShortenURLsResponse{Results: make([]CreateShortenURLsResultResponse, len(requests))}
+		var params []usecase.CreateShortenURLParams
+		var paramsIndexes []int
+		for i, request := range requests {
+			err := binding.Validator.ValidateStruct(request)
+			if err != nil {
+				response.Results[i] = CreateShortenURLsResultResponse{Status: http.StatusBadRequest, Error: &ApiError{
+					Name:        "bad_request",
+					Description: "missing required fields",
+					Hint:        "each item requires an original_url",
+				}}
+				continue
+			}
+			params = append(params, request.toParams())
+			paramsIndexes = append(paramsIndexes, i)
+		}
+
+		// The command is skipped when every item is already failed, an empty batch is rejected by the command
+		if len(params) > 0 || len(requests) == 0 {
+			results, err := cmd(c.Request.Context(), params)
+			switch err {
+			case nil:
+			case usecase.ErrInvalidBatchSize:
+				c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+					Name:        "bad_request",
+					Description: "invalid batch size",
+					Hint:        "a batch should hold between 1 and 10 000 items",
+				}, err))
+				return
+			case usecase.ErrForbidden:
+				c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+				return
+			default:
+				glog.Error(err)
+				c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+					Name:        "internal_server_error",
+					Description: "unknown error",
+					Hint:        "if you are the application owner, please check the logs for more details",
+				}, err))
+				return
+			}
+
+			for j, result := range results {
+				if result.Err != nil {
+					status, apiError := createShortenURLAPIError(result.Err)
+					response.Results[paramsIndexes[j]] = CreateShortenURLsResultResponse{Status: status, Error: &apiError}
+					continue
+				}
+				response.Results[paramsIndexes[j]] = CreateShortenURLsResultResponse{Status: http.StatusCreated, ShortURL: result.ShortURL}
+			}
+		}
+
+		for _, result := range response.Results {
+			if result.Error != nil {
+				response.Failed++
+			} else {
+				response.Succeeded++
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
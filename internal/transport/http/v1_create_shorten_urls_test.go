@@ -0,0 +1,166 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1CreateShortenURLsHandler(t *testing.T) {
+	path := fmt.Sprintf("%s/shorten/batch", pathPrefixV1)
+	mockCmd := func(expectedURLs []string, results []usecase.CreateShortenURLResult, err error) usecase.CreateShortenURLsCmd {
+		return func(ctx context.Context, params []usecase.CreateShortenURLParams) ([]usecase.CreateShortenURLResult, error) {
+			var urls []string
+			for _, param := range params {
+				urls = append(urls, param.URLToShorten)
+			}
+			assert.Equal(t, expectedURLs, urls)
+			return results, err
+		}
+	}
+	results := []usecase.CreateShortenURLResult{
+		{ShortURL: "http://localhost/zTw34enA"},
+		{Err: command.ErrInvalidURL},
+		{Err: shorturl.ErrSlugAlreadyExists},
+	}
+	expectedURLs := []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"}
+	assertResponse := func(t *testing.T, record *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := CreateShortenURLsResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		require.Len(t, bodyResponse.Results, 4)
+		assert.Equal(t, 1, bodyResponse.Succeeded)
+		assert.Equal(t, 3, bodyResponse.Failed)
+		assert.Equal(t, http.StatusCreated, bodyResponse.Results[0].Status)
+		assert.Equal(t, "http://localhost/zTw34enA", bodyResponse.Results[0].ShortURL)
+		assert.Equal(t, http.StatusBadRequest, bodyResponse.Results[1].Status)
+		assert.Equal(t, http.StatusUnprocessableEntity, bodyResponse.Results[2].Status)
+		assert.Equal(t, http.StatusConflict, bodyResponse.Results[3].Status)
+		require.NotNil(t, bodyResponse.Results[3].Error)
+		assert.Equal(t, "conflict", bodyResponse.Results[3].Error.Name)
+	}
+
+	t.Run("ok with JSON array", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd(expectedURLs, results, nil)).router
+		body := `[{"original_url": "https://example.com/1"}, {"custom_slug": "missing-url"}, {"original_url": "https://example.com/2"}, {"original_url": "https://example.com/3", "custom_slug": "taken"}]`
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assertResponse(t, record)
+	})
+	t.Run("ok with NDJSON", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd(expectedURLs, results, nil)).router
+		body := "{\"original_url\": \"https://example.com/1\"}\n{\"custom_slug\": \"missing-url\"}\n{\"original_url\": \"https://example.com/2\"}\n{\"original_url\": \"https://example.com/3\", \"custom_slug\": \"taken\"}\n"
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assertResponse(t, record)
+	})
+	t.Run("bad request on malformed body", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd(nil, nil, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"original_url": "https://example.com/1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("bad request on invalid batch size", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd(nil, nil, usecase.ErrInvalidBatchSize)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(`[]`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("request entity too large on too many items", func(t *testing.T) {
+		for contentType, body := range map[string]string{
+			"application/json":     "[" + strings.Repeat(`{"original_url": "https://example.com"},`, usecase.MaxShortenURLsBatchSize) + `{"original_url": "https://example.com"}]`,
+			"application/x-ndjson": strings.Repeat("{\"original_url\": \"https://example.com\"}\n", usecase.MaxShortenURLsBatchSize+1),
+		} {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd(nil, nil, nil)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", path, strings.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusRequestEntityTooLarge, record.Code, contentType)
+		}
+	})
+	t.Run("request entity too large on too large body", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd(nil, nil, nil)).router
+		body := `[{"original_url": "https://example.com/` + strings.Repeat("a", int(maxCreateShortenURLsBodySize)) + `"}]`
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusRequestEntityTooLarge, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd([]string{"https://example.com/1"}, nil, usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(`[{"original_url": "https://example.com/1"}]`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1CreateShortenURLsHandler(mockCmd([]string{"https://example.com/1"}, nil, assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(`[{"original_url": "https://example.com/1"}]`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// WithV1DeleteLinkHandler register the delete link API in the router of the HTTP builder
+func (b *Builder) WithV1DeleteLinkHandler(cmd usecase.DeleteLinkCmd) *Builder {
+	b.router.DELETE(fmt.Sprintf("%s/links/:slug", pathPrefixV1), b.protected(v1DeleteLinkHandler(cmd))...)
+	return b
+}
+
+// v1DeleteLinkHandler deletes a link given a slug
+func v1DeleteLinkHandler(cmd usecase.DeleteLinkCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := cmd(c.Request.Context(), c.Param("slug"))
+		switch err {
+		case nil:
+			c.Status(http.StatusNoContent)
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(ApiError{
+				Name:        "forbidden",
+				Description: "the API key is not allowed to modify this link",
+				Hint:        "only the editor API key that created the link or an admin API key can modify it",
+			}, err))
+			return
+		case shorturl.ErrExpired:
+			c.JSON(http.StatusGone, CreateAPIError(ApiError{
+				Name:        "gone",
+				Description: "the URL associated to the given slug has expired",
+				Hint:        "ask the owner of the link for a new one",
+			}, err))
+			return
+		case shorturl.ErrNotFound:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "no URL found associated to the given slug",
+				Hint:        "the slug might be incorrect",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1DeleteLinkHandler(t *testing.T) {
+	slug := "zTw34enA"
+	u, err := url.Parse(fmt.Sprintf("%s/links/%s", pathPrefixV1, slug))
+	require.NoError(t, err)
+	mockCmd := func(err error) usecase.DeleteLinkCmd {
+		return func(ctx context.Context, s string) error {
+			assert.Equal(t, slug, s)
+			return err
+		}
+	}
+
+	t.Run("no content", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DeleteLinkHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusNoContent, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DeleteLinkHandler(mockCmd(usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("not found", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DeleteLinkHandler(mockCmd(shorturl.ErrNotFound)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusNotFound, record.Code)
+	})
+	t.Run("unprocessable entity", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DeleteLinkHandler(mockCmd(command.ErrInvalidSlugLenght)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DeleteLinkHandler(mockCmd(assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
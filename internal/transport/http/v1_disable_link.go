@@ -0,0 +1,67 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// WithV1DisableLinkHandler register the disable and enable link APIs in the router of the HTTP builder
+func (b *Builder) WithV1DisableLinkHandler(cmd usecase.DisableLinkCmd) *Builder {
+	b.router.POST(fmt.Sprintf("%s/links/:slug/disable", pathPrefixV1), b.protected(v1DisableLinkHandler(cmd, true))...)
+	b.router.POST(fmt.Sprintf("%s/links/:slug/enable", pathPrefixV1), b.protected(v1DisableLinkHandler(cmd, false))...)
+	return b
+}
+
+// v1DisableLinkHandler disables a link given a slug, or enables it again
+func v1DisableLinkHandler(cmd usecase.DisableLinkCmd, disabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		urlMapping, err := cmd(c.Request.Context(), c.Param("slug"), disabled)
+		switch err {
+		case nil:
+			c.JSON(http.StatusOK, newLinkResponse(urlMapping))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(ApiError{
+				Name:        "forbidden",
+				Description: "the API key is not allowed to disable links",
+				Hint:        "only an admin API key can disable or enable a link",
+			}, err))
+			return
+		case shorturl.ErrExpired:
+			c.JSON(http.StatusGone, CreateAPIError(ApiError{
+				Name:        "gone",
+				Description: "the URL associated to the given slug has expired",
+				Hint:        "an expired link can be purged instead",
+			}, err))
+			return
+		case shorturl.ErrNotFound:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "no URL found associated to the given slug",
+				Hint:        "the slug might be incorrect or expired",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1DisableLinkHandler(t *testing.T) {
+	slug := "zTw34enA"
+	mockCmd := func(expectedDisabled bool, err error) usecase.DisableLinkCmd {
+		return func(ctx context.Context, s string, disabled bool) (domain.URLMapping, error) {
+			assert.Equal(t, slug, s)
+			assert.Equal(t, expectedDisabled, disabled)
+			if err != nil {
+				return domain.URLMapping{}, err
+			}
+			return domain.URLMapping{Slug: slug, OriginalURL: "https://example.com", Disabled: disabled}, nil
+		}
+	}
+
+	t.Run("ok disabled", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DisableLinkHandler(mockCmd(true, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", fmt.Sprintf("%s/links/%s/disable", pathPrefixV1, slug), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := LinkResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, string(domain.URLMappingStatusDisabled), bodyResponse.Status)
+	})
+	t.Run("ok enabled", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1DisableLinkHandler(mockCmd(false, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", fmt.Sprintf("%s/links/%s/enable", pathPrefixV1, slug), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := LinkResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, string(domain.URLMappingStatusActive), bodyResponse.Status)
+	})
+
+	scenarios := []struct {
+		Name     string
+		Err      error
+		Expected int
+	}{
+		{Name: "forbidden", Err: usecase.ErrForbidden, Expected: http.StatusForbidden},
+		{Name: "gone", Err: shorturl.ErrExpired, Expected: http.StatusGone},
+		{Name: "not found", Err: shorturl.ErrNotFound, Expected: http.StatusNotFound},
+		{Name: "unprocessable entity", Err: command.ErrInvalidSlugLenght, Expected: http.StatusUnprocessableEntity},
+		{Name: "internal server error", Err: assert.AnError, Expected: http.StatusInternalServerError},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1DisableLinkHandler(mockCmd(true, scenario.Err)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("%s/links/%s/disable", pathPrefixV1, slug), nil)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, scenario.Expected, record.Code)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// GetClickBreakdownResponse holds the JSON body response structure
+type GetClickBreakdownResponse struct {
+	Slug      string                           `json:"slug"`
+	Dimension string                           `json:"dimension"`
+	Values    []getClickBreakdownValueResponse `json:"values"`
+}
+
+type getClickBreakdownValueResponse struct {
+	Value   string `json:"value"`
+	Counter int    `json:"counter"`
+}
+
+// WithGetClickBreakdownHandler register the get click breakdown API in the router of the HTTP builder
+func (b *Builder) WithGetClickBreakdownHandler(cmd usecase.GetClickBreakdownCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/statistics/slug/:slug/:dimension", pathPrefixV1), b.protected(getClickBreakdownHandler(cmd))...)
+	return b
+}
+
+// getClickBreakdownHandler retrieves the top values of a dimension among the clicks of a given slug
+func getClickBreakdownHandler(cmd usecase.GetClickBreakdownCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var resultLimit int
+		resultLimitStr, resultLimitExists := c.GetQuery("limit")
+		if resultLimitExists {
+			var err error
+			resultLimit, err = strconv.Atoi(resultLimitStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+					Name:        "bad_request",
+					Description: "invalid query parameter 'limit'",
+					Hint:        "'limit' value is not an integer",
+				}, err))
+				return
+			}
+		}
+
+		slug := c.Param("slug")
+		dimension := statistics.Dimension(c.Param("dimension"))
+		breakdowns, err := cmd(c.Request.Context(), slug, dimension, int64(resultLimit))
+		switch err {
+		case nil:
+			var response = GetClickBreakdownResponse{Slug: slug, Dimension: string(dimension), Values: []getClickBreakdownValueResponse{}}
+			for _, breakdown := range breakdowns {
+				response.Values = append(response.Values, getClickBreakdownValueResponse{
+					Value:   breakdown.Value,
+					Counter: breakdown.Counter,
+				})
+			}
+			c.JSON(http.StatusOK, response)
+			return
+		case usecase.ErrInvalidDimension:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "the clicks are not broken down by the given dimension",
+				Hint:        "the dimension should be one of 'referrers', 'countries', 'browsers', 'os' or 'devices'",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithGetClickBreakdownHandler(t *testing.T) {
+	slug := "spring-sale"
+	mockCmd := func(expectedLimit int64, breakdowns []domain.ClickBreakdown, err error) usecase.GetClickBreakdownCmd {
+		return func(ctx context.Context, askedSlug string, dimension statistics.Dimension, limitOveride int64) ([]domain.ClickBreakdown, error) {
+			assert.Equal(t, slug, askedSlug)
+			assert.Equal(t, statistics.DimensionCountry, dimension)
+			assert.Equal(t, expectedLimit, limitOveride)
+			return breakdowns, err
+		}
+	}
+	serve := func(cmd usecase.GetClickBreakdownCmd, query string) *httptest.ResponseRecorder {
+		router := NewBuilder(domain.EnvTest).WithGetClickBreakdownHandler(cmd).router
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", fmt.Sprintf("%s/statistics/slug/%s/countries%s", pathPrefixV1, slug, query), nil)
+		router.ServeHTTP(record, req)
+		return record
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		// When
+		record := serve(mockCmd(2, []domain.ClickBreakdown{{Value: "FR", Counter: 10}, {Value: "unknown", Counter: 3}}, nil), "?limit=2")
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := GetClickBreakdownResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, GetClickBreakdownResponse{
+			Slug:      slug,
+			Dimension: "countries",
+			Values:    []getClickBreakdownValueResponse{{Value: "FR", Counter: 10}, {Value: "unknown", Counter: 3}},
+		}, bodyResponse)
+	})
+	t.Run("no click", func(t *testing.T) {
+		// When
+		record := serve(mockCmd(0, nil, nil), "")
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		assert.JSONEq(t, `{"slug":"spring-sale","dimension":"countries","values":[]}`, record.Body.String())
+	})
+	t.Run("invalid limit", func(t *testing.T) {
+		// When
+		record := serve(nil, "?limit=ten")
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("errors", func(t *testing.T) {
+		scenarios := []struct {
+			Name           string
+			Err            error
+			ExpectedStatus int
+		}{
+			{Name: "invalid dimension", Err: usecase.ErrInvalidDimension, ExpectedStatus: http.StatusNotFound},
+			{Name: "invalid slug", Err: command.ErrInvalidSlugNonAlphanumeric, ExpectedStatus: http.StatusUnprocessableEntity},
+			{Name: "forbidden", Err: usecase.ErrForbidden, ExpectedStatus: http.StatusForbidden},
+			{Name: "internal server error", Err: assert.AnError, ExpectedStatus: http.StatusInternalServerError},
+		}
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				// When
+				record := serve(mockCmd(0, nil, scenario.Err), "")
+
+				// Then
+				assert.Equal(t, scenario.ExpectedStatus, record.Code)
+			})
+		}
+	})
+}
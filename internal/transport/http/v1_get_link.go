@@ -0,0 +1,86 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// LinkResponse holds the JSON body response structure of a link
+type LinkResponse struct {
+	Slug        string     `json:"slug"`
+	OriginalURL string     `json:"original_url"`
+	InsertedAt  time.Time  `json:"inserted_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Status      string     `json:"status"`
+	Owner       string     `json:"owner,omitempty"`
+}
+
+// newLinkResponse creates a link response from an URL mapping
+func newLinkResponse(urlMapping domain.URLMapping) LinkResponse {
+	return LinkResponse{
+		Slug:        urlMapping.Slug,
+		OriginalURL: urlMapping.OriginalURL,
+		InsertedAt:  urlMapping.InsertedAt,
+		ExpiresAt:   urlMapping.ExpiresAt,
+		Status:      string(urlMapping.Status(time.Now())),
+		Owner:       urlMapping.Owner,
+	}
+}
+
+// WithV1GetLinkHandler register the get link API in the router of the HTTP builder
+func (b *Builder) WithV1GetLinkHandler(cmd usecase.GetLinkCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/links/:slug", pathPrefixV1), b.protected(v1GetLinkHandler(cmd))...)
+	return b
+}
+
+// v1GetLinkHandler retrieves a link metadata given a slug
+func v1GetLinkHandler(cmd usecase.GetLinkCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		urlMapping, err := cmd(c.Request.Context(), c.Param("slug"))
+		switch err {
+		case nil:
+			c.JSON(http.StatusOK, newLinkResponse(urlMapping))
+			return
+		case shorturl.ErrNotFound:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "no URL found associated to the given slug",
+				Hint:        "the slug might be incorrect",
+			}, err))
+			return
+		case shorturl.ErrExpired:
+			c.JSON(http.StatusGone, CreateAPIError(ApiError{
+				Name:        "gone",
+				Description: "the URL associated to the given slug has expired",
+				Hint:        "ask the owner of the link for a new one",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
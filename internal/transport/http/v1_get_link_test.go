@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1GetLinkHandler(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	urlMapping := domain.URLMapping{
+		Slug:        "zTw34enA",
+		OriginalURL: "https://my-very-long-url.com/needs-to-be-shortened",
+		InsertedAt:  time.Now().UTC().Truncate(time.Second),
+		ExpiresAt:   &expiresAt,
+	}
+	u, err := url.Parse(fmt.Sprintf("%s/links/%s", pathPrefixV1, urlMapping.Slug))
+	require.NoError(t, err)
+	mockCmd := func(err error) usecase.GetLinkCmd {
+		return func(ctx context.Context, slug string) (domain.URLMapping, error) {
+			assert.Equal(t, urlMapping.Slug, slug)
+			if err != nil {
+				return domain.URLMapping{}, err
+			}
+			return urlMapping, nil
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := LinkResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, urlMapping.Slug, bodyResponse.Slug)
+		assert.Equal(t, urlMapping.OriginalURL, bodyResponse.OriginalURL)
+		assert.Equal(t, urlMapping.InsertedAt, bodyResponse.InsertedAt)
+		assert.Equal(t, urlMapping.ExpiresAt, bodyResponse.ExpiresAt)
+		assert.Equal(t, string(domain.URLMappingStatusActive), bodyResponse.Status)
+	})
+	t.Run("expired but not deleted yet", func(t *testing.T) {
+		// Given
+		expiredAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		expiredURLMapping := domain.URLMapping{Slug: urlMapping.Slug, OriginalURL: urlMapping.OriginalURL, ExpiresAt: &expiredAt}
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(func(ctx context.Context, slug string) (domain.URLMapping, error) {
+			return expiredURLMapping, nil
+		}).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := LinkResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, &expiredAt, bodyResponse.ExpiresAt)
+		assert.Equal(t, string(domain.URLMappingStatusExpired), bodyResponse.Status)
+	})
+	t.Run("not found", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(mockCmd(shorturl.ErrNotFound)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusNotFound, record.Code)
+	})
+	t.Run("gone", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(mockCmd(shorturl.ErrExpired)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusGone, record.Code)
+	})
+	t.Run("unprocessable entity", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(mockCmd(command.ErrInvalidSlugLenght)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(mockCmd(usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1GetLinkHandler(mockCmd(assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// GetStatisticsForSlugResponse holds the JSON body response structure
+type GetStatisticsForSlugResponse struct {
+	Slug             string `json:"slug"`
+	URL              string `json:"url,omitempty"` // Empty once the slug is deleted or expired
+	ShortenedCounter int    `json:"shortened_counter"`
+	AccessedCounter  int    `json:"accessed_counter"`
+	UniqueVisitors   int    `json:"unique_visitors"` // Approximate
+}
+
+// WithGetStatisticsForSlugHandler register the get statistics for slug API in the router of the HTTP builder
+func (b *Builder) WithGetStatisticsForSlugHandler(cmd usecase.GetStatisticsForSlugCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/statistics/slug/:slug", pathPrefixV1), b.protected(getStatisticsForSlugHandler(cmd))...)
+	return b
+}
+
+// getStatisticsForSlugHandler retrieves statistics for a given slug
+func getStatisticsForSlugHandler(cmd usecase.GetStatisticsForSlugCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		statistics, err := cmd(c.Request.Context(), c.Param("slug"))
+		switch err {
+		case nil:
+			c.JSON(http.StatusOK, GetStatisticsForSlugResponse{
+				Slug:             statistics.Slug,
+				URL:              statistics.URL,
+				ShortenedCounter: statistics.ShortenedCounter,
+				AccessedCounter:  statistics.AccessedCounter,
+				UniqueVisitors:   statistics.UniqueVisitors,
+			})
+			return
+		case shorturl.ErrNotFound:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "no statistics found for the given slug",
+				Hint:        "the slug might be incorrect",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithGetStatisticsForSlugHandler(t *testing.T) {
+	slug := "spring-sale"
+	mockCmd := func(statistic domain.URLStatistic, err error) usecase.GetStatisticsForSlugCmd {
+		return func(ctx context.Context, askedSlug string) (domain.URLStatistic, error) {
+			assert.Equal(t, slug, askedSlug)
+			return statistic, err
+		}
+	}
+	serve := func(cmd usecase.GetStatisticsForSlugCmd) *httptest.ResponseRecorder {
+		router := NewBuilder(domain.EnvTest).WithGetStatisticsForSlugHandler(cmd).router
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", fmt.Sprintf("%s/statistics/slug/%s", pathPrefixV1, slug), nil)
+		router.ServeHTTP(record, req)
+		return record
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		// When
+		record := serve(mockCmd(domain.URLStatistic{Slug: slug, URL: "https://example.com", ShortenedCounter: 1, AccessedCounter: 10, UniqueVisitors: 4}, nil))
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := GetStatisticsForSlugResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, GetStatisticsForSlugResponse{Slug: slug, URL: "https://example.com", ShortenedCounter: 1, AccessedCounter: 10, UniqueVisitors: 4}, bodyResponse)
+	})
+	t.Run("errors", func(t *testing.T) {
+		scenarios := []struct {
+			Name           string
+			Err            error
+			ExpectedStatus int
+		}{
+			{Name: "not found", Err: shorturl.ErrNotFound, ExpectedStatus: http.StatusNotFound},
+			{Name: "invalid slug", Err: command.ErrInvalidSlugNonAlphanumeric, ExpectedStatus: http.StatusUnprocessableEntity},
+			{Name: "forbidden", Err: usecase.ErrForbidden, ExpectedStatus: http.StatusForbidden},
+			{Name: "internal server error", Err: assert.AnError, ExpectedStatus: http.StatusInternalServerError},
+		}
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				// When
+				record := serve(mockCmd(domain.URLStatistic{}, scenario.Err))
+
+				// Then
+				assert.Equal(t, scenario.ExpectedStatus, record.Code)
+			})
+		}
+	})
+}
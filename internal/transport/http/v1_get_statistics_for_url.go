@@ -15,11 +15,12 @@ type GetStatisticsForURLResponse struct {
 	URL              string `json:"url"`
 	ShortenedCounter int    `json:"shortened_counter"`
 	AccessedCounter  int    `json:"accessed_counter"`
+	UniqueVisitors   int    `json:"unique_visitors"` // Approximate
 }
 
 // WithGetStatisticsForURLHandler register the get statistics for URL API in the router of the HTTP builder
 func (b *Builder) WithGetStatisticsForURLHandler(cmd usecase.GetStatisticsForURLCmd) *Builder {
-	b.router.GET(fmt.Sprintf("%s/statistics", pathPrefixV1), getStatisticsForURLHandler(cmd))
+	b.router.GET(fmt.Sprintf("%s/statistics", pathPrefixV1), b.protected(getStatisticsForURLHandler(cmd))...)
 	return b
 }
 
@@ -54,8 +55,12 @@ func getStatisticsForURLHandler(cmd usecase.GetStatisticsForURLCmd) gin.HandlerF
 				URL:              statistics.URL,
 				ShortenedCounter: statistics.ShortenedCounter,
 				AccessedCounter:  statistics.AccessedCounter,
+				UniqueVisitors:   statistics.UniqueVisitors,
 			})
 			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
 		default:
 			glog.Error(err)
 			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
@@ -21,6 +21,7 @@ func TestWithGetStatisticsForURLHandler(t *testing.T) {
 		URL:              urlToStat,
 		ShortenedCounter: 1,
 		AccessedCounter:  10,
+		UniqueVisitors:   4,
 	}
 	mockCmd := func(expectedURL *string, urlStatistics domain.URLStatistic, err error) usecase.GetStatisticsForURLCmd {
 		return func(ctx context.Context, urlToStat string) (domain.URLStatistic, error) {
@@ -48,6 +49,7 @@ func TestWithGetStatisticsForURLHandler(t *testing.T) {
 		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
 		assert.Equal(t, urlStatistics.URL, bodyResponse.URL)
 		assert.Equal(t, urlStatistics.AccessedCounter, bodyResponse.AccessedCounter)
+		assert.Equal(t, urlStatistics.UniqueVisitors, bodyResponse.UniqueVisitors)
 		assert.Equal(t, urlStatistics.ShortenedCounter, bodyResponse.ShortenedCounter)
 	})
 	t.Run("bad request", func(t *testing.T) {
@@ -80,6 +82,20 @@ func TestWithGetStatisticsForURLHandler(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, record.Code)
 		})
 	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithGetStatisticsForURLHandler(mockCmd(&urlToStat, urlStatistics, usecase.ErrForbidden)).router
+		u, err := url.Parse(fmt.Sprintf("%s/statistics?encoded_url=%s", pathPrefixV1, url.QueryEscape(urlToStat)))
+		require.NoError(t, err)
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
 	t.Run("internal server error", func(t *testing.T) {
 		// Given
 		router := NewBuilder(domain.EnvTest).WithGetStatisticsForURLHandler(mockCmd(&urlToStat, urlStatistics, assert.AnError)).router
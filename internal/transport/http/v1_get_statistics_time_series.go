@@ -0,0 +1,144 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// GetStatisticsTimeSeriesResponse holds the JSON body response structure
+type GetStatisticsTimeSeriesResponse struct {
+	URL         string                   `json:"url"`
+	Granularity string                   `json:"granularity"`
+	Points      []StatisticPointResponse `json:"points"`
+}
+
+// StatisticPointResponse holds the JSON structure of the counters within a bucket of time
+type StatisticPointResponse struct {
+	Time             time.Time `json:"time"`
+	ShortenedCounter int       `json:"shortened_counter"`
+	AccessedCounter  int       `json:"accessed_counter"`
+	UniqueVisitors   int       `json:"unique_visitors"` // Approximate
+}
+
+// WithGetStatisticsTimeSeriesHandler register the get statistics time series API in the router of the HTTP builder
+func (b *Builder) WithGetStatisticsTimeSeriesHandler(cmd usecase.GetStatisticsTimeSeriesCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/statistics/timeseries", pathPrefixV1), b.protected(getStatisticsTimeSeriesHandler(cmd))...)
+	return b
+}
+
+// parseTimeRange parses the optional 'from' and 'to' query parameters, left zero when not given
+// It returns the name of the invalid query parameter along with the parsing error
+func parseTimeRange(c *gin.Context) (time.Time, time.Time, string, error) {
+	var from, to time.Time
+	var err error
+	if fromStr, exists := c.GetQuery("from"); exists {
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, "from", err
+		}
+	}
+	if toStr, exists := c.GetQuery("to"); exists {
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, "to", err
+		}
+	}
+	return from, to, "", nil
+}
+
+// getStatisticsTimeSeriesHandler retrieves the statistics of a given URL per bucket of time
+func getStatisticsTimeSeriesHandler(cmd usecase.GetStatisticsTimeSeriesCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encodedURL, encodedURLExists := c.GetQuery("encoded_url")
+		if !encodedURLExists {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "no URL given as query parameter",
+				Hint:        "add an URL in query parameter name 'encoded_url'",
+			}, nil))
+			return
+		}
+
+		url, err := url.QueryUnescape(encodedURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "unable to unescape given URL",
+				Hint:        "badly encoded URL",
+			}, err))
+			return
+		}
+
+		from, to, invalidParameter, err := parseTimeRange(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: fmt.Sprintf("invalid query parameter '%s'", invalidParameter),
+				Hint:        "'from' and 'to' should be RFC3339 dates",
+			}, err))
+			return
+		}
+
+		granularity := statistics.Granularity(c.DefaultQuery("granularity", string(statistics.GranularityHour)))
+		points, err := cmd(c.Request.Context(), url, granularity, from, to)
+		switch {
+		case err == nil:
+			response := GetStatisticsTimeSeriesResponse{
+				URL:         url,
+				Granularity: string(granularity),
+				Points:      make([]StatisticPointResponse, 0, len(points)),
+			}
+			for _, point := range points {
+				response.Points = append(response.Points, StatisticPointResponse{
+					Time:             point.Time,
+					ShortenedCounter: point.ShortenedCounter,
+					AccessedCounter:  point.AccessedCounter,
+					UniqueVisitors:   point.UniqueVisitors,
+				})
+			}
+			c.JSON(http.StatusOK, response)
+			return
+		case errors.Is(err, usecase.ErrInvalidGranularity):
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "invalid query parameter 'granularity'",
+				Hint:        "'granularity' should be either 'hour' or 'day'",
+			}, err))
+			return
+		case errors.Is(err, usecase.ErrInvalidTimeRange):
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "invalid time range",
+				Hint:        "'from' should be before 'to' and the range should hold at most 1000 buckets",
+			}, err))
+			return
+		case errors.Is(err, command.ErrInvalidURL):
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given URL is invalid",
+				Hint:        "the URL should respect the RFC: https://datatracker.ietf.org/doc/html/rfc1738 ",
+			}, err))
+			return
+		case errors.Is(err, usecase.ErrForbidden):
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithGetStatisticsTimeSeriesHandler(t *testing.T) {
+	urlToStat := "https://example.com"
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	points := []domain.URLStatisticPoint{
+		{Time: from, AccessedCounter: 10, ShortenedCounter: 1, UniqueVisitors: 3},
+		{Time: from.Add(time.Hour), AccessedCounter: 5},
+	}
+	mockCmd := func(points []domain.URLStatisticPoint, err error) usecase.GetStatisticsTimeSeriesCmd {
+		return func(ctx context.Context, url string, granularity statistics.Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error) {
+			assert.Equal(t, urlToStat, url)
+			return points, err
+		}
+	}
+	serve := func(cmd usecase.GetStatisticsTimeSeriesCmd, query string) *httptest.ResponseRecorder {
+		router := NewBuilder(domain.EnvTest).WithGetStatisticsTimeSeriesHandler(cmd).router
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", fmt.Sprintf("%s/statistics/timeseries?%s", pathPrefixV1, query), nil)
+		router.ServeHTTP(record, req)
+		return record
+	}
+	encodedURL := "encoded_url=" + url.QueryEscape(urlToStat)
+
+	t.Run("ok", func(t *testing.T) {
+		// Given
+		cmd := func(ctx context.Context, url string, granularity statistics.Granularity, askedFrom time.Time, askedTo time.Time) ([]domain.URLStatisticPoint, error) {
+			assert.Equal(t, urlToStat, url)
+			assert.Equal(t, statistics.GranularityHour, granularity)
+			assert.True(t, from.Equal(askedFrom))
+			assert.True(t, to.Equal(askedTo))
+			return points, nil
+		}
+
+		// When
+		record := serve(cmd, fmt.Sprintf("%s&granularity=hour&from=%s&to=%s", encodedURL, from.Format(time.RFC3339), to.Format(time.RFC3339)))
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := GetStatisticsTimeSeriesResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, urlToStat, bodyResponse.URL)
+		assert.Equal(t, "hour", bodyResponse.Granularity)
+		require.Len(t, bodyResponse.Points, 2)
+		assert.True(t, from.Equal(bodyResponse.Points[0].Time))
+		assert.Equal(t, 10, bodyResponse.Points[0].AccessedCounter)
+		assert.Equal(t, 3, bodyResponse.Points[0].UniqueVisitors)
+		assert.Equal(t, 1, bodyResponse.Points[0].ShortenedCounter)
+		assert.Equal(t, 5, bodyResponse.Points[1].AccessedCounter)
+	})
+	t.Run("bad request", func(t *testing.T) {
+		scenarios := []struct {
+			Name  string
+			Query string
+			Err   error
+		}{
+			{Name: "missing encoded_url query parameter", Query: "granularity=hour"},
+			{Name: "badly encoded URL", Query: "encoded_url=https://badly-encoded.com%"},
+			{Name: "invalid from", Query: encodedURL + "&from=yesterday"},
+			{Name: "invalid to", Query: encodedURL + "&to=tomorrow"},
+			{Name: "invalid granularity", Query: encodedURL + "&granularity=week", Err: usecase.ErrInvalidGranularity},
+			{Name: "invalid time range", Query: encodedURL, Err: usecase.ErrInvalidTimeRange},
+		}
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				// When
+				record := serve(mockCmd(nil, scenario.Err), scenario.Query)
+
+				// Then
+				assert.Equal(t, http.StatusBadRequest, record.Code)
+			})
+		}
+	})
+	t.Run("invalid URL", func(t *testing.T) {
+		// When
+		record := serve(mockCmd(nil, command.ErrInvalidURL), encodedURL)
+
+		// Then
+		assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// When
+		record := serve(mockCmd(nil, usecase.ErrForbidden), encodedURL)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// When
+		record := serve(mockCmd(nil, assert.AnError), encodedURL)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -24,8 +24,8 @@ type getTopStatisticsForURLResponse struct {
 
 // WithGetTopStatisticsHandler register the get top statistics API in the router of the HTTP builder
 func (b *Builder) WithGetTopStatisticsHandler(cmd usecase.GetTopStatisticsCmd) *Builder {
-	b.router.GET(fmt.Sprintf("%s/statistics/accessed", pathPrefixV1), getTopStatisticsHandler(statistics.StatisticTypeAccessed, cmd))
-	b.router.GET(fmt.Sprintf("%s/statistics/shortened", pathPrefixV1), getTopStatisticsHandler(statistics.StatisticTypeShortened, cmd))
+	b.router.GET(fmt.Sprintf("%s/statistics/accessed", pathPrefixV1), b.protected(getTopStatisticsHandler(statistics.StatisticTypeAccessed, cmd))...)
+	b.router.GET(fmt.Sprintf("%s/statistics/shortened", pathPrefixV1), b.protected(getTopStatisticsHandler(statistics.StatisticTypeShortened, cmd))...)
 	return b
 }
 
@@ -60,6 +60,9 @@ func getTopStatisticsHandler(statType statistics.StatisticType, cmd usecase.GetT
 			}
 			c.JSON(http.StatusOK, response)
 			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
 		default:
 			glog.Error(err)
 			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
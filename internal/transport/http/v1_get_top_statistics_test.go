@@ -97,6 +97,20 @@ func TestWithGetTopStatisticsHandler(t *testing.T) {
 			// Then
 			assert.Equal(t, http.StatusBadRequest, record.Code)
 		})
+		t.Run("forbidden", func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithGetTopStatisticsHandler(mockCmd(nil, 0, topAccessedStatistics, usecase.ErrForbidden)).router
+			u, err := url.Parse(fmt.Sprintf("%s/statistics/accessed", pathPrefixV1))
+			require.NoError(t, err)
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", u.String(), nil)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusForbidden, record.Code)
+		})
 		t.Run("internal server error", func(t *testing.T) {
 			// Given
 			router := NewBuilder(domain.EnvTest).WithGetTopStatisticsHandler(mockCmd(nil, 0, topAccessedStatistics, assert.AnError)).router
@@ -161,6 +175,20 @@ func TestWithGetTopStatisticsHandler(t *testing.T) {
 			// Then
 			assert.Equal(t, http.StatusBadRequest, record.Code)
 		})
+		t.Run("forbidden", func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithGetTopStatisticsHandler(mockCmd(nil, 0, topShortenedStatistics, usecase.ErrForbidden)).router
+			u, err := url.Parse(fmt.Sprintf("%s/statistics/shortened", pathPrefixV1))
+			require.NoError(t, err)
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", u.String(), nil)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusForbidden, record.Code)
+		})
 		t.Run("internal server error", func(t *testing.T) {
 			// Given
 			router := NewBuilder(domain.EnvTest).WithGetTopStatisticsHandler(mockCmd(nil, 0, topShortenedStatistics, assert.AnError)).router
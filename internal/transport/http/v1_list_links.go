@@ -0,0 +1,123 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// ListLinksResponse holds the JSON body response structure of a links listing
+type ListLinksResponse struct {
+	Links      []LinkResponse `json:"links"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// WithV1ListLinksHandler register the list links API in the router of the HTTP builder
+func (b *Builder) WithV1ListLinksHandler(cmd usecase.ListLinksCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/links", pathPrefixV1), b.protected(v1ListLinksHandler(cmd))...)
+	return b
+}
+
+// parseListFilter parses the query parameters of a links listing into a filter
+// It returns the name of the invalid query parameter along with the parsing error
+func parseListFilter(c *gin.Context) (shorturl.ListFilter, string, error) {
+	filter := shorturl.ListFilter{
+		Domain:   c.Query("domain"),
+		Contains: c.Query("contains"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	if limitStr, exists := c.GetQuery("limit"); exists {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return shorturl.ListFilter{}, "limit", err
+		}
+		filter.Limit = limit
+	}
+
+	if fromStr, exists := c.GetQuery("from"); exists {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return shorturl.ListFilter{}, "from", err
+		}
+		filter.InsertedFrom = &from
+	}
+
+	if toStr, exists := c.GetQuery("to"); exists {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return shorturl.ListFilter{}, "to", err
+		}
+		filter.InsertedTo = &to
+	}
+
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "asc":
+		filter.Ascending = true
+	case "desc":
+	default:
+		return shorturl.ListFilter{}, "order", fmt.Errorf("unknown order %q", order)
+	}
+
+	return filter, "", nil
+}
+
+// v1ListLinksHandler lists a page of links matching the query filters
+func v1ListLinksHandler(cmd usecase.ListLinksCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, invalidParameter, err := parseListFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: fmt.Sprintf("invalid query parameter '%s'", invalidParameter),
+				Hint:        "'limit' should be an integer, 'from' and 'to' RFC3339 dates and 'order' either 'asc' or 'desc'",
+			}, err))
+			return
+		}
+
+		page, err := cmd(c.Request.Context(), filter)
+		switch err {
+		case nil:
+			response := ListLinksResponse{
+				Links:      make([]LinkResponse, 0, len(page.URLMappings)),
+				NextCursor: page.NextCursor,
+			}
+			for _, urlMapping := range page.URLMappings {
+				response.Links = append(response.Links, newLinkResponse(urlMapping))
+			}
+			c.JSON(http.StatusOK, response)
+			return
+		case shorturl.ErrInvalidCursor:
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "invalid query parameter 'cursor'",
+				Hint:        "use the 'next_cursor' value of a previous listing response",
+			}, err))
+			return
+		case usecase.ErrInvalidListLimit:
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "invalid query parameter 'limit'",
+				Hint:        "'limit' should be between 1 and 1000",
+			}, err))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1ListLinksHandler(t *testing.T) {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	urlMapping := domain.URLMapping{
+		Slug:        "zTw34enA",
+		OriginalURL: "https://example.com/needs-to-be-shortened",
+		InsertedAt:  from.Add(time.Hour),
+	}
+	mockCmd := func(expectedFilter shorturl.ListFilter, err error) usecase.ListLinksCmd {
+		return func(ctx context.Context, filter shorturl.ListFilter) (shorturl.ListPage, error) {
+			assert.Equal(t, expectedFilter, filter)
+			if err != nil {
+				return shorturl.ListPage{}, err
+			}
+			return shorturl.ListPage{URLMappings: []domain.URLMapping{urlMapping}, NextCursor: "next"}, nil
+		}
+	}
+	buildURL := func(query url.Values) string {
+		u, err := url.Parse(fmt.Sprintf("%s/links", pathPrefixV1))
+		require.NoError(t, err)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		// Given
+		expectedFilter := shorturl.ListFilter{
+			Domain:       "example.com",
+			Contains:     "shortened",
+			InsertedFrom: &from,
+			InsertedTo:   &to,
+			Ascending:    true,
+			Cursor:       "cursor",
+			Limit:        10,
+		}
+		router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(expectedFilter, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", buildURL(url.Values{
+			"domain":   {"example.com"},
+			"contains": {"shortened"},
+			"from":     {from.Format(time.RFC3339)},
+			"to":       {to.Format(time.RFC3339)},
+			"order":    {"asc"},
+			"cursor":   {"cursor"},
+			"limit":    {"10"},
+		}), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := ListLinksResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		require.Len(t, bodyResponse.Links, 1)
+		assert.Equal(t, urlMapping.Slug, bodyResponse.Links[0].Slug)
+		assert.Equal(t, urlMapping.OriginalURL, bodyResponse.Links[0].OriginalURL)
+		assert.Equal(t, "next", bodyResponse.NextCursor)
+	})
+	t.Run("ok without filters", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(shorturl.ListFilter{}, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", buildURL(nil), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+	})
+	t.Run("bad request on query parameters", func(t *testing.T) {
+		for _, query := range []url.Values{
+			{"limit": {"ten"}},
+			{"from": {"yesterday"}},
+			{"to": {"2024-08-01"}},
+			{"order": {"random"}},
+		} {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(shorturl.ListFilter{}, nil)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", buildURL(query), nil)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusBadRequest, record.Code, query.Encode())
+		}
+	})
+	t.Run("bad request on invalid cursor", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(shorturl.ListFilter{Cursor: "invalid"}, shorturl.ErrInvalidCursor)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", buildURL(url.Values{"cursor": {"invalid"}}), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("bad request on invalid limit", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(shorturl.ListFilter{Limit: 5000}, usecase.ErrInvalidListLimit)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", buildURL(url.Values{"limit": {"5000"}}), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(shorturl.ListFilter{}, usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", buildURL(nil), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ListLinksHandler(mockCmd(shorturl.ListFilter{}, assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", buildURL(nil), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// LookupLinksResponse holds the JSON body response structure of a reverse lookup
+type LookupLinksResponse struct {
+	URL   string         `json:"url"`
+	Links []LinkResponse `json:"links"`
+}
+
+// WithV1LookupLinksHandler register the lookup links API in the router of the HTTP builder
+func (b *Builder) WithV1LookupLinksHandler(cmd usecase.LookupLinksCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/lookup", pathPrefixV1), b.protected(v1LookupLinksHandler(cmd))...)
+	return b
+}
+
+// v1LookupLinksHandler retrieves the links already associated to a destination URL
+func v1LookupLinksHandler(cmd usecase.LookupLinksCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encodedURL, encodedURLExists := c.GetQuery("encoded_url")
+		if !encodedURLExists {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "no URL given as query parameter",
+				Hint:        "add an URL in query parameter name 'encoded_url'",
+			}, nil))
+			return
+		}
+
+		url, err := url.QueryUnescape(encodedURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "unable to unescape given URL",
+				Hint:        "badly encoded URL",
+			}, err))
+			return
+		}
+
+		urlMappings, err := cmd(c.Request.Context(), url)
+		switch {
+		case err == nil:
+			response := LookupLinksResponse{URL: url, Links: make([]LinkResponse, 0, len(urlMappings))}
+			for _, urlMapping := range urlMappings {
+				response.Links = append(response.Links, newLinkResponse(urlMapping))
+			}
+			c.JSON(http.StatusOK, response)
+			return
+		case errors.Is(err, command.ErrInvalidURL):
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given URL is invalid",
+				Hint:        "the URL should respect the RFC: https://datatracker.ietf.org/doc/html/rfc1738 ",
+			}, err))
+			return
+		case errors.Is(err, usecase.ErrForbidden):
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
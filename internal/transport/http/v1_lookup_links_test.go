@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1LookupLinksHandler(t *testing.T) {
+	originalURL := "https://example.com/campaign"
+	urlMappings := []domain.URLMapping{
+		{Slug: "zTw34enA", OriginalURL: originalURL},
+		{Slug: "spring-sale", OriginalURL: originalURL},
+	}
+	mockCmd := func(err error) usecase.LookupLinksCmd {
+		return func(ctx context.Context, url string) ([]domain.URLMapping, error) {
+			assert.Equal(t, originalURL, url)
+			if err != nil {
+				return nil, err
+			}
+			return urlMappings, nil
+		}
+	}
+	u, err := url.Parse(fmt.Sprintf("%s/lookup", pathPrefixV1))
+	require.NoError(t, err)
+	q := u.Query()
+	q.Set("encoded_url", url.QueryEscape(originalURL))
+	u.RawQuery = q.Encode()
+
+	t.Run("ok", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1LookupLinksHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := LookupLinksResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, originalURL, bodyResponse.URL)
+		require.Len(t, bodyResponse.Links, 2)
+		assert.Equal(t, "zTw34enA", bodyResponse.Links[0].Slug)
+		assert.Equal(t, "spring-sale", bodyResponse.Links[1].Slug)
+	})
+	t.Run("bad request", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1LookupLinksHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", fmt.Sprintf("%s/lookup", pathPrefixV1), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("unprocessable entity", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1LookupLinksHandler(mockCmd(fmt.Errorf("%w: %w", command.ErrInvalidURL, assert.AnError))).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1LookupLinksHandler(mockCmd(usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1LookupLinksHandler(mockCmd(assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", u.String(), nil)
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// WithV1MetricsHandler register the metrics API in the router of the HTTP builder
+// It is restricted to the admins as expvar also exposes the command line and the memory statistics of the process
+func (b *Builder) WithV1MetricsHandler(authorizeCmd usecase.AuthorizeCmd) *Builder {
+	b.router.GET(fmt.Sprintf("%s/metrics", pathPrefixV1), b.protected(v1MetricsHandler(authorizeCmd))...)
+	return b
+}
+
+// v1MetricsHandler exposes the service metrics published with expvar as JSON
+func v1MetricsHandler(authorizeCmd usecase.AuthorizeCmd) gin.HandlerFunc {
+	expvarHandler := gin.WrapH(expvar.Handler())
+	return func(c *gin.Context) {
+		err := authorizeCmd(c.Request.Context(), domain.PermissionReadMetrics)
+		switch err {
+		case nil:
+			expvarHandler(c)
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
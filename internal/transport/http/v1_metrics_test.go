@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1MetricsHandler(t *testing.T) {
+	// Given
+	metric := expvar.NewInt("test_metric")
+	metric.Set(42)
+	router := NewBuilder(domain.EnvTest).WithV1MetricsHandler(usecase.AuthorizeCmdBuilder(false)).router
+
+	// When
+	u, err := url.Parse(fmt.Sprintf("%s/metrics", pathPrefixV1))
+	require.NoError(t, err)
+	record := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", u.String(), nil)
+	router.ServeHTTP(record, req)
+
+	// Then
+	assert.Equal(t, http.StatusOK, record.Code)
+	bodyResponse := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+	assert.Equal(t, float64(42), bodyResponse["test_metric"])
+}
+
+func TestWithV1MetricsHandlerProtected(t *testing.T) {
+	authenticateAPIKeyCmd := func(ctx context.Context, secret string) (domain.APIKey, error) {
+		switch secret {
+		case "usk_admin":
+			return domain.APIKey{ID: "key-1", Role: domain.RoleAdmin}, nil
+		case "usk_editor":
+			return domain.APIKey{ID: "key-2", Role: domain.RoleEditor}, nil
+		case "usk_viewer":
+			return domain.APIKey{ID: "key-3", Role: domain.RoleViewer}, nil
+		default:
+			return domain.APIKey{}, usecase.ErrInvalidAPIKey
+		}
+	}
+
+	scenarios := []struct {
+		Name     string
+		Secret   string
+		Expected int
+	}{
+		{Name: "admin", Secret: "usk_admin", Expected: http.StatusOK},
+		{Name: "editor", Secret: "usk_editor", Expected: http.StatusForbidden},
+		{Name: "viewer", Secret: "usk_viewer", Expected: http.StatusForbidden},
+		{Name: "invalid API key", Secret: "usk_invalid", Expected: http.StatusUnauthorized},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithAPIKeyAuthentication(authenticateAPIKeyCmd).WithV1MetricsHandler(usecase.AuthorizeCmdBuilder(true)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", fmt.Sprintf("%s/metrics", pathPrefixV1), nil)
+			req.Header.Set("Authorization", "Bearer "+scenario.Secret)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, scenario.Expected, record.Code)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// WithV1PurgeLinkHandler register the purge link API in the router of the HTTP builder
+func (b *Builder) WithV1PurgeLinkHandler(cmd usecase.PurgeLinkCmd) *Builder {
+	b.router.POST(fmt.Sprintf("%s/links/:slug/purge", pathPrefixV1), b.protected(v1PurgeLinkHandler(cmd))...)
+	return b
+}
+
+// v1PurgeLinkHandler purges a link given a slug, along with its tombstone
+func v1PurgeLinkHandler(cmd usecase.PurgeLinkCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := cmd(c.Request.Context(), c.Param("slug"))
+		switch err {
+		case nil:
+			c.Status(http.StatusNoContent)
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(ApiError{
+				Name:        "forbidden",
+				Description: "the API key is not allowed to purge links",
+				Hint:        "only an admin API key can purge a link",
+			}, err))
+			return
+		case shorturl.ErrNotFound:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "no URL nor tombstone found associated to the given slug",
+				Hint:        "the slug might be incorrect or already purged",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithV1PurgeLinkHandler(t *testing.T) {
+	slug := "zTw34enA"
+	path := fmt.Sprintf("%s/links/%s/purge", pathPrefixV1, slug)
+	mockCmd := func(err error) usecase.PurgeLinkCmd {
+		return func(ctx context.Context, s string) error {
+			assert.Equal(t, slug, s)
+			return err
+		}
+	}
+
+	scenarios := []struct {
+		Name     string
+		Err      error
+		Expected int
+	}{
+		{Name: "no content", Err: nil, Expected: http.StatusNoContent},
+		{Name: "forbidden", Err: usecase.ErrForbidden, Expected: http.StatusForbidden},
+		{Name: "not found", Err: shorturl.ErrNotFound, Expected: http.StatusNotFound},
+		{Name: "unprocessable entity", Err: command.ErrInvalidSlugLenght, Expected: http.StatusUnprocessableEntity},
+		{Name: "internal server error", Err: assert.AnError, Expected: http.StatusInternalServerError},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1PurgeLinkHandler(mockCmd(scenario.Err)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", path, nil)
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, scenario.Expected, record.Code)
+		})
+	}
+}
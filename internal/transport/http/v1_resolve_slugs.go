@@ -0,0 +1,125 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// ResolveSlugsRequest holds the JSON body request structure of a batch resolution
+type ResolveSlugsRequest struct {
+	Slugs       []string `json:"slugs" binding:"required"`
+	CountAccess bool     `json:"count_access"` // Counts the resolutions toward the accessed statistics
+}
+
+// ResolveSlugsResponse holds the JSON body response structure of a batch resolution
+type ResolveSlugsResponse struct {
+	Results []ResolveSlugsResultResponse `json:"results"`
+}
+
+// ResolveSlugsResultResponse holds the result of a slug of a batch resolution, in the same order as the request
+type ResolveSlugsResultResponse struct {
+	Slug        string    `json:"slug"`
+	Status      int       `json:"status"`
+	OriginalURL string    `json:"original_url,omitempty"`
+	Error       *ApiError `json:"error,omitempty"`
+}
+
+// WithV1ResolveSlugsHandler register the resolve slugs batch API in the router of the HTTP builder
+func (b *Builder) WithV1ResolveSlugsHandler(cmd usecase.ResolveSlugsCmd) *Builder {
+	b.router.POST(fmt.Sprintf("%s/resolve/batch", pathPrefixV1), b.protectedRateLimited(usecase.RateLimitRouteResolveBatch, v1ResolveSlugsHandler(cmd))...)
+	return b
+}
+
+// resolveSlugAPIError returns the HTTP status and the API error of a failed slug resolution
+func resolveSlugAPIError(err error) (int, ApiError) {
+	switch {
+	case errors.Is(err, shorturl.ErrNotFound):
+		return http.StatusNotFound, ApiError{
+			Name:        "not_found",
+			Description: "no URL found associated to the given slug",
+			Hint:        "the slug might be incorrect",
+		}
+	case errors.Is(err, shorturl.ErrExpired):
+		return http.StatusGone, ApiError{
+			Name:        "gone",
+			Description: "the URL associated to the given slug has expired",
+			Hint:        "ask the owner of the link for a new one",
+		}
+	case errors.Is(err, usecase.ErrLinkDisabled):
+		return http.StatusGone, ApiError{
+			Name:        "disabled",
+			Description: "the URL associated to the given slug has been disabled",
+			Hint:        "ask the owner of the link for a new one",
+		}
+	case errors.Is(err, command.ErrInvalidSlugLenght), errors.Is(err, command.ErrInvalidSlugNonAlphanumeric):
+		return http.StatusUnprocessableEntity, ApiError{
+			Name:        "unprocessable_entity",
+			Description: "the given slug is invalid",
+			Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+		}
+	default:
+		glog.Error(err)
+		return http.StatusInternalServerError, ApiError{
+			Name:        "internal_server_error",
+			Description: "unknown error",
+			Hint:        "if you are the application owner, please check the logs for more details",
+		}
+	}
+}
+
+// v1ResolveSlugsHandler retrieves the original URLs of several slugs at once
+func v1ResolveSlugsHandler(cmd usecase.ResolveSlugsCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var resolveSlugsRequest ResolveSlugsRequest
+		err := c.ShouldBindJSON(&resolveSlugsRequest)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "can't parse JSON body",
+				Hint:        "the body should be JSON with application/json and required fields",
+			}, err))
+			return
+		}
+
+		results, err := cmd(c.Request.Context(), resolveSlugsRequest.Slugs, resolveSlugsRequest.CountAccess)
+		switch err {
+		case nil:
+			response := ResolveSlugsResponse{Results: make([]ResolveSlugsResultResponse, len(results))}
+			for i, result := range results {
+				if result.Err != nil {
+					status, apiError := resolveSlugAPIError(result.Err)
+					response.Results[i] = ResolveSlugsResultResponse{Slug: resolveSlugsRequest.Slugs[i], Status: status, Error: &apiError}
+					continue
+				}
+				response.Results[i] = ResolveSlugsResultResponse{Slug: resolveSlugsRequest.Slugs[i], Status: http.StatusOK, OriginalURL: result.OriginalURL}
+			}
+			c.JSON(http.StatusOK, response)
+			return
+		case usecase.ErrInvalidBatchSize:
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "invalid batch size",
+				Hint:        "a batch should hold between 1 and 1 000 slugs",
+			}, err))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(forbiddenAPIError, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
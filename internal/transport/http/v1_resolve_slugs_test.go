@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1ResolveSlugsHandler(t *testing.T) {
+	path := fmt.Sprintf("%s/resolve/batch", pathPrefixV1)
+	slugs := []string{"zTw34enA", "invalid!", "unknown", "expired"}
+	mockCmd := func(expectedCountAccess bool, results []usecase.ResolveSlugResult, err error) usecase.ResolveSlugsCmd {
+		return func(ctx context.Context, requestedSlugs []string, countAccess bool) ([]usecase.ResolveSlugResult, error) {
+			assert.Equal(t, slugs, requestedSlugs)
+			assert.Equal(t, expectedCountAccess, countAccess)
+			return results, err
+		}
+	}
+	body := `{"slugs": ["zTw34enA", "invalid!", "unknown", "expired"], "count_access": true}`
+
+	t.Run("ok", func(t *testing.T) {
+		// Given
+		results := []usecase.ResolveSlugResult{
+			{OriginalURL: "https://example.com/1"},
+			{Err: command.ErrInvalidSlugNonAlphanumeric},
+			{Err: shorturl.ErrNotFound},
+			{Err: shorturl.ErrExpired},
+		}
+		router := NewBuilder(domain.EnvTest).WithV1ResolveSlugsHandler(mockCmd(true, results, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := ResolveSlugsResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		require.Len(t, bodyResponse.Results, 4)
+		assert.Equal(t, ResolveSlugsResultResponse{Slug: "zTw34enA", Status: http.StatusOK, OriginalURL: "https://example.com/1"}, bodyResponse.Results[0])
+		assert.Equal(t, http.StatusUnprocessableEntity, bodyResponse.Results[1].Status)
+		assert.Equal(t, http.StatusNotFound, bodyResponse.Results[2].Status)
+		assert.Equal(t, "unknown", bodyResponse.Results[2].Slug)
+		assert.Equal(t, http.StatusGone, bodyResponse.Results[3].Status)
+		require.NotNil(t, bodyResponse.Results[3].Error)
+		assert.Equal(t, "gone", bodyResponse.Results[3].Error.Name)
+	})
+	t.Run("bad request on malformed body", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ResolveSlugsHandler(mockCmd(true, nil, nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"count_access": true}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("bad request on invalid batch size", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ResolveSlugsHandler(mockCmd(true, nil, usecase.ErrInvalidBatchSize)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ResolveSlugsHandler(mockCmd(true, nil, usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1ResolveSlugsHandler(mockCmd(true, nil, assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
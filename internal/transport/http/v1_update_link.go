@@ -0,0 +1,97 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/malwarescanner"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// UpdateLinkRequest holds the JSON body request structure
+type UpdateLinkRequest struct {
+	OriginalURL string `json:"original_url" binding:"required"`
+}
+
+// WithV1UpdateLinkHandler register the update link API in the router of the HTTP builder
+func (b *Builder) WithV1UpdateLinkHandler(cmd usecase.UpdateLinkCmd) *Builder {
+	b.router.PATCH(fmt.Sprintf("%s/links/:slug", pathPrefixV1), b.protected(v1UpdateLinkHandler(cmd))...)
+	return b
+}
+
+// v1UpdateLinkHandler changes the original URL of a link given a slug
+func v1UpdateLinkHandler(cmd usecase.UpdateLinkCmd) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var updateLinkRequest UpdateLinkRequest
+		err := c.ShouldBindJSON(&updateLinkRequest)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, CreateAPIError(ApiError{
+				Name:        "bad_request",
+				Description: "can't parse JSON body",
+				Hint:        "the body should be JSON with application/json and required fields",
+			}, err))
+			return
+		}
+
+		urlMapping, err := cmd(c.Request.Context(), c.Param("slug"), updateLinkRequest.OriginalURL)
+		switch err {
+		case nil:
+			c.JSON(http.StatusOK, newLinkResponse(urlMapping))
+			return
+		case malwarescanner.ErrMalswareURL:
+			c.JSON(http.StatusForbidden, CreateAPIError(ApiError{
+				Name:        "forbidden",
+				Description: "a malware has been detected within the given original_url",
+				Hint:        "the link can't target a malicious URL",
+			}, err))
+			return
+		case usecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, CreateAPIError(ApiError{
+				Name:        "forbidden",
+				Description: "the API key is not allowed to modify this link",
+				Hint:        "only the editor API key that created the link or an admin API key can modify it",
+			}, err))
+			return
+		case shorturl.ErrExpired:
+			c.JSON(http.StatusGone, CreateAPIError(ApiError{
+				Name:        "gone",
+				Description: "the URL associated to the given slug has expired",
+				Hint:        "ask the owner of the link for a new one",
+			}, err))
+			return
+		case shorturl.ErrNotFound:
+			c.JSON(http.StatusNotFound, CreateAPIError(ApiError{
+				Name:        "not_found",
+				Description: "no URL found associated to the given slug",
+				Hint:        "the slug might be incorrect or expired",
+			}, err))
+			return
+		case command.ErrInvalidURL:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given original_url is invalid",
+				Hint:        "the URL should respect the RFC: https://datatracker.ietf.org/doc/html/rfc1738 ",
+			}, err))
+			return
+		case command.ErrInvalidSlugLenght, command.ErrInvalidSlugNonAlphanumeric:
+			c.JSON(http.StatusUnprocessableEntity, CreateAPIError(ApiError{
+				Name:        "unprocessable_entity",
+				Description: "the given slug is invalid",
+				Hint:        "the slug should be alpha numeric (or use the configuration allowed characters) and less than the configuration setted maximal lenght",
+			}, err))
+			return
+		default:
+			glog.Error(err)
+			c.JSON(http.StatusInternalServerError, CreateAPIError(ApiError{
+				Name:        "internal_server_error",
+				Description: "unknown error",
+				Hint:        "if you are the application owner, please check the logs for more details",
+			}, err))
+			return
+		}
+	}
+}
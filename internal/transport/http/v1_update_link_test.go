@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/malwarescanner"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1UpdateLinkHandler(t *testing.T) {
+	slug := "zTw34enA"
+	originalURL := "https://my-new-url.com/retargeted"
+	u, err := url.Parse(fmt.Sprintf("%s/links/%s", pathPrefixV1, slug))
+	require.NoError(t, err)
+	mockCmd := func(err error) usecase.UpdateLinkCmd {
+		return func(ctx context.Context, s string, urlToTarget string) (domain.URLMapping, error) {
+			assert.Equal(t, slug, s)
+			assert.Equal(t, originalURL, urlToTarget)
+			if err != nil {
+				return domain.URLMapping{}, err
+			}
+			return domain.URLMapping{Slug: s, OriginalURL: urlToTarget}, nil
+		}
+	}
+	body := fmt.Sprintf(`{"original_url": "%s"}`, originalURL)
+
+	t.Run("ok", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusOK, record.Code)
+		bodyResponse := LinkResponse{}
+		require.NoError(t, json.Unmarshal(record.Body.Bytes(), &bodyResponse))
+		assert.Equal(t, slug, bodyResponse.Slug)
+		assert.Equal(t, originalURL, bodyResponse.OriginalURL)
+	})
+	t.Run("bad request", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(nil)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(`{}`))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusBadRequest, record.Code)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(malwarescanner.ErrMalswareURL)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("forbidden owner", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(usecase.ErrForbidden)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, record.Code)
+	})
+	t.Run("not found", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(shorturl.ErrNotFound)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusNotFound, record.Code)
+	})
+	t.Run("unprocessable entity", func(t *testing.T) {
+		t.Run("invalid URL", func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(command.ErrInvalidURL)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+		})
+		t.Run("invalid slug", func(t *testing.T) {
+			// Given
+			router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(command.ErrInvalidSlugNonAlphanumeric)).router
+
+			// When
+			record := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+			router.ServeHTTP(record, req)
+
+			// Then
+			assert.Equal(t, http.StatusUnprocessableEntity, record.Code)
+		})
+	})
+	t.Run("internal server error", func(t *testing.T) {
+		// Given
+		router := NewBuilder(domain.EnvTest).WithV1UpdateLinkHandler(mockCmd(assert.AnError)).router
+
+		// When
+		record := httptest.NewRecorder()
+		req := httptest.NewRequest("PATCH", u.String(), strings.NewReader(body))
+		router.ServeHTTP(record, req)
+
+		// Then
+		assert.Equal(t, http.StatusInternalServerError, record.Code)
+	})
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/apikey"
+)
+
+var (
+	// ErrInvalidAPIKey is the error when the given API key is missing or unknown
+	ErrInvalidAPIKey error = errors.New("api key is invalid")
+)
+
+// AuthenticateAPIKeyCmd represents the function signature of the command that authenticates an API key given its secret
+type AuthenticateAPIKeyCmd func(ctx context.Context, secret string) (domain.APIKey, error)
+
+// authenticateAPIKey retrieves the API key matching the hash of the secret
+func authenticateAPIKey(apiKeyHasherCmd command.APIKeyHasherCmd, apiKeyStore apikey.Store) AuthenticateAPIKeyCmd {
+	return func(ctx context.Context, secret string) (domain.APIKey, error) {
+		if secret == "" {
+			return domain.APIKey{}, ErrInvalidAPIKey
+		}
+
+		apiKey, err := apiKeyStore.Get(ctx, apiKeyHasherCmd(secret))
+		if err != nil {
+			if errors.Is(err, apikey.ErrNotFound) {
+				return domain.APIKey{}, ErrInvalidAPIKey
+			}
+			return domain.APIKey{}, err
+		}
+		return apiKey, nil
+	}
+}
+
+// AuthenticateAPIKeyCmdBuilder builds the command that will authenticate an API key
+func AuthenticateAPIKeyCmdBuilder(apiKeyHasherCmd command.APIKeyHasherCmd, apiKeyStore apikey.Store) AuthenticateAPIKeyCmd {
+	return authenticateAPIKey(apiKeyHasherCmd, apiKeyStore)
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/apikey"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateAPIKeyCmdBuilder(t *testing.T) {
+	apiKeyHasherStub := func(secret string) string {
+		return "hash-of-" + secret
+	}
+	var apiKey domain.APIKey = domain.APIKey{ID: "key-1", Name: "marketing", Hash: "hash-of-usk_secret"}
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		apiKeyMock := apikey.NewMockStore(t)
+		apiKeyMock.On("Get", mock.Anything, "hash-of-usk_secret").Return(apiKey, nil)
+		cmd := AuthenticateAPIKeyCmdBuilder(apiKeyHasherStub, apiKeyMock)
+
+		// When
+		authenticatedAPIKey, err := cmd(context.Background(), "usk_secret")
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, apiKey, authenticatedAPIKey)
+	})
+	t.Run("missing secret", func(t *testing.T) {
+		// Given
+		cmd := AuthenticateAPIKeyCmdBuilder(apiKeyHasherStub, apikey.NewMockStore(t))
+
+		// When
+		authenticatedAPIKey, err := cmd(context.Background(), "")
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+		assert.Empty(t, authenticatedAPIKey)
+	})
+	t.Run("unknown secret", func(t *testing.T) {
+		// Given
+		apiKeyMock := apikey.NewMockStore(t)
+		apiKeyMock.On("Get", mock.Anything, "hash-of-usk_unknown").Return(domain.APIKey{}, apikey.ErrNotFound)
+		cmd := AuthenticateAPIKeyCmdBuilder(apiKeyHasherStub, apiKeyMock)
+
+		// When
+		authenticatedAPIKey, err := cmd(context.Background(), "usk_unknown")
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+		assert.Empty(t, authenticatedAPIKey)
+	})
+	t.Run("failed retrieving API key", func(t *testing.T) {
+		// Given
+		apiKeyMock := apikey.NewMockStore(t)
+		apiKeyMock.On("Get", mock.Anything, mock.Anything).Return(domain.APIKey{}, assert.AnError)
+		cmd := AuthenticateAPIKeyCmdBuilder(apiKeyHasherStub, apiKeyMock)
+
+		// When
+		authenticatedAPIKey, err := cmd(context.Background(), "usk_secret")
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, authenticatedAPIKey)
+	})
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+var (
+	// ErrForbidden is the error when the authenticated API key is not allowed to perform an action
+	ErrForbidden error = errors.New("forbidden")
+)
+
+// ownerOf returns the ID of the authenticated API key of the context, empty if the authentication is disabled
+func ownerOf(ctx context.Context) string {
+	apiKey, _ := domain.APIKeyFromContext(ctx)
+	return apiKey.ID
+}
+
+// slugSeedOf returns what a slug is generated from, the URL salted by its owner so that each owner gets its own slug for a same URL
+func slugSeedOf(owner string, url string) string {
+	if owner == "" {
+		return url
+	}
+	return owner + "|" + url
+}
+
+// AuthorizeCmd represents the function signature of the command that checks the role of the authenticated API key of the context grants a permission
+type AuthorizeCmd func(ctx context.Context, permission domain.Permission) error
+
+// authorize returns ErrForbidden if the role of the authenticated API key of the context does not grant the permission
+// Without authenticated API key, it returns ErrForbidden unless the authentication is disabled, in which case everything is allowed
+func authorize(authEnabled bool) AuthorizeCmd {
+	return func(ctx context.Context, permission domain.Permission) error {
+		apiKey, authenticated := domain.APIKeyFromContext(ctx)
+		if !authenticated {
+			if authEnabled {
+				return ErrForbidden
+			}
+			return nil
+		}
+		if !apiKey.Role.Can(permission) {
+			return ErrForbidden
+		}
+		return nil
+	}
+}
+
+// AuthorizeCmdBuilder builds the command that checks the permissions of the authenticated API key, authEnabled being false only when the authentication is disabled
+func AuthorizeCmdBuilder(authEnabled bool) AuthorizeCmd {
+	return authorize(authEnabled)
+}
+
+// authorizeLinkManagement returns ErrForbidden if the authenticated API key of the context can't modify the link
+// When the authentication is disabled, every link can be modified
+func authorizeLinkManagement(ctx context.Context, authorizeCmd AuthorizeCmd, shortURLStore shorturl.Store, slug string) error {
+	err := authorizeCmd(ctx, domain.PermissionWriteLinks)
+	if err != nil {
+		return err
+	}
+	apiKey, authenticated := domain.APIKeyFromContext(ctx)
+	if !authenticated {
+		return nil
+	}
+
+	urlMapping, err := shortURLStore.Get(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if !apiKey.CanManage(urlMapping) {
+		return ErrForbidden
+	}
+	return nil
+}
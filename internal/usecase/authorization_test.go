@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorize(t *testing.T) {
+	scenarios := []struct {
+		Name        string
+		AuthEnabled bool
+		Ctx         context.Context
+		Permission  domain.Permission
+		Expected    error
+	}{
+		{Name: "authentication disabled", AuthEnabled: false, Ctx: context.Background(), Permission: domain.PermissionManageAPIKeys, Expected: nil},
+		{Name: "missing API key", AuthEnabled: true, Ctx: context.Background(), Permission: domain.PermissionReadStatistics, Expected: ErrForbidden},
+		{Name: "granted", AuthEnabled: true, Ctx: domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor}), Permission: domain.PermissionWriteLinks, Expected: nil},
+		{Name: "not granted", AuthEnabled: true, Ctx: domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleViewer}), Permission: domain.PermissionWriteLinks, Expected: ErrForbidden},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			cmd := AuthorizeCmdBuilder(scenario.AuthEnabled)
+
+			// When
+			err := cmd(scenario.Ctx, scenario.Permission)
+
+			// Then
+			assert.Equal(t, scenario.Expected, err)
+		})
+	}
+}
+
+func TestAuthorizeLinkManagement(t *testing.T) {
+	slug := "zTw34enA"
+
+	t.Run("authentication disabled", func(t *testing.T) {
+		// When
+		err := authorizeLinkManagement(context.Background(), AuthorizeCmdBuilder(false), shorturl.NewMock(t), slug)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("forbidden without API key", func(t *testing.T) {
+		// When
+		err := authorizeLinkManagement(context.Background(), AuthorizeCmdBuilder(true), shorturl.NewMock(t), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+	t.Run("forbidden for a viewer", func(t *testing.T) {
+		// Given
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleViewer})
+
+		// When
+		err := authorizeLinkManagement(ctx, AuthorizeCmdBuilder(true), shorturl.NewMock(t), slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+	t.Run("admin overrides any link", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{Slug: slug, Owner: "key-1"}, nil)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "admin", Role: domain.RoleAdmin})
+
+		// When
+		err := authorizeLinkManagement(ctx, AuthorizeCmdBuilder(true), shortURLMock, slug)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("failed retrieving URL", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, shorturl.ErrExpired)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		err := authorizeLinkManagement(ctx, AuthorizeCmdBuilder(true), shortURLMock, slug)
+
+		// Then
+		assert.ErrorIs(t, err, shorturl.ErrExpired)
+	})
+}
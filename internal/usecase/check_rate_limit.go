@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/ratelimit"
+)
+
+// RateLimitRoute identifies a group of routes sharing a rate limit
+type RateLimitRoute string
+
+var (
+	RateLimitRouteShorten      RateLimitRoute = "shorten"
+	RateLimitRouteShortenBatch RateLimitRoute = "shorten-batch"
+	RateLimitRouteRedirect     RateLimitRoute = "redirect"
+	RateLimitRouteResolveBatch RateLimitRoute = "resolve-batch"
+)
+
+// RateLimitScope identifies what the requests of a client are counted by
+type RateLimitScope string
+
+var (
+	RateLimitScopeIP     RateLimitScope = "ip"
+	RateLimitScopeAPIKey RateLimitScope = "key"
+)
+
+// CheckRateLimitCmd represents the function signature of the command that consumes one request of a client against the rate limit of a route
+type CheckRateLimitCmd func(ctx context.Context, route RateLimitRoute, scope RateLimitScope, clientIP string) (ratelimit.Result, error)
+
+// rateLimitKeyOf returns the key the requests are counted by within the given scope, false if the request has no such key
+func rateLimitKeyOf(ctx context.Context, route RateLimitRoute, scope RateLimitScope, clientIP string) (string, bool) {
+	if scope == RateLimitScopeIP {
+		return fmt.Sprintf("%s:ip:%s", route, clientIP), true
+	}
+	apiKey, authenticated := domain.APIKeyFromContext(ctx)
+	if !authenticated {
+		return "", false
+	}
+	return fmt.Sprintf("%s:key:%s", route, apiKey.ID), true
+}
+
+// checkRateLimit consumes one request of a client against the rate limit of a route within a scope
+// A route without rule is not limited, nor is a request without API key within the API key scope (the authentication being disabled)
+func checkRateLimit(rules map[RateLimitRoute]ratelimit.Rule, rateLimitStore ratelimit.Store) CheckRateLimitCmd {
+	return func(ctx context.Context, route RateLimitRoute, scope RateLimitScope, clientIP string) (ratelimit.Result, error) {
+		rule, ok := rules[route]
+		if !ok || rule.IsZero() {
+			return ratelimit.Result{Allowed: true}, nil
+		}
+
+		key, ok := rateLimitKeyOf(ctx, route, scope, clientIP)
+		if !ok {
+			return ratelimit.Result{Allowed: true}, nil
+		}
+		return rateLimitStore.Allow(ctx, key, rule)
+	}
+}
+
+// CheckRateLimitCmdBuilder builds the command that will check the rate limit of a route
+func CheckRateLimitCmdBuilder(rules map[RateLimitRoute]ratelimit.Rule, rateLimitStore ratelimit.Store) CheckRateLimitCmd {
+	return checkRateLimit(rules, rateLimitStore)
+}
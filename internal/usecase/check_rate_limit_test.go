@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRateLimitCmdBuilder(t *testing.T) {
+	rule := ratelimit.Rule{Requests: 60, Window: time.Minute}
+	rules := map[RateLimitRoute]ratelimit.Rule{RateLimitRouteShorten: rule}
+	clientIP := "203.0.113.7"
+
+	t.Run("keyed by client IP", func(t *testing.T) {
+		// Given
+		expectedResult := ratelimit.Result{Allowed: true, Limit: 60, Remaining: 59, ResetAfter: time.Second}
+		rateLimitMock := ratelimit.NewMockStore(t)
+		rateLimitMock.On("Allow", mock.Anything, "shorten:ip:203.0.113.7", rule).Return(expectedResult, nil)
+		cmd := CheckRateLimitCmdBuilder(rules, rateLimitMock)
+
+		// When
+		result, err := cmd(context.Background(), RateLimitRouteShorten, RateLimitScopeIP, clientIP)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedResult, result)
+	})
+	t.Run("keyed by client IP even when authenticated", func(t *testing.T) {
+		// Given
+		expectedResult := ratelimit.Result{Allowed: true, Limit: 60, Remaining: 59, ResetAfter: time.Second}
+		rateLimitMock := ratelimit.NewMockStore(t)
+		rateLimitMock.On("Allow", mock.Anything, "shorten:ip:203.0.113.7", rule).Return(expectedResult, nil)
+		cmd := CheckRateLimitCmdBuilder(rules, rateLimitMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		result, err := cmd(ctx, RateLimitRouteShorten, RateLimitScopeIP, clientIP)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedResult, result)
+	})
+	t.Run("keyed by API key", func(t *testing.T) {
+		// Given
+		expectedResult := ratelimit.Result{Allowed: false, Limit: 60, RetryAfter: time.Second}
+		rateLimitMock := ratelimit.NewMockStore(t)
+		rateLimitMock.On("Allow", mock.Anything, "shorten:key:key-1", rule).Return(expectedResult, nil)
+		cmd := CheckRateLimitCmdBuilder(rules, rateLimitMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		result, err := cmd(ctx, RateLimitRouteShorten, RateLimitScopeAPIKey, clientIP)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedResult, result)
+	})
+	t.Run("API key scope without API key", func(t *testing.T) {
+		// Given
+		cmd := CheckRateLimitCmdBuilder(rules, ratelimit.NewMockStore(t))
+
+		// When
+		result, err := cmd(context.Background(), RateLimitRouteShorten, RateLimitScopeAPIKey, clientIP)
+		require.NoError(t, err)
+
+		// Then
+		assert.True(t, result.Allowed)
+		assert.Zero(t, result.Limit)
+	})
+	t.Run("route without rule", func(t *testing.T) {
+		// Given
+		cmd := CheckRateLimitCmdBuilder(rules, ratelimit.NewMockStore(t))
+
+		// When
+		result, err := cmd(context.Background(), RateLimitRouteRedirect, RateLimitScopeIP, clientIP)
+		require.NoError(t, err)
+
+		// Then
+		assert.True(t, result.Allowed)
+	})
+	t.Run("failed applying rate limit", func(t *testing.T) {
+		// Given
+		rateLimitMock := ratelimit.NewMockStore(t)
+		rateLimitMock.On("Allow", mock.Anything, mock.Anything, mock.Anything).Return(ratelimit.Result{}, assert.AnError)
+		cmd := CheckRateLimitCmdBuilder(rules, rateLimitMock)
+
+		// When
+		_, err := cmd(context.Background(), RateLimitRouteShorten, RateLimitScopeIP, clientIP)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
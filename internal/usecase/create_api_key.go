@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/apikey"
+)
+
+var (
+	// ErrInvalidRole is the error when the role of an API key to create is unknown
+	ErrInvalidRole error = errors.New("invalid role")
+)
+
+// CreateAPIKeyCmd represents the function signature of the command that creates an API key
+// It returns the stored API key along with its secret, the secret can't be retrieved afterwards
+type CreateAPIKeyCmd func(ctx context.Context, name string, role domain.Role) (domain.APIKey, string, error)
+
+// createAPIKey generates and stores an API key, only an authenticated role allowed to manage API keys can create one
+// Unlike the other commands, it is forbidden without authenticated API key: the keys created while the authentication is disabled would be trusted once it is enabled again
+func createAPIKey(authorizeCmd AuthorizeCmd, apiKeyGeneratorCmd command.APIKeyGeneratorCmd, apiKeyHasherCmd command.APIKeyHasherCmd, apiKeyStore apikey.Store) CreateAPIKeyCmd {
+	return func(ctx context.Context, name string, role domain.Role) (domain.APIKey, string, error) {
+		if _, authenticated := domain.APIKeyFromContext(ctx); !authenticated {
+			return domain.APIKey{}, "", ErrForbidden
+		}
+		err := authorizeCmd(ctx, domain.PermissionManageAPIKeys)
+		if err != nil {
+			return domain.APIKey{}, "", err
+		}
+		if !role.IsValid() {
+			return domain.APIKey{}, "", ErrInvalidRole
+		}
+
+		// Generate and store the API key
+		id, secret, err := apiKeyGeneratorCmd()
+		if err != nil {
+			return domain.APIKey{}, "", err
+		}
+		apiKey := domain.APIKey{
+			ID:        id,
+			Name:      name,
+			Hash:      apiKeyHasherCmd(secret),
+			Role:      role,
+			CreatedAt: time.Now(),
+		}
+		err = apiKeyStore.Set(ctx, apiKey)
+		if err != nil {
+			return domain.APIKey{}, "", err
+		}
+
+		return apiKey, secret, nil
+	}
+}
+
+// CreateAPIKeyCmdBuilder builds the command that will create an API key
+func CreateAPIKeyCmdBuilder(authorizeCmd AuthorizeCmd, apiKeyGeneratorCmd command.APIKeyGeneratorCmd, apiKeyHasherCmd command.APIKeyHasherCmd, apiKeyStore apikey.Store) CreateAPIKeyCmd {
+	return createAPIKey(authorizeCmd, apiKeyGeneratorCmd, apiKeyHasherCmd, apiKeyStore)
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/apikey"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAPIKeyCmdBuilder(t *testing.T) {
+	apiKeyGeneratorStub := func() (string, string, error) {
+		return "key-1", "usk_secret", nil
+	}
+	apiKeyHasherStub := func(secret string) string {
+		return "hash-of-" + secret
+	}
+	adminCtx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "admin", Role: domain.RoleAdmin})
+	expectedAPIKey := func(apiKey domain.APIKey) bool {
+		return apiKey.ID == "key-1" && apiKey.Name == "marketing" && apiKey.Hash == "hash-of-usk_secret" && apiKey.Role == domain.RoleEditor && !apiKey.CreatedAt.IsZero()
+	}
+
+	t.Run("nominal by an admin", func(t *testing.T) {
+		// Given
+		apiKeyMock := apikey.NewMockStore(t)
+		apiKeyMock.On("Set", mock.Anything, mock.MatchedBy(expectedAPIKey)).Return(nil)
+		cmd := CreateAPIKeyCmdBuilder(AuthorizeCmdBuilder(true), apiKeyGeneratorStub, apiKeyHasherStub, apiKeyMock)
+
+		// When
+		apiKey, secret, err := cmd(adminCtx, "marketing", domain.RoleEditor)
+		require.NoError(t, err)
+
+		// Then
+		assert.True(t, expectedAPIKey(apiKey))
+		assert.Equal(t, "usk_secret", secret)
+	})
+	t.Run("forbidden without authentication", func(t *testing.T) {
+		// Given
+		cmd := CreateAPIKeyCmdBuilder(AuthorizeCmdBuilder(false), apiKeyGeneratorStub, apiKeyHasherStub, apikey.NewMockStore(t))
+
+		// When
+		apiKey, secret, err := cmd(context.Background(), "marketing", domain.RoleAdmin)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, apiKey)
+		assert.Empty(t, secret)
+	})
+	t.Run("forbidden for a non admin", func(t *testing.T) {
+		// Given
+		cmd := CreateAPIKeyCmdBuilder(AuthorizeCmdBuilder(true), apiKeyGeneratorStub, apiKeyHasherStub, apikey.NewMockStore(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-2", Role: domain.RoleEditor})
+
+		// When
+		apiKey, secret, err := cmd(ctx, "marketing", domain.RoleEditor)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, apiKey)
+		assert.Empty(t, secret)
+	})
+	t.Run("invalid role", func(t *testing.T) {
+		// Given
+		cmd := CreateAPIKeyCmdBuilder(AuthorizeCmdBuilder(true), apiKeyGeneratorStub, apiKeyHasherStub, apikey.NewMockStore(t))
+
+		// When
+		apiKey, secret, err := cmd(adminCtx, "marketing", domain.Role("owner"))
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidRole)
+		assert.Empty(t, apiKey)
+		assert.Empty(t, secret)
+	})
+	t.Run("failed generating API key", func(t *testing.T) {
+		// Given
+		apiKeyGeneratorCmd := func() (string, string, error) {
+			return "", "", assert.AnError
+		}
+		cmd := CreateAPIKeyCmdBuilder(AuthorizeCmdBuilder(true), apiKeyGeneratorCmd, apiKeyHasherStub, apikey.NewMockStore(t))
+
+		// When
+		_, _, err := cmd(adminCtx, "marketing", domain.RoleEditor)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("failed storing API key", func(t *testing.T) {
+		// Given
+		apiKeyMock := apikey.NewMockStore(t)
+		apiKeyMock.On("Set", mock.Anything, mock.Anything).Return(assert.AnError)
+		cmd := CreateAPIKeyCmdBuilder(AuthorizeCmdBuilder(true), apiKeyGeneratorStub, apiKeyHasherStub, apiKeyMock)
+
+		// When
+		apiKey, secret, err := cmd(adminCtx, "marketing", domain.RoleEditor)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, apiKey)
+		assert.Empty(t, secret)
+	})
+}
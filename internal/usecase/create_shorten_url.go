@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
+	"time"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
 	"urlShortenerService/internal/infrastructure/shorturl"
@@ -11,45 +14,151 @@ import (
 	"github.com/golang/glog"
 )
 
+var (
+	// ErrSlugCollisionUnresolved is the error when no free slug has been found for an URL after all the collision retries
+	ErrSlugCollisionUnresolved error = errors.New("slug collision unresolved")
+	// ErrInvalidExpiration is the error when the expiration asked for a shorten URL is invalid
+	ErrInvalidExpiration error = errors.New("expiration is invalid")
+)
+
+// slugCollisionsMetric counts the slug collisions that have been detected, resolved and unresolved
+var slugCollisionsMetric = expvar.NewMap("slug_collisions")
+
+// CreateShortenURLParams holds the parameters of the command that create a shorten URL
+type CreateShortenURLParams struct {
+	URLToShorten string
+	CustomSlug   string        // Optional, if empty a slug is generated from the URL
+	TTL          time.Duration // Optional, the time to live of the shorten URL
+	ExpiresAt    *time.Time    // Optional, the expiration date of the shorten URL
+	NeverExpires bool          // Optional, the shorten URL never expires
+}
+
 // CreateShortenURLCmd represents the function signature of the command that create a shorten URL
-type CreateShortenURLCmd func(ctx context.Context, urlToShorten string) (string, error)
+type CreateShortenURLCmd func(ctx context.Context, params CreateShortenURLParams) (string, error)
+
+// computeExpiresAt computes the expiration date of a shorten URL given its parameters
+// If no expiration is asked, the default time to expire is used (a zero default means that the shorten URL never expires)
+func computeExpiresAt(now time.Time, defaultTimeToExpire time.Duration, params CreateShortenURLParams) (*time.Time, error) {
+	var expirationsAsked int
+	for _, asked := range []bool{params.TTL != 0, params.ExpiresAt != nil, params.NeverExpires} {
+		if asked {
+			expirationsAsked++
+		}
+	}
+	if expirationsAsked > 1 {
+		return nil, ErrInvalidExpiration
+	}
+
+	var expiresAt time.Time
+	switch {
+	case params.NeverExpires:
+		return nil, nil
+	case params.ExpiresAt != nil:
+		expiresAt = *params.ExpiresAt
+	case params.TTL != 0:
+		expiresAt = now.Add(params.TTL)
+	case defaultTimeToExpire != 0:
+		expiresAt = now.Add(defaultTimeToExpire)
+	default:
+		return nil, nil
+	}
+
+	if !expiresAt.After(now) {
+		return nil, ErrInvalidExpiration
+	}
+	return &expiresAt, nil
+}
+
+// setGeneratedSlug generates a slug for the URL and stores it
+// If the slug is already associated to a different URL, a longer slug is generated until maxCollisionRetries is reached
+func setGeneratedSlug(ctx context.Context, maxCollisionRetries int, slugGeneratorCmd command.SlugGeneratorCmd,
+	shortURLStore shorturl.Store, url string, expiresAt *time.Time, owner string) (string, error) {
+	for attempt := 0; attempt <= maxCollisionRetries; attempt++ {
+		slug := slugGeneratorCmd(slugSeedOf(owner, url), attempt)
+		err := shortURLStore.Set(ctx, domain.URLMapping{
+			Slug:        slug,
+			OriginalURL: url,
+			ExpiresAt:   expiresAt,
+			Owner:       owner,
+		})
+		if err == nil {
+			if attempt > 0 {
+				slugCollisionsMetric.Add("resolved", 1)
+				glog.Infof("slug collision resolved for [%s] with slug [%s] after [%d] attempt(s)", url, slug, attempt)
+			}
+			return slug, nil
+		}
+		if !errors.Is(err, shorturl.ErrSlugAlreadyExists) {
+			return "", err
+		}
+		slugCollisionsMetric.Add("detected", 1)
+		glog.Warningf("slug collision detected for [%s] with slug [%s]", url, slug)
+	}
+
+	slugCollisionsMetric.Add("unresolved", 1)
+	glog.Errorf("slug collision unresolved for [%s] after [%d] retries", url, maxCollisionRetries)
+	return "", ErrSlugCollisionUnresolved
+}
 
 // createShortenURL creates, stores and returns a shorten URL
-func createShortenURL(baseURL string, urlSanitizerCmd command.URLSanitizerCmd, slugGeneratorCmd command.SlugGeneratorCmd,
-	shortURLStore shorturl.Store, statisticsStore statistics.Store) CreateShortenURLCmd {
-	return func(ctx context.Context, urlToShorten string) (string, error) {
-		// Sanitize and validate URL
-		sanitizedURLToShorten, err := urlSanitizerCmd(urlToShorten)
+func createShortenURL(authorizeCmd AuthorizeCmd, baseURL string, maxCollisionRetries int, defaultTimeToExpire time.Duration, urlSanitizerCmd command.URLSanitizerCmd, slugGeneratorCmd command.SlugGeneratorCmd,
+	slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) CreateShortenURLCmd {
+	return func(ctx context.Context, params CreateShortenURLParams) (string, error) {
+		// Ensure the caller is allowed to create links
+		err := authorizeCmd(ctx, domain.PermissionWriteLinks)
 		if err != nil {
 			return "", err
 		}
 
-		// Shorten URL
-		slug := slugGeneratorCmd(sanitizedURLToShorten)
+		// Sanitize and validate URL
+		sanitizedURLToShorten, err := urlSanitizerCmd(params.URLToShorten)
+		if err != nil {
+			return "", err
+		}
 
-		// Save URL
-		err = shortURLStore.Set(ctx, domain.URLMapping{
-			Slug:        slug,
-			OriginalURL: sanitizedURLToShorten,
-		})
+		// Compute and validate expiration
+		expiresAt, err := computeExpiresAt(time.Now(), defaultTimeToExpire, params)
 		if err != nil {
 			return "", err
 		}
 
-		// Update statistics
-		go func(url string) {
-			err := statisticsStore.SetURL(context.Background(), url, statistics.StatisticTypeShortened)
+		// Save URL with the custom slug once validated or with a generated one, owned by the caller
+		owner := ownerOf(ctx)
+		var slug string
+		if params.CustomSlug != "" {
+			err = slugValidatorCmd(params.CustomSlug)
+			if err != nil {
+				return "", err
+			}
+			err = shortURLStore.Set(ctx, domain.URLMapping{
+				Slug:        params.CustomSlug,
+				OriginalURL: sanitizedURLToShorten,
+				ExpiresAt:   expiresAt,
+				Owner:       owner,
+			})
+			if err != nil {
+				return "", err
+			}
+			slug = params.CustomSlug
+		} else {
+			slug, err = setGeneratedSlug(ctx, maxCollisionRetries, slugGeneratorCmd, shortURLStore, sanitizedURLToShorten, expiresAt, owner)
 			if err != nil {
-				glog.Errorf("failed to set [%s] statistics for [%s]: %w", statistics.StatisticTypeShortened, url, err)
+				return "", err
 			}
-		}(sanitizedURLToShorten)
+		}
+
+		// Update statistics
+		err = statisticsStore.SetURL(ctx, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURLToShorten}, statistics.StatisticTypeShortened)
+		if err != nil {
+			glog.Errorf("failed to set [%s] statistics for [%s]: %s", statistics.StatisticTypeShortened, sanitizedURLToShorten, err)
+		}
 
 		return fmt.Sprintf("%s/%s", baseURL, slug), nil
 	}
 }
 
 // CreateShortenURLCmdBuilder builds the command that will create a shorten URL
-func CreateShortenURLCmdBuilder(baseURL string, urlSanitizerCmd command.URLSanitizerCmd, slugGeneratorCmd command.SlugGeneratorCmd,
-	shortURLStore shorturl.Store, statisticsStore statistics.Store) CreateShortenURLCmd {
-	return createShortenURL(baseURL, urlSanitizerCmd, slugGeneratorCmd, shortURLStore, statisticsStore)
+func CreateShortenURLCmdBuilder(authorizeCmd AuthorizeCmd, baseURL string, maxCollisionRetries int, defaultTimeToExpire time.Duration, urlSanitizerCmd command.URLSanitizerCmd,
+	slugGeneratorCmd command.SlugGeneratorCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) CreateShortenURLCmd {
+	return createShortenURL(authorizeCmd, baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLStore, statisticsStore)
 }
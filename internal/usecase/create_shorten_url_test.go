@@ -3,8 +3,8 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"sync"
 	"testing"
+	"time"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
 	"urlShortenerService/internal/infrastructure/shorturl"
@@ -25,50 +25,211 @@ func TestCreateShortenURLCmdBuilder(t *testing.T) {
 		}
 	}
 	slugGeneratorStub := func(expectedURL *string, returnedSlug string) command.SlugGeneratorCmd {
-		return func(rawURL string) string {
+		return func(rawURL string, attempt int) string {
 			if expectedURL != nil {
 				assert.Equal(t, *expectedURL, rawURL)
 			}
 			return returnedSlug
 		}
 	}
+	slugValidatorStub := func(expectedSlug *string, err error) command.SlugValidatorCmd {
+		return func(slug string) error {
+			if expectedSlug != nil {
+				assert.Equal(t, *expectedSlug, slug)
+			}
+			return err
+		}
+	}
 	var baseURL string = "https://example.com"
 	var originalURL string = "https://My-Very-Long-URL.com/needs-to-be-shortened"
 	var sanitizedURL string = "https://my-very-long-url.com/needs-to-be-shortened"
 	var slug string = "zTw34enA"
+	var customSlug string = "spring-sale"
+	var maxCollisionRetries int = 2
+	var defaultTimeToExpire time.Duration = 0 // Never expires by default to ease mocks expectations
 
 	t.Run("nominal", func(t *testing.T) {
 		// Given
 		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
 		slugGeneratorCmd := slugGeneratorStub(&sanitizedURL, slug)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL}).Return(nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, sanitizedURL, statistics.StatisticTypeShortened).Return(nil).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
-		cmd := CreateShortenURLCmdBuilder(baseURL, urlSanitizerCmd, slugGeneratorCmd, shortURLMock, statisticsMock)
+		statisticsMock.On("SetURL", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL}, statistics.StatisticTypeShortened).Return(nil)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, fmt.Sprintf("%s/%s", baseURL, slug), shortURL)
+	})
+	t.Run("owned by the authenticated API key", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		seed := "key-1|" + sanitizedURL
+		slugGeneratorCmd := slugGeneratorStub(&seed, slug)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL, Owner: "key-1"}).Return(nil)
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURL", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL}, statistics.StatisticTypeShortened).Return(nil)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(true), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		shortURL, err := cmd(ctx, CreateShortenURLParams{URLToShorten: originalURL})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, fmt.Sprintf("%s/%s", baseURL, slug), shortURL)
+	})
+	t.Run("with a custom slug", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(&customSlug, nil)
+		slugGeneratorCmd := func(rawURL string, attempt int) string {
+			assert.Fail(t, "slug generator should not be called with a custom slug")
+			return ""
+		}
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: customSlug, OriginalURL: sanitizedURL}).Return(nil)
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURL", mock.Anything, domain.URLMapping{Slug: customSlug, OriginalURL: sanitizedURL}, statistics.StatisticTypeShortened).Return(nil)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL, CustomSlug: customSlug})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, fmt.Sprintf("%s/%s", baseURL, customSlug), shortURL)
+	})
+	t.Run("invalid custom slug", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(&customSlug, command.ErrInvalidSlugNonAlphanumeric)
+		slugGeneratorCmd := slugGeneratorStub(nil, slug)
+		shortURLMock := shorturl.NewMock(t)
+		statisticsMock := statistics.NewMockStore(t)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL, CustomSlug: customSlug})
+
+		// Then
+		require.ErrorIs(t, err, command.ErrInvalidSlugNonAlphanumeric)
+		assert.Empty(t, shortURL)
+	})
+	t.Run("custom slug already taken", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(&customSlug, nil)
+		slugGeneratorCmd := slugGeneratorStub(nil, slug)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: customSlug, OriginalURL: sanitizedURL}).Return(shorturl.ErrSlugAlreadyExists)
+		statisticsMock := statistics.NewMockStore(t)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL, CustomSlug: customSlug})
+
+		// Then
+		require.ErrorIs(t, err, shorturl.ErrSlugAlreadyExists)
+		assert.Empty(t, shortURL)
+	})
+	t.Run("slug collision resolved", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		slugGeneratorCmd := func(rawURL string, attempt int) string {
+			assert.Equal(t, sanitizedURL, rawURL)
+			return fmt.Sprintf("%s%d", slug, attempt)
+		}
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: slug + "0", OriginalURL: sanitizedURL}).Return(shorturl.ErrSlugAlreadyExists)
+		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: slug + "1", OriginalURL: sanitizedURL}).Return(nil)
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURL", mock.Anything, domain.URLMapping{Slug: slug + "1", OriginalURL: sanitizedURL}, statistics.StatisticTypeShortened).Return(nil)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, fmt.Sprintf("%s/%s1", baseURL, slug), shortURL)
+	})
+	t.Run("slug collision unresolved", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		slugGeneratorCmd := func(rawURL string, attempt int) string {
+			return fmt.Sprintf("%s%d", slug, attempt)
+		}
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Set", mock.Anything, mock.Anything).Return(shorturl.ErrSlugAlreadyExists).Times(maxCollisionRetries + 1)
+		statisticsMock := statistics.NewMockStore(t)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL})
+
+		// Then
+		require.ErrorIs(t, err, ErrSlugCollisionUnresolved)
+		assert.Empty(t, shortURL)
+	})
+	t.Run("with an expiration", func(t *testing.T) {
+		// Given
+		ttl := time.Hour
+		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		slugGeneratorCmd := slugGeneratorStub(&sanitizedURL, slug)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Set", mock.Anything, mock.MatchedBy(func(urlMapping domain.URLMapping) bool {
+			return urlMapping.Slug == slug && urlMapping.ExpiresAt != nil &&
+				urlMapping.ExpiresAt.After(time.Now()) && urlMapping.ExpiresAt.Before(time.Now().Add(ttl))
+		})).Return(nil)
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURL", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL}, statistics.StatisticTypeShortened).Return(nil)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, 24*time.Hour, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		shortURL, err := cmd(context.Background(), originalURL)
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL, TTL: ttl})
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, fmt.Sprintf("%s/%s", baseURL, slug), shortURL)
-		wg.Wait()
+	})
+	t.Run("invalid expiration", func(t *testing.T) {
+		// Given
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		slugGeneratorCmd := slugGeneratorStub(nil, slug)
+		shortURLMock := shorturl.NewMock(t)
+		statisticsMock := statistics.NewMockStore(t)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
+
+		// When
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL, TTL: -time.Hour})
+
+		// Then
+		require.ErrorIs(t, err, ErrInvalidExpiration)
+		assert.Empty(t, shortURL)
 	})
 	t.Run("failed sanitizing URL", func(t *testing.T) {
 		// Given
 		urlSanitizerCmd := urlSanitizerStub(nil, "", assert.AnError)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
 		slugGeneratorCmd := slugGeneratorStub(nil, slug)
 		shortURLMock := shorturl.NewMock(t)
 		statisticsMock := statistics.NewMockStore(t)
-		cmd := CreateShortenURLCmdBuilder(baseURL, urlSanitizerCmd, slugGeneratorCmd, shortURLMock, statisticsMock)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		shortURL, err := cmd(context.Background(), originalURL)
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL})
 
 		// Then
 		require.ErrorIs(t, err, assert.AnError)
@@ -77,14 +238,15 @@ func TestCreateShortenURLCmdBuilder(t *testing.T) {
 	t.Run("failed storing URL", func(t *testing.T) {
 		// Given
 		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
 		slugGeneratorCmd := slugGeneratorStub(nil, slug)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Set", mock.Anything, mock.Anything).Return(assert.AnError)
 		statisticsMock := statistics.NewMockStore(t)
-		cmd := CreateShortenURLCmdBuilder(baseURL, urlSanitizerCmd, slugGeneratorCmd, shortURLMock, statisticsMock)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		shortURL, err := cmd(context.Background(), originalURL)
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL})
 
 		// Then
 		require.ErrorIs(t, err, assert.AnError)
@@ -93,23 +255,54 @@ func TestCreateShortenURLCmdBuilder(t *testing.T) {
 	t.Run("failed updating statistics", func(t *testing.T) {
 		// Given
 		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		slugValidatorCmd := slugValidatorStub(nil, nil)
 		slugGeneratorCmd := slugGeneratorStub(&sanitizedURL, slug)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Set", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL}).Return(nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, sanitizedURL, statistics.StatisticTypeShortened).Return(assert.AnError).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
-		cmd := CreateShortenURLCmdBuilder(baseURL, urlSanitizerCmd, slugGeneratorCmd, shortURLMock, statisticsMock)
+		statisticsMock.On("SetURL", mock.Anything, domain.URLMapping{Slug: slug, OriginalURL: sanitizedURL}, statistics.StatisticTypeShortened).Return(assert.AnError)
+		cmd := CreateShortenURLCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		shortURL, err := cmd(context.Background(), originalURL)
+		shortURL, err := cmd(context.Background(), CreateShortenURLParams{URLToShorten: originalURL})
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, fmt.Sprintf("%s/%s", baseURL, slug), shortURL)
-		wg.Wait()
 	})
 }
+
+func TestComputeExpiresAt(t *testing.T) {
+	now := time.Now()
+	defaultTimeToExpire := 24 * time.Hour
+	expiresAt := now.Add(time.Hour)
+	expiredAt := now.Add(-time.Hour)
+	defaultExpiresAt := now.Add(defaultTimeToExpire)
+	ttlExpiresAt := now.Add(2 * time.Hour)
+	scenarios := []struct {
+		Name                string
+		DefaultTimeToExpire time.Duration
+		Params              CreateShortenURLParams
+		ExpectedExpiresAt   *time.Time
+		ExpectError         bool
+	}{
+		{Name: "default time to expire", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{}, ExpectedExpiresAt: &defaultExpiresAt},
+		{Name: "no default time to expire", DefaultTimeToExpire: 0, Params: CreateShortenURLParams{}, ExpectedExpiresAt: nil},
+		{Name: "with ttl", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{TTL: 2 * time.Hour}, ExpectedExpiresAt: &ttlExpiresAt},
+		{Name: "with expires at", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{ExpiresAt: &expiresAt}, ExpectedExpiresAt: &expiresAt},
+		{Name: "never expires", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{NeverExpires: true}, ExpectedExpiresAt: nil},
+		{Name: "negative ttl", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{TTL: -time.Hour}, ExpectError: true},
+		{Name: "expires at in the past", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{ExpiresAt: &expiredAt}, ExpectError: true},
+		{Name: "several expirations asked", DefaultTimeToExpire: defaultTimeToExpire, Params: CreateShortenURLParams{TTL: time.Hour, NeverExpires: true}, ExpectError: true},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// When
+			computedExpiresAt, err := computeExpiresAt(now, scenario.DefaultTimeToExpire, scenario.Params)
+
+			// Then
+			require.Equal(t, scenario.ExpectError, err != nil)
+			assert.Equal(t, scenario.ExpectedExpiresAt, computedExpiresAt)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/golang/glog"
+)
+
+// MaxShortenURLsBatchSize is the maximal number of URLs that can be shortened at once
+const MaxShortenURLsBatchSize int = 10000
+
+var (
+	// ErrInvalidBatchSize is the error when a batch is empty or holds too many items
+	ErrInvalidBatchSize error = errors.New("batch size is invalid")
+)
+
+// CreateShortenURLResult holds the result of a shorten URL creation within a batch
+type CreateShortenURLResult struct {
+	ShortURL string
+	Err      error // Nil if the shorten URL has been created
+}
+
+// CreateShortenURLsCmd represents the function signature of the command that create several shorten URLs at once
+// It returns a result per parameter, in the same order
+type CreateShortenURLsCmd func(ctx context.Context, params []CreateShortenURLParams) ([]CreateShortenURLResult, error)
+
+// pendingShortenURL holds a valid shorten URL waiting to be stored
+type pendingShortenURL struct {
+	index      int
+	url        string
+	customSlug string
+	expiresAt  *time.Time
+}
+
+// createShortenURLs creates, stores and returns several shorten URLs
+// An invalid item only fails its own result, the store is called once per collision retry
+func createShortenURLs(authorizeCmd AuthorizeCmd, baseURL string, maxCollisionRetries int, defaultTimeToExpire time.Duration, urlSanitizerCmd command.URLSanitizerCmd, slugGeneratorCmd command.SlugGeneratorCmd,
+	slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) CreateShortenURLsCmd {
+	return func(ctx context.Context, params []CreateShortenURLParams) ([]CreateShortenURLResult, error) {
+		// Ensure the caller is allowed to create links
+		err := authorizeCmd(ctx, domain.PermissionWriteLinks)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(params) == 0 || len(params) > MaxShortenURLsBatchSize {
+			return nil, ErrInvalidBatchSize
+		}
+
+		// Sanitize and validate every item
+		now := time.Now()
+		results := make([]CreateShortenURLResult, len(params))
+		var pendings []pendingShortenURL
+		for i, param := range params {
+			sanitizedURLToShorten, err := urlSanitizerCmd(param.URLToShorten)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			expiresAt, err := computeExpiresAt(now, defaultTimeToExpire, param)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			if param.CustomSlug != "" {
+				err = slugValidatorCmd(param.CustomSlug)
+				if err != nil {
+					results[i].Err = err
+					continue
+				}
+			}
+			pendings = append(pendings, pendingShortenURL{index: i, url: sanitizedURLToShorten, customSlug: param.CustomSlug, expiresAt: expiresAt})
+		}
+
+		// Save URLs with their custom slug or a generated one, a longer slug is generated for colliding ones until maxCollisionRetries is reached
+		owner := ownerOf(ctx)
+		var shortenedURLs []domain.URLMapping
+		for attempt := 0; len(pendings) > 0 && attempt <= maxCollisionRetries; attempt++ {
+			urlMappings := make([]domain.URLMapping, len(pendings))
+			for j, pending := range pendings {
+				slug := pending.customSlug
+				if slug == "" {
+					slug = slugGeneratorCmd(slugSeedOf(owner, pending.url), attempt)
+				}
+				urlMappings[j] = domain.URLMapping{Slug: slug, OriginalURL: pending.url, ExpiresAt: pending.expiresAt, Owner: owner}
+			}
+
+			errs, err := shortURLStore.SetBatch(ctx, urlMappings)
+			if err != nil {
+				return nil, err
+			}
+
+			var collidings []pendingShortenURL
+			for j, pending := range pendings {
+				switch {
+				case errs[j] == nil:
+					if attempt > 0 {
+						slugCollisionsMetric.Add("resolved", 1)
+					}
+					results[pending.index].ShortURL = fmt.Sprintf("%s/%s", baseURL, urlMappings[j].Slug)
+					shortenedURLs = append(shortenedURLs, urlMappings[j])
+				case errors.Is(errs[j], shorturl.ErrSlugAlreadyExists) && pending.customSlug == "":
+					slugCollisionsMetric.Add("detected", 1)
+					glog.Warningf("slug collision detected for [%s] with slug [%s]", pending.url, urlMappings[j].Slug)
+					collidings = append(collidings, pending)
+				default:
+					results[pending.index].Err = errs[j]
+				}
+			}
+			pendings = collidings
+		}
+		for _, pending := range pendings {
+			slugCollisionsMetric.Add("unresolved", 1)
+			glog.Errorf("slug collision unresolved for [%s] after [%d] retries", pending.url, maxCollisionRetries)
+			results[pending.index].Err = ErrSlugCollisionUnresolved
+		}
+
+		// Update statistics
+		if len(shortenedURLs) > 0 {
+			err := statisticsStore.SetURLs(ctx, shortenedURLs, statistics.StatisticTypeShortened)
+			if err != nil {
+				glog.Errorf("failed to set [%s] statistics for [%d] URLs: %v", statistics.StatisticTypeShortened, len(shortenedURLs), err)
+			}
+		}
+
+		return results, nil
+	}
+}
+
+// CreateShortenURLsCmdBuilder builds the command that will create several shorten URLs at once
+func CreateShortenURLsCmdBuilder(authorizeCmd AuthorizeCmd, baseURL string, maxCollisionRetries int, defaultTimeToExpire time.Duration, urlSanitizerCmd command.URLSanitizerCmd,
+	slugGeneratorCmd command.SlugGeneratorCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) CreateShortenURLsCmd {
+	return createShortenURLs(authorizeCmd, baseURL, maxCollisionRetries, defaultTimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, shortURLStore, statisticsStore)
+}
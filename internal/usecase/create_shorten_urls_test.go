@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateShortenURLsCmdBuilder(t *testing.T) {
+	urlSanitizerStub := func(rawURL string) (string, error) {
+		if strings.Contains(rawURL, "invalid") {
+			return "", command.ErrInvalidURL
+		}
+		return strings.ToLower(rawURL), nil
+	}
+	slugGeneratorStub := func(rawURL string, attempt int) string {
+		return fmt.Sprintf("%s-%d", rawURL[strings.LastIndex(rawURL, "/")+1:], attempt)
+	}
+	slugValidatorStub := func(slug string) error {
+		return nil
+	}
+	var baseURL string = "https://example.com"
+	var maxCollisionRetries int = 1
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("SetBatch", mock.Anything, []domain.URLMapping{
+			{Slug: "first-0", OriginalURL: "https://long.com/first"},
+			{Slug: "spring-sale", OriginalURL: "https://long.com/custom"},
+			{Slug: "colliding-0", OriginalURL: "https://long.com/colliding"},
+		}).Return([]error{nil, shorturl.ErrSlugAlreadyExists, shorturl.ErrSlugAlreadyExists}, nil).Once()
+		shortURLMock.On("SetBatch", mock.Anything, []domain.URLMapping{
+			{Slug: "colliding-1", OriginalURL: "https://long.com/colliding"},
+		}).Return([]error{nil}, nil).Once()
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURLs", mock.Anything, []domain.URLMapping{
+			{Slug: "first-0", OriginalURL: "https://long.com/first"},
+			{Slug: "colliding-1", OriginalURL: "https://long.com/colliding"},
+		}, statistics.StatisticTypeShortened).Return(nil)
+		cmd := CreateShortenURLsCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, 0, urlSanitizerStub, slugGeneratorStub, slugValidatorStub, shortURLMock, statisticsMock)
+
+		// When
+		results, err := cmd(context.Background(), []CreateShortenURLParams{
+			{URLToShorten: "https://Long.com/first"},
+			{URLToShorten: "https://long.com/invalid"},
+			{URLToShorten: "https://long.com/custom", CustomSlug: "spring-sale"},
+			{URLToShorten: "https://long.com/colliding"},
+			{URLToShorten: "https://long.com/expiration", TTL: -1},
+		})
+		require.NoError(t, err)
+
+		// Then
+		require.Len(t, results, 5)
+		assert.Equal(t, CreateShortenURLResult{ShortURL: fmt.Sprintf("%s/first-0", baseURL)}, results[0])
+		assert.ErrorIs(t, results[1].Err, command.ErrInvalidURL)
+		assert.ErrorIs(t, results[2].Err, shorturl.ErrSlugAlreadyExists)
+		assert.Equal(t, CreateShortenURLResult{ShortURL: fmt.Sprintf("%s/colliding-1", baseURL)}, results[3])
+		assert.ErrorIs(t, results[4].Err, ErrInvalidExpiration)
+	})
+	t.Run("collision unresolved", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("SetBatch", mock.Anything, mock.Anything).Return([]error{shorturl.ErrSlugAlreadyExists}, nil).Times(maxCollisionRetries + 1)
+		cmd := CreateShortenURLsCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, 0, urlSanitizerStub, slugGeneratorStub, slugValidatorStub, shortURLMock, statistics.NewMockStore(t))
+
+		// When
+		results, err := cmd(context.Background(), []CreateShortenURLParams{{URLToShorten: "https://long.com/colliding"}})
+		require.NoError(t, err)
+
+		// Then
+		require.Len(t, results, 1)
+		assert.ErrorIs(t, results[0].Err, ErrSlugCollisionUnresolved)
+	})
+	t.Run("forbidden for a viewer", func(t *testing.T) {
+		// Given
+		cmd := CreateShortenURLsCmdBuilder(AuthorizeCmdBuilder(true), baseURL, maxCollisionRetries, 0, urlSanitizerStub, slugGeneratorStub, slugValidatorStub, shorturl.NewMock(t), statistics.NewMockStore(t))
+
+		// When
+		results, err := cmd(domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleViewer}), []CreateShortenURLParams{{URLToShorten: "https://long.com/first"}})
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+		assert.Nil(t, results)
+	})
+	t.Run("invalid batch size", func(t *testing.T) {
+		// Given
+		cmd := CreateShortenURLsCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, 0, urlSanitizerStub, slugGeneratorStub, slugValidatorStub, shorturl.NewMock(t), statistics.NewMockStore(t))
+
+		// When
+		emptyResults, emptyErr := cmd(context.Background(), nil)
+		tooLargeResults, tooLargeErr := cmd(context.Background(), make([]CreateShortenURLParams, MaxShortenURLsBatchSize+1))
+
+		// Then
+		assert.ErrorIs(t, emptyErr, ErrInvalidBatchSize)
+		assert.Nil(t, emptyResults)
+		assert.ErrorIs(t, tooLargeErr, ErrInvalidBatchSize)
+		assert.Nil(t, tooLargeResults)
+	})
+	t.Run("failed to store URLs", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("SetBatch", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		cmd := CreateShortenURLsCmdBuilder(AuthorizeCmdBuilder(false), baseURL, maxCollisionRetries, 0, urlSanitizerStub, slugGeneratorStub, slugValidatorStub, shortURLMock, statistics.NewMockStore(t))
+
+		// When
+		results, err := cmd(context.Background(), []CreateShortenURLParams{{URLToShorten: "https://long.com/first"}})
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, results)
+	})
+}
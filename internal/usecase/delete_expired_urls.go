@@ -2,7 +2,6 @@ package usecase
 
 import (
 	"context"
-	"time"
 	"urlShortenerService/internal/infrastructure/shorturl"
 )
 
@@ -10,14 +9,14 @@ import (
 type DeleteExpiredURLsCmd func(ctx context.Context) ([]string, error)
 
 // deleteExpiredURLs deletes URLs that have expired
-func deleteExpiredURLs(timeToExpire time.Duration, shortURLStore shorturl.Store) DeleteExpiredURLsCmd {
+func deleteExpiredURLs(shortURLStore shorturl.Store) DeleteExpiredURLsCmd {
 	return func(ctx context.Context) ([]string, error) {
 		// Deletes expired URL
-		return shortURLStore.DeleteExpired(ctx, timeToExpire)
+		return shortURLStore.DeleteExpired(ctx)
 	}
 }
 
 // DeleteExpiredURLsCmdBuilder builds the command that will deletes expired URLs
-func DeleteExpiredURLsCmdBuilder(timeToExpire time.Duration, shortURLStore shorturl.Store) DeleteExpiredURLsCmd {
-	return deleteExpiredURLs(timeToExpire, shortURLStore)
+func DeleteExpiredURLsCmdBuilder(shortURLStore shorturl.Store) DeleteExpiredURLsCmd {
+	return deleteExpiredURLs(shortURLStore)
 }
@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"testing"
-	"time"
 	"urlShortenerService/internal/infrastructure/shorturl"
 
 	"github.com/stretchr/testify/assert"
@@ -14,11 +13,10 @@ import (
 func TestDeleteExpiredURLsCmdBuilder(t *testing.T) {
 	t.Run("nominal", func(t *testing.T) {
 		// Given
-		timeToExpire := 1 * time.Hour
 		slugsDeleted := []string{"2zv8a2Im", "1eJSWjFM", "UsIJeS1D", "K11q8dTj", "Sd7k2eDU"}
 		shortURLMock := shorturl.NewMock(t)
-		shortURLMock.On("DeleteExpired", mock.Anything, timeToExpire).Return(slugsDeleted, nil)
-		cmd := DeleteExpiredURLsCmdBuilder(timeToExpire, shortURLMock)
+		shortURLMock.On("DeleteExpired", mock.Anything).Return(slugsDeleted, nil)
+		cmd := DeleteExpiredURLsCmdBuilder(shortURLMock)
 
 		// When
 		slugsDeletedResult, err := cmd(context.Background())
@@ -29,10 +27,9 @@ func TestDeleteExpiredURLsCmdBuilder(t *testing.T) {
 	})
 	t.Run("deletion failed", func(t *testing.T) {
 		// Given
-		timeToExpire := 1 * time.Hour
 		shortURLMock := shorturl.NewMock(t)
-		shortURLMock.On("DeleteExpired", mock.Anything, timeToExpire).Return([]string{}, assert.AnError)
-		cmd := DeleteExpiredURLsCmdBuilder(timeToExpire, shortURLMock)
+		shortURLMock.On("DeleteExpired", mock.Anything).Return([]string{}, assert.AnError)
+		cmd := DeleteExpiredURLsCmdBuilder(shortURLMock)
 
 		// When
 		slugsDeletedResult, err := cmd(context.Background())
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+// DeleteLinkCmd represents the function signature of the command that deletes a link given a slug
+type DeleteLinkCmd func(ctx context.Context, slug string) error
+
+// deleteLink deletes a link given a slug
+func deleteLink(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) DeleteLinkCmd {
+	return func(ctx context.Context, slug string) error {
+		// Ensure slug validity to avoid useless query to store
+		err := slugValidatorCmd(slug)
+		if err != nil {
+			return err
+		}
+
+		// Ensure the link belongs to the caller
+		err = authorizeLinkManagement(ctx, authorizeCmd, shortURLStore, slug)
+		if err != nil {
+			return err
+		}
+
+		// Deletes URL
+		return shortURLStore.Delete(ctx, slug)
+	}
+}
+
+// DeleteLinkCmdBuilder builds the command that will deletes a link
+func DeleteLinkCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) DeleteLinkCmd {
+	return deleteLink(authorizeCmd, slugValidatorCmd, shortURLStore)
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteLinkCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(expectedSlug *string, err error) command.SlugValidatorCmd {
+		return func(slug string) error {
+			if expectedSlug != nil {
+				assert.Equal(t, *expectedSlug, slug)
+			}
+			return err
+		}
+	}
+	var slug string = "zTw34enA"
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(&slug, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Delete", mock.Anything, slug).Return(nil)
+		cmd := DeleteLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, shortURLMock)
+
+		// When
+		err := cmd(context.Background(), slug)
+
+		// Then
+		require.NoError(t, err)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, assert.AnError)
+		shortURLMock := shorturl.NewMock(t)
+		cmd := DeleteLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, shortURLMock)
+
+		// When
+		err := cmd(context.Background(), slug)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("failed deleting URL", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Delete", mock.Anything, slug).Return(shorturl.ErrNotFound)
+		cmd := DeleteLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, shortURLMock)
+
+		// When
+		err := cmd(context.Background(), slug)
+
+		// Then
+		require.ErrorIs(t, err, shorturl.ErrNotFound)
+	})
+	t.Run("owned by the caller", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(&slug, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{Slug: slug, Owner: "key-1"}, nil)
+		shortURLMock.On("Delete", mock.Anything, slug).Return(nil)
+		cmd := DeleteLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorCmd, shortURLMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		err := cmd(ctx, slug)
+
+		// Then
+		require.NoError(t, err)
+	})
+	t.Run("forbidden when owned by another API key", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(&slug, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{Slug: slug, Owner: "key-1"}, nil)
+		cmd := DeleteLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorCmd, shortURLMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-2", Role: domain.RoleEditor})
+
+		// When
+		err := cmd(ctx, slug)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+}
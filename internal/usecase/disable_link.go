@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+// DisableLinkCmd represents the function signature of the command that disables a link given a slug, or enables it again
+type DisableLinkCmd func(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error)
+
+// disableLink disables a link given a slug, or enables it again, whatever its owner
+func disableLink(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) DisableLinkCmd {
+	return func(ctx context.Context, slug string, disabled bool) (domain.URLMapping, error) {
+		// Ensure the caller is allowed to disable links
+		err := authorizeCmd(ctx, domain.PermissionDisableLinks)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Ensure slug validity to avoid useless query to store
+		err = slugValidatorCmd(slug)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Disable or enable URL
+		return shortURLStore.SetDisabled(ctx, slug, disabled)
+	}
+}
+
+// DisableLinkCmdBuilder builds the command that will disable a link or enable it again
+func DisableLinkCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) DisableLinkCmd {
+	return disableLink(authorizeCmd, slugValidatorCmd, shortURLStore)
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisableLinkCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(err error) func(slug string) error {
+		return func(slug string) error {
+			return err
+		}
+	}
+	var slug string = "zTw34enA"
+	adminCtx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "admin", Role: domain.RoleAdmin})
+
+	t.Run("nominal for an admin", func(t *testing.T) {
+		// Given
+		expectedURLMapping := domain.URLMapping{Slug: slug, OriginalURL: "https://example.com", Owner: "key-1", Disabled: true}
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("SetDisabled", mock.Anything, slug, true).Return(expectedURLMapping, nil)
+		cmd := DisableLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shortURLMock)
+
+		// When
+		urlMapping, err := cmd(adminCtx, slug, true)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedURLMapping, urlMapping)
+	})
+	t.Run("enabled again", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("SetDisabled", mock.Anything, slug, false).Return(domain.URLMapping{Slug: slug, OriginalURL: "https://example.com"}, nil)
+		cmd := DisableLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shortURLMock)
+
+		// When
+		urlMapping, err := cmd(adminCtx, slug, false)
+		require.NoError(t, err)
+
+		// Then
+		assert.False(t, urlMapping.Disabled)
+	})
+	t.Run("forbidden for the editor owning the link", func(t *testing.T) {
+		// Given
+		cmd := DisableLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shorturl.NewMock(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		_, err := cmd(ctx, slug, true)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		cmd := DisableLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(assert.AnError), shorturl.NewMock(t))
+
+		// When
+		_, err := cmd(adminCtx, slug, true)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("failed disabling URL", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("SetDisabled", mock.Anything, slug, true).Return(domain.URLMapping{}, shorturl.ErrNotFound)
+		cmd := DisableLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shortURLMock)
+
+		// When
+		urlMapping, err := cmd(adminCtx, slug, true)
+
+		// Then
+		assert.ErrorIs(t, err, shorturl.ErrNotFound)
+		assert.Empty(t, urlMapping)
+	})
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+)
+
+var (
+	// ErrInvalidDimension is the error when the dimension asked for a click breakdown is unknown
+	ErrInvalidDimension error = errors.New("dimension is invalid")
+)
+
+// GetClickBreakdownCmd represents the function signature of the command that retrieves the top values of a dimension among the clicks of a given slug
+type GetClickBreakdownCmd func(ctx context.Context, slug string, dimension statistics.Dimension, limitOveride int64) ([]domain.ClickBreakdown, error)
+
+// getClickBreakdown retrieves the top values of a dimension among the clicks of a given slug, such as its top referrers or countries
+func getClickBreakdown(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, statisticsStore statistics.Store) GetClickBreakdownCmd {
+	return func(ctx context.Context, slug string, dimension statistics.Dimension, limitOveride int64) ([]domain.ClickBreakdown, error) {
+		// Ensure the caller is allowed to read statistics
+		err := authorizeCmd(ctx, domain.PermissionReadStatistics)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ensure dimension and slug validity to avoid useless query to store
+		if !dimension.Valid() {
+			return nil, ErrInvalidDimension
+		}
+		err = slugValidatorCmd(slug)
+		if err != nil {
+			return nil, err
+		}
+
+		// Retrieves statistics
+		return statisticsStore.GetClickBreakdown(ctx, slug, dimension, limitOveride)
+	}
+}
+
+// GetClickBreakdownCmdBuilder builds the command that will retrieves the breakdown of the clicks of a slug
+func GetClickBreakdownCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, statisticsStore statistics.Store) GetClickBreakdownCmd {
+	return getClickBreakdown(authorizeCmd, slugValidatorCmd, statisticsStore)
+}
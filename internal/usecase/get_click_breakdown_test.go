@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClickBreakdownCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(expectedSlug *string, err error) command.SlugValidatorCmd {
+		return func(slug string) error {
+			if expectedSlug != nil {
+				assert.Equal(t, *expectedSlug, slug)
+			}
+			return err
+		}
+	}
+	var slug string = "spring-sale"
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		expectedBreakdowns := []domain.ClickBreakdown{{Value: "FR", Counter: 10}, {Value: "US", Counter: 3}}
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetClickBreakdown", mock.Anything, slug, statistics.DimensionCountry, int64(2)).Return(expectedBreakdowns, nil)
+		cmd := GetClickBreakdownCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), statisticsMock)
+
+		// When
+		breakdowns, err := cmd(context.Background(), slug, statistics.DimensionCountry, 2)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedBreakdowns, breakdowns)
+	})
+	t.Run("forbidden without statistics permission", func(t *testing.T) {
+		// Given
+		cmd := GetClickBreakdownCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil, nil), statistics.NewMockStore(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.Role("unknown")})
+
+		// When
+		breakdowns, err := cmd(ctx, slug, statistics.DimensionCountry, 0)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, breakdowns)
+	})
+	t.Run("invalid dimension", func(t *testing.T) {
+		// Given
+		cmd := GetClickBreakdownCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(nil, nil), statistics.NewMockStore(t))
+
+		// When
+		breakdowns, err := cmd(context.Background(), slug, statistics.Dimension("languages"), 0)
+
+		// Then
+		require.ErrorIs(t, err, ErrInvalidDimension)
+		assert.Empty(t, breakdowns)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		cmd := GetClickBreakdownCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, command.ErrInvalidSlugNonAlphanumeric), statistics.NewMockStore(t))
+
+		// When
+		breakdowns, err := cmd(context.Background(), slug, statistics.DimensionReferrer, 0)
+
+		// Then
+		require.ErrorIs(t, err, command.ErrInvalidSlugNonAlphanumeric)
+		assert.Empty(t, breakdowns)
+	})
+	t.Run("failed retrieving statistics", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetClickBreakdown", mock.Anything, slug, statistics.DimensionReferrer, int64(0)).Return(nil, assert.AnError)
+		cmd := GetClickBreakdownCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), statisticsMock)
+
+		// When
+		breakdowns, err := cmd(context.Background(), slug, statistics.DimensionReferrer, 0)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, breakdowns)
+	})
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+// GetLinkCmd represents the function signature of the command that retrieves a link given a slug
+type GetLinkCmd func(ctx context.Context, slug string) (domain.URLMapping, error)
+
+// getLink retrieves the URL mapping of a link given a slug
+func getLink(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) GetLinkCmd {
+	return func(ctx context.Context, slug string) (domain.URLMapping, error) {
+		// Ensure the caller is allowed to read links
+		err := authorizeCmd(ctx, domain.PermissionReadLinks)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Ensure slug validity to avoid useless query to store
+		err = slugValidatorCmd(slug)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Retrieves URL mapping, an expired one being returned with its status until it is deleted
+		return shortURLStore.GetIncludingExpired(ctx, slug)
+	}
+}
+
+// GetLinkCmdBuilder builds the command that will retrieves a link
+func GetLinkCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) GetLinkCmd {
+	return getLink(authorizeCmd, slugValidatorCmd, shortURLStore)
+}
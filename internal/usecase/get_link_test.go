@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLinkCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(expectedSlug *string, err error) command.SlugValidatorCmd {
+		return func(slug string) error {
+			if expectedSlug != nil {
+				assert.Equal(t, *expectedSlug, slug)
+			}
+			return err
+		}
+	}
+	var urlMappingData domain.URLMapping = domain.URLMapping{
+		Slug:        "zTw34enA",
+		OriginalURL: "https://my-very-long-url.com/needs-to-be-shortened",
+	}
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(&urlMappingData.Slug, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetIncludingExpired", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
+		cmd := GetLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), urlMappingData.Slug)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, urlMappingData, urlMapping)
+	})
+	t.Run("forbidden for a viewer", func(t *testing.T) {
+		// Given
+		cmd := GetLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil, nil), shorturl.NewMock(t))
+
+		// When
+		urlMapping, err := cmd(domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleViewer}), urlMappingData.Slug)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, assert.AnError)
+		shortURLMock := shorturl.NewMock(t)
+		cmd := GetLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), urlMappingData.Slug)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("failed retrieving URL", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetIncludingExpired", mock.Anything, mock.Anything).Return(domain.URLMapping{}, assert.AnError)
+		cmd := GetLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), urlMappingData.Slug)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, urlMapping)
+	})
+}
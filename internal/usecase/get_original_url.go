@@ -2,8 +2,14 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
 	"time"
+	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/geoip"
 	"urlShortenerService/internal/infrastructure/malwarescanner"
 	"urlShortenerService/internal/infrastructure/shorturl"
 	"urlShortenerService/internal/infrastructure/statistics"
@@ -11,43 +17,94 @@ import (
 	"github.com/golang/glog"
 )
 
+// ErrLinkDisabled is the error when the link of a slug has been disabled by an admin
+var ErrLinkDisabled error = errors.New("link disabled")
+
 // GetOriginalURLCmd represents the function signature of the command that retrieves an original URL given a slug
-type GetOriginalURLCmd func(ctx context.Context, shortURL string) (string, error)
+// The click holds the request fields of the access, recorded within the statistics
+type GetOriginalURLCmd func(ctx context.Context, shortURL string, click domain.ClickEvent) (string, error)
+
+// scanURL scans the URL for malware and returns malwarescanner.ErrMalswareURL if one has been detected
+// If the malware scanner errored for something else than a malware or timed out, the error is logged but ignored
+func scanURL(malwareScanner malwarescanner.Scanner, url string) error {
+	malwareScanResult := make(chan malwarescanner.MalwareScanResult, 1)
+	go malwareScanner.Scan(context.Background(), url, malwareScanResult)
+
+	timeout := time.After(time.Second) // Timeout of 1 second
+	select {
+	case malwareScanRes := <-malwareScanResult:
+		switch malwareScanRes {
+		case malwarescanner.MalwareScanResultClear:
+			return nil
+		case malwarescanner.MalwareScanResultDetected:
+			return malwarescanner.ErrMalswareURL
+		default:
+			glog.Warningf("malware scanner errored for [%s]", url)
+		}
+	case <-timeout:
+		glog.Warningf("malware scanner timed out for [%s]", url)
+	}
+
+	return nil
+}
 
 func withMalwareScan(f GetOriginalURLCmd, malwareScanner malwarescanner.Scanner) GetOriginalURLCmd {
-	return func(ctx context.Context, slug string) (string, error) {
-		url, err := f(ctx, slug)
+	return func(ctx context.Context, slug string, click domain.ClickEvent) (string, error) {
+		url, err := f(ctx, slug, click)
 		if err != nil {
 			return url, err
 		}
 
 		// Scan the URL for malware
-		malwareScanResult := make(chan malwarescanner.MalwareScanResult, 1)
-		go malwareScanner.Scan(context.Background(), url, malwareScanResult)
-
-		timeout := time.After(time.Second) // Timeout of 1 second
-		select {
-		case malwareScanRes := <-malwareScanResult:
-			switch malwareScanRes {
-			case malwarescanner.MalwareScanResultClear:
-				return url, nil
-			case malwarescanner.MalwareScanResultDetected:
-				return "", malwarescanner.ErrMalswareURL
-			default:
-				// If malware scanner errored for something else than a malware, we log but ignore the error
-				glog.Warningf("malware scanner errored for [%s]", url)
-			}
-		case <-timeout:
-			glog.Warningf("malware scanner timed out for [%s]", url)
+		err = scanURL(malwareScanner, url)
+		if err != nil {
+			return "", err
 		}
 
 		return url, nil
 	}
 }
 
+// enrichClick fills the visitor, the browser, the operating system, the device and the country of the click from its request fields
+// The referrer is reduced to its host, or to domain.UnknownReferrer if it has none, the country is left unknown without locator or on lookup failure
+func enrichClick(click domain.ClickEvent, visitorHasherCmd command.VisitorHasherCmd, userAgentParserCmd command.UserAgentParserCmd, geoIPLocator geoip.Locator) domain.ClickEvent {
+	if click.Referrer != "" {
+		referrer, err := url.Parse(click.Referrer)
+		if err == nil && referrer.Host != "" {
+			click.Referrer = strings.ToLower(referrer.Hostname())
+		} else {
+			// The raw header is not recorded, any client could make the referrers grow at will
+			click.Referrer = domain.UnknownReferrer
+		}
+	}
+
+	if click.IP != "" {
+		click.Visitor = visitorHasherCmd(click.IP, click.UserAgent)
+	}
+	click.Browser, click.OS, click.Device = userAgentParserCmd(click.UserAgent)
+
+	ip := net.ParseIP(click.IP)
+	if geoIPLocator != nil && ip != nil {
+		country, err := geoIPLocator.Country(ip)
+		if err != nil {
+			glog.Warningf("failed to locate the click from [%s]: %s", click.IP, err)
+		}
+		click.Country = country
+	}
+	return click
+}
+
+// ClickEnricherBuilder builds the enricher of the clicks given to the statistics store, which runs it off the redirection path once buffered
+// The GeoIP locator is optional, nil leaving the countries of the clicks unknown
+func ClickEnricherBuilder(visitorHasherCmd command.VisitorHasherCmd, userAgentParserCmd command.UserAgentParserCmd, geoIPLocator geoip.Locator) statistics.ClickEnricher {
+	return func(click domain.ClickEvent) domain.ClickEvent {
+		return enrichClick(click, visitorHasherCmd, userAgentParserCmd, geoIPLocator)
+	}
+}
+
 // getOriginalURL retrieves an original URL given a slug
 func getOriginalURL(slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) GetOriginalURLCmd {
-	return func(ctx context.Context, slug string) (string, error) {
+	return func(ctx context.Context, slug string, click domain.ClickEvent) (string, error) {
 		// Ensure slug validity to avoid useless query to store
 		err := slugValidatorCmd(slug)
 		if err != nil {
@@ -59,14 +116,15 @@ func getOriginalURL(slugValidatorCmd command.SlugValidatorCmd, shortURLStore sho
 		if err != nil {
 			return "", err
 		}
+		if urlMapping.Disabled {
+			return "", ErrLinkDisabled
+		}
 
-		// Update statistics
-		go func(url string) {
-			err := statisticsStore.SetURL(context.Background(), url, statistics.StatisticTypeAccessed)
-			if err != nil {
-				glog.Errorf("failed to set [%s] statistics for [%s]: %w", statistics.StatisticTypeAccessed, url, err)
-			}
-		}(urlMapping.OriginalURL)
+		// Update statistics along with the click, enriched by the statistics store
+		err = statisticsStore.SetClick(ctx, urlMapping, click)
+		if err != nil {
+			glog.Errorf("failed to set [%s] statistics for [%s]: %s", statistics.StatisticTypeAccessed, urlMapping.OriginalURL, err)
+		}
 
 		return urlMapping.OriginalURL, nil
 	}
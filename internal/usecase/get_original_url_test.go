@@ -2,10 +2,11 @@ package usecase
 
 import (
 	"context"
-	"sync"
+	"net"
 	"testing"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/geoip"
 	"urlShortenerService/internal/infrastructure/malwarescanner"
 	"urlShortenerService/internal/infrastructure/shorturl"
 	"urlShortenerService/internal/infrastructure/statistics"
@@ -24,6 +25,7 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 			return err
 		}
 	}
+	click := domain.ClickEvent{UserAgent: "Firefox", IP: "82.64.1.1"}
 	var urlMappingData domain.URLMapping = domain.URLMapping{
 		Slug:        "zTw34enA",
 		OriginalURL: "https://My-Very-Long-URL.com/needs-to-be-shortened/malware",
@@ -36,21 +38,16 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 		malwareScannerMock.On("Scan", mock.Anything, urlMappingData.OriginalURL, mock.Anything).Return(malwarescanner.MalwareScanResultClear)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, urlMappingData.OriginalURL, statistics.StatisticTypeAccessed).Return(nil).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
+		statisticsMock.On("SetClick", mock.Anything, urlMappingData, click).Return(nil)
 		cmd := GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScannerMock, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, urlMappingData.OriginalURL, originalURL)
-		wg.Wait()
 	})
 	t.Run("invalid slug", func(t *testing.T) {
 		// Given
@@ -61,7 +58,7 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 		cmd := GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScannerMock, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 
 		// Then
 		require.ErrorIs(t, err, assert.AnError)
@@ -77,7 +74,7 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 		cmd := GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScannerMock, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 
 		// Then
 		require.ErrorIs(t, err, assert.AnError)
@@ -90,21 +87,16 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 		malwareScannerMock.On("Scan", mock.Anything, urlMappingData.OriginalURL, mock.Anything).Return(malwarescanner.MalwareScanResultClear)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, urlMappingData.OriginalURL, statistics.StatisticTypeAccessed).Return(assert.AnError).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
+		statisticsMock.On("SetClick", mock.Anything, urlMappingData, click).Return(assert.AnError)
 		cmd := GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScannerMock, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, urlMappingData.OriginalURL, originalURL)
-		wg.Wait()
 	})
 	t.Run("failed to scan the URL for malware", func(t *testing.T) {
 		// Given
@@ -113,21 +105,16 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 		malwareScannerMock.On("Scan", mock.Anything, urlMappingData.OriginalURL, mock.Anything).Return(malwarescanner.MalwareScanUnknownError)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, urlMappingData.OriginalURL, statistics.StatisticTypeAccessed).Return(nil).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
+		statisticsMock.On("SetClick", mock.Anything, urlMappingData, click).Return(nil)
 		cmd := GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScannerMock, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, urlMappingData.OriginalURL, originalURL)
-		wg.Wait()
 	})
 	t.Run("malware detected", func(t *testing.T) {
 		// Given
@@ -136,21 +123,16 @@ func TestGetOriginalURLWithMalwareScanCmdBuilder(t *testing.T) {
 		malwareScannerMock.On("Scan", mock.Anything, urlMappingData.OriginalURL, mock.Anything).Return(malwarescanner.MalwareScanResultDetected)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, urlMappingData.OriginalURL, statistics.StatisticTypeAccessed).Return(nil).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
+		statisticsMock.On("SetClick", mock.Anything, urlMappingData, click).Return(nil)
 		cmd := GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScannerMock, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 
 		// Then
 		require.ErrorIs(t, err, malwarescanner.ErrMalswareURL)
 		assert.Empty(t, originalURL)
-		wg.Wait()
 	})
 }
 
@@ -163,6 +145,7 @@ func TestForceGetOriginalURLCmdBuilder(t *testing.T) {
 			return err
 		}
 	}
+	click := domain.ClickEvent{UserAgent: "Firefox", IP: "82.64.1.1"}
 	var urlMappingData domain.URLMapping = domain.URLMapping{
 		Slug:        "zTw34enA",
 		OriginalURL: "https://My-Very-Long-URL.com/needs-to-be-shortened",
@@ -173,21 +156,16 @@ func TestForceGetOriginalURLCmdBuilder(t *testing.T) {
 		slugValidatorCmd := slugValidatorStub(&urlMappingData.Slug, nil)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, urlMappingData.OriginalURL, statistics.StatisticTypeAccessed).Return(nil).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
+		statisticsMock.On("SetClick", mock.Anything, urlMappingData, click).Return(nil)
 		cmd := ForceGetOriginalURLCmdBuilder(slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, urlMappingData.OriginalURL, originalURL)
-		wg.Wait()
 	})
 	t.Run("invalid slug", func(t *testing.T) {
 		// Given
@@ -197,7 +175,7 @@ func TestForceGetOriginalURLCmdBuilder(t *testing.T) {
 		cmd := ForceGetOriginalURLCmdBuilder(slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 
 		// Then
 		require.ErrorIs(t, err, assert.AnError)
@@ -212,31 +190,152 @@ func TestForceGetOriginalURLCmdBuilder(t *testing.T) {
 		cmd := ForceGetOriginalURLCmdBuilder(slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 
 		// Then
 		require.ErrorIs(t, err, assert.AnError)
 		assert.Empty(t, originalURL)
 	})
+	t.Run("disabled link", func(t *testing.T) {
+		// Given
+		disabledURLMapping := urlMappingData
+		disabledURLMapping.Disabled = true
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(disabledURLMapping, nil)
+		cmd := ForceGetOriginalURLCmdBuilder(slugValidatorStub(nil, nil), shortURLMock, statistics.NewMockStore(t))
+
+		// When
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
+
+		// Then
+		require.ErrorIs(t, err, ErrLinkDisabled)
+		assert.Empty(t, originalURL)
+	})
 	t.Run("failed updating statistics", func(t *testing.T) {
 		// Given
 		slugValidatorCmd := slugValidatorStub(&urlMappingData.Slug, nil)
 		shortURLMock := shorturl.NewMock(t)
 		shortURLMock.On("Get", mock.Anything, urlMappingData.Slug).Return(urlMappingData, nil)
-		var wg sync.WaitGroup
-		wg.Add(1)
 		statisticsMock := statistics.NewMockStore(t)
-		statisticsMock.On("SetURL", mock.Anything, urlMappingData.OriginalURL, statistics.StatisticTypeAccessed).Return(assert.AnError).Run(func(args mock.Arguments) {
-			wg.Done()
-		})
+		statisticsMock.On("SetClick", mock.Anything, urlMappingData, click).Return(assert.AnError)
 		cmd := ForceGetOriginalURLCmdBuilder(slugValidatorCmd, shortURLMock, statisticsMock)
 
 		// When
-		originalURL, err := cmd(context.Background(), urlMappingData.Slug)
+		originalURL, err := cmd(context.Background(), urlMappingData.Slug, click)
 		require.NoError(t, err)
 
 		// Then
 		assert.Equal(t, urlMappingData.OriginalURL, originalURL)
-		wg.Wait()
+	})
+}
+
+func TestClickEnricherBuilder(t *testing.T) {
+	// Given
+	visitorHasherStub := func(ip string, userAgent string) string {
+		return ip + userAgent
+	}
+	userAgentParserStub := func(userAgent string) (string, string, domain.Device) {
+		return userAgent, "Linux", domain.DeviceDesktop
+	}
+	enrich := ClickEnricherBuilder(visitorHasherStub, userAgentParserStub, nil)
+
+	// When
+	click := enrich(domain.ClickEvent{UserAgent: "Firefox", IP: "82.64.1.1"})
+
+	// Then
+	assert.Equal(t, domain.ClickEvent{UserAgent: "Firefox", IP: "82.64.1.1", Visitor: "82.64.1.1Firefox", Browser: "Firefox", OS: "Linux", Device: domain.DeviceDesktop}, click)
+}
+
+func TestEnrichClick(t *testing.T) {
+	visitorHasherStub := func(ip string, userAgent string) string {
+		return "visitor-1"
+	}
+	userAgentParserStub := func(userAgent string) (string, string, domain.Device) {
+		return "Safari", "iOS", domain.DeviceMobile
+	}
+	scenarios := []struct {
+		Name             string
+		Click            domain.ClickEvent
+		LocatorCountry   string
+		LocatorErr       error
+		ExpectedReferrer string
+		ExpectedCountry  string
+	}{
+		{
+			Name:             "nominal",
+			Click:            domain.ClickEvent{Referrer: "https://News.ycombinator.com/item?id=1", IP: "82.64.1.1"},
+			LocatorCountry:   "FR",
+			ExpectedReferrer: "news.ycombinator.com",
+			ExpectedCountry:  "FR",
+		},
+		{
+			Name:             "direct access",
+			Click:            domain.ClickEvent{IP: "82.64.1.1"},
+			LocatorCountry:   "FR",
+			ExpectedReferrer: "",
+			ExpectedCountry:  "FR",
+		},
+		{
+			Name:             "referrer without host",
+			Click:            domain.ClickEvent{Referrer: "android-app", IP: "82.64.1.1"},
+			LocatorCountry:   "FR",
+			ExpectedReferrer: "unknown",
+			ExpectedCountry:  "FR",
+		},
+		{
+			Name:             "unparsable referrer",
+			Click:            domain.ClickEvent{Referrer: "http://[::1", IP: "82.64.1.1"},
+			LocatorCountry:   "FR",
+			ExpectedReferrer: "unknown",
+			ExpectedCountry:  "FR",
+		},
+		{
+			Name:            "failed to locate",
+			Click:           domain.ClickEvent{IP: "82.64.1.1"},
+			LocatorErr:      assert.AnError,
+			ExpectedCountry: "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			// Given
+			locatorMock := geoip.NewMockLocator(t)
+			locatorMock.On("Country", net.ParseIP(scenario.Click.IP)).Return(scenario.LocatorCountry, scenario.LocatorErr)
+
+			// When
+			click := enrichClick(scenario.Click, visitorHasherStub, userAgentParserStub, locatorMock)
+
+			// Then
+			assert.Equal(t, scenario.ExpectedReferrer, click.Referrer)
+			assert.Equal(t, scenario.ExpectedCountry, click.Country)
+			assert.Equal(t, "visitor-1", click.Visitor)
+			assert.Equal(t, "Safari", click.Browser)
+			assert.Equal(t, "iOS", click.OS)
+			assert.Equal(t, domain.DeviceMobile, click.Device)
+		})
+	}
+	t.Run("without locator", func(t *testing.T) {
+		// When
+		click := enrichClick(domain.ClickEvent{IP: "82.64.1.1"}, visitorHasherStub, userAgentParserStub, nil)
+
+		// Then
+		assert.Empty(t, click.Country)
+	})
+	t.Run("without IP", func(t *testing.T) {
+		// When
+		click := enrichClick(domain.ClickEvent{UserAgent: "curl/8.4.0"}, visitorHasherStub, userAgentParserStub, nil)
+
+		// Then
+		assert.Empty(t, click.Visitor)
+	})
+	t.Run("invalid IP", func(t *testing.T) {
+		// Given
+		locatorMock := geoip.NewMockLocator(t)
+
+		// When
+		click := enrichClick(domain.ClickEvent{IP: "unknown"}, visitorHasherStub, userAgentParserStub, locatorMock)
+
+		// Then
+		assert.Empty(t, click.Country)
 	})
 }
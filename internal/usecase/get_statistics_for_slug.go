@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+)
+
+// GetStatisticsForSlugCmd represents the function signature of the command that retrieves statistics for a given slug
+type GetStatisticsForSlugCmd func(ctx context.Context, slug string) (domain.URLStatistic, error)
+
+// getStatisticsForSlug retrieves statistics for a given slug along with the URL it points to, if it still exists
+func getStatisticsForSlug(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) GetStatisticsForSlugCmd {
+	return func(ctx context.Context, slug string) (domain.URLStatistic, error) {
+		// Ensure the caller is allowed to read statistics
+		err := authorizeCmd(ctx, domain.PermissionReadStatistics)
+		if err != nil {
+			return domain.URLStatistic{}, err
+		}
+
+		// Ensure slug validity to avoid useless query to store
+		err = slugValidatorCmd(slug)
+		if err != nil {
+			return domain.URLStatistic{}, err
+		}
+
+		// Retrieves statistics
+		statistic, err := statisticsStore.GetSlug(ctx, slug)
+		if err != nil {
+			return domain.URLStatistic{}, err
+		}
+
+		// Retrieves the URL, the statistics of a deleted or expired slug are kept
+		urlMapping, err := shortURLStore.Get(ctx, slug)
+		switch {
+		case err == nil:
+			statistic.URL = urlMapping.OriginalURL
+		case errors.Is(err, shorturl.ErrNotFound):
+			if statistic.ShortenedCounter == 0 && statistic.AccessedCounter == 0 {
+				return domain.URLStatistic{}, err
+			}
+		case errors.Is(err, shorturl.ErrExpired):
+		default:
+			return domain.URLStatistic{}, err
+		}
+		return statistic, nil
+	}
+}
+
+// GetStatisticsForSlugCmdBuilder builds the command that will retrieves statistics for a slug
+func GetStatisticsForSlugCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) GetStatisticsForSlugCmd {
+	return getStatisticsForSlug(authorizeCmd, slugValidatorCmd, shortURLStore, statisticsStore)
+}
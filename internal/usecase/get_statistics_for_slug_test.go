@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatisticsForSlugCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(expectedSlug *string, err error) command.SlugValidatorCmd {
+		return func(slug string) error {
+			if expectedSlug != nil {
+				assert.Equal(t, *expectedSlug, slug)
+			}
+			return err
+		}
+	}
+	var slug string = "spring-sale"
+	var originalURL string = "https://example.com/sale"
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetSlug", mock.Anything, slug).Return(domain.URLStatistic{Slug: slug, ShortenedCounter: 1, AccessedCounter: 10}, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{Slug: slug, OriginalURL: originalURL}, nil)
+		cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), shortURLMock, statisticsMock)
+
+		// When
+		statistic, err := cmd(context.Background(), slug)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, domain.URLStatistic{URL: originalURL, Slug: slug, ShortenedCounter: 1, AccessedCounter: 10}, statistic)
+	})
+	t.Run("slug gone", func(t *testing.T) {
+		for _, storeErr := range []error{shorturl.ErrNotFound, shorturl.ErrExpired} {
+			t.Run(storeErr.Error(), func(t *testing.T) {
+				// Given
+				statisticsMock := statistics.NewMockStore(t)
+				statisticsMock.On("GetSlug", mock.Anything, slug).Return(domain.URLStatistic{Slug: slug, AccessedCounter: 10}, nil)
+				shortURLMock := shorturl.NewMock(t)
+				shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, storeErr)
+				cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), shortURLMock, statisticsMock)
+
+				// When
+				statistic, err := cmd(context.Background(), slug)
+				require.NoError(t, err)
+
+				// Then
+				assert.Equal(t, domain.URLStatistic{Slug: slug, AccessedCounter: 10}, statistic)
+			})
+		}
+	})
+	t.Run("unknown slug", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetSlug", mock.Anything, slug).Return(domain.URLStatistic{Slug: slug}, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, shorturl.ErrNotFound)
+		cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), shortURLMock, statisticsMock)
+
+		// When
+		statistic, err := cmd(context.Background(), slug)
+
+		// Then
+		require.ErrorIs(t, err, shorturl.ErrNotFound)
+		assert.Empty(t, statistic)
+	})
+	t.Run("forbidden without statistics permission", func(t *testing.T) {
+		// Given
+		cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil, nil), shorturl.NewMock(t), statistics.NewMockStore(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.Role("unknown")})
+
+		// When
+		statistic, err := cmd(ctx, slug)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, statistic)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, command.ErrInvalidSlugNonAlphanumeric), shorturl.NewMock(t), statistics.NewMockStore(t))
+
+		// When
+		statistic, err := cmd(context.Background(), slug)
+
+		// Then
+		require.ErrorIs(t, err, command.ErrInvalidSlugNonAlphanumeric)
+		assert.Empty(t, statistic)
+	})
+	t.Run("failed retrieving statistics", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetSlug", mock.Anything, slug).Return(domain.URLStatistic{}, assert.AnError)
+		cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), shorturl.NewMock(t), statisticsMock)
+
+		// When
+		statistic, err := cmd(context.Background(), slug)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, statistic)
+	})
+	t.Run("failed retrieving URL", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetSlug", mock.Anything, slug).Return(domain.URLStatistic{Slug: slug, AccessedCounter: 10}, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{}, assert.AnError)
+		cmd := GetStatisticsForSlugCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub(&slug, nil), shortURLMock, statisticsMock)
+
+		// When
+		statistic, err := cmd(context.Background(), slug)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, statistic)
+	})
+}
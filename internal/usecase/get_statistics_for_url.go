@@ -11,8 +11,14 @@ import (
 type GetStatisticsForURLCmd func(ctx context.Context, url string) (domain.URLStatistic, error)
 
 // getStatisticsForURL retrieves statistics for a given URL
-func getStatisticsForURL(urlSanitizerCmd command.URLSanitizerCmd, statisticsStore statistics.Store) GetStatisticsForURLCmd {
+func getStatisticsForURL(authorizeCmd AuthorizeCmd, urlSanitizerCmd command.URLSanitizerCmd, statisticsStore statistics.Store) GetStatisticsForURLCmd {
 	return func(ctx context.Context, url string) (domain.URLStatistic, error) {
+		// Ensure the caller is allowed to read statistics
+		err := authorizeCmd(ctx, domain.PermissionReadStatistics)
+		if err != nil {
+			return domain.URLStatistic{}, err
+		}
+
 		// Sanitize and validate URL
 		sanitizedURL, err := urlSanitizerCmd(url)
 		if err != nil {
@@ -25,6 +31,6 @@ func getStatisticsForURL(urlSanitizerCmd command.URLSanitizerCmd, statisticsStor
 }
 
 // GetStatisticsForURLCmdBuilder builds the command that will retrieves statistics
-func GetStatisticsForURLCmdBuilder(urlSanitizerCmd command.URLSanitizerCmd, statisticsStore statistics.Store) GetStatisticsForURLCmd {
-	return getStatisticsForURL(urlSanitizerCmd, statisticsStore)
+func GetStatisticsForURLCmdBuilder(authorizeCmd AuthorizeCmd, urlSanitizerCmd command.URLSanitizerCmd, statisticsStore statistics.Store) GetStatisticsForURLCmd {
+	return getStatisticsForURL(authorizeCmd, urlSanitizerCmd, statisticsStore)
 }
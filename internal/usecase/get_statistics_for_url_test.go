@@ -30,7 +30,7 @@ func TestGetStatisticsForURLCmdBuilder(t *testing.T) {
 		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
 		statisticsMock := statistics.NewMockStore(t)
 		statisticsMock.On("GetURL", mock.Anything, sanitizedURL).Return(expectedURLStatistics, nil)
-		cmd := GetStatisticsForURLCmdBuilder(urlSanitizerCmd, statisticsMock)
+		cmd := GetStatisticsForURLCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerCmd, statisticsMock)
 
 		// When
 		urlStatisticsResp, err := cmd(context.Background(), originalURL)
@@ -39,11 +39,23 @@ func TestGetStatisticsForURLCmdBuilder(t *testing.T) {
 		// Then
 		assert.Equal(t, expectedURLStatistics, urlStatisticsResp)
 	})
+	t.Run("forbidden without statistics permission", func(t *testing.T) {
+		// Given
+		cmd := GetStatisticsForURLCmdBuilder(AuthorizeCmdBuilder(true), urlSanitizerStub(nil, "", nil), statistics.NewMockStore(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.Role("unknown")})
+
+		// When
+		urlStatisticsResp, err := cmd(ctx, originalURL)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, urlStatisticsResp)
+	})
 	t.Run("failed sanitizing URL", func(t *testing.T) {
 		// Given
 		urlSanitizerCmd := urlSanitizerStub(nil, "", assert.AnError)
 		statisticsMock := statistics.NewMockStore(t)
-		cmd := GetStatisticsForURLCmdBuilder(urlSanitizerCmd, statisticsMock)
+		cmd := GetStatisticsForURLCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerCmd, statisticsMock)
 
 		// When
 		urlStatisticsResp, err := cmd(context.Background(), originalURL)
@@ -57,7 +69,7 @@ func TestGetStatisticsForURLCmdBuilder(t *testing.T) {
 		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
 		statisticsMock := statistics.NewMockStore(t)
 		statisticsMock.On("GetURL", mock.Anything, mock.Anything).Return(domain.URLStatistic{}, assert.AnError)
-		cmd := GetStatisticsForURLCmdBuilder(urlSanitizerCmd, statisticsMock)
+		cmd := GetStatisticsForURLCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerCmd, statisticsMock)
 
 		// When
 		urlStatisticsResp, err := cmd(context.Background(), originalURL)
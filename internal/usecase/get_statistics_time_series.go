@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+)
+
+const (
+	// defaultTimeSeriesPoints is the number of buckets of a time series when no start is asked
+	defaultTimeSeriesPoints int = 24
+	// maxTimeSeriesPoints is the maximal number of buckets of a time series
+	maxTimeSeriesPoints int = 1000
+)
+
+var (
+	// ErrInvalidGranularity is the error when the granularity asked for a time series is unknown
+	ErrInvalidGranularity error = errors.New("granularity is invalid")
+	// ErrInvalidTimeRange is the error when the time range asked for a time series is empty or holds too many buckets
+	ErrInvalidTimeRange error = errors.New("time range is invalid")
+)
+
+// GetStatisticsTimeSeriesCmd represents the function signature of the command that retrieves the statistics of a given URL per bucket of time
+// The granularity defaults to hour, to defaults to now and from to the start of the default number of buckets before to
+type GetStatisticsTimeSeriesCmd func(ctx context.Context, url string, granularity statistics.Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error)
+
+// getStatisticsTimeSeries retrieves the statistics of a given URL per bucket of time
+func getStatisticsTimeSeries(authorizeCmd AuthorizeCmd, urlSanitizerCmd command.URLSanitizerCmd, statisticsStore statistics.Store) GetStatisticsTimeSeriesCmd {
+	return func(ctx context.Context, url string, granularity statistics.Granularity, from time.Time, to time.Time) ([]domain.URLStatisticPoint, error) {
+		// Ensure the caller is allowed to read statistics
+		err := authorizeCmd(ctx, domain.PermissionReadStatistics)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ensure granularity and time range validity
+		if granularity == "" {
+			granularity = statistics.GranularityHour
+		}
+		if granularity.Duration() == 0 {
+			return nil, ErrInvalidGranularity
+		}
+		if to.IsZero() {
+			to = time.Now()
+		}
+		if from.IsZero() {
+			// Starts the default number of buckets before the last one, the bucket holding to being excluded
+			lastBucket := granularity.Truncate(to.Add(-time.Nanosecond))
+			from = lastBucket.Add(-time.Duration(defaultTimeSeriesPoints-1) * granularity.Duration())
+		}
+		if !from.Before(to) || to.Sub(granularity.Truncate(from)) > time.Duration(maxTimeSeriesPoints)*granularity.Duration() {
+			return nil, ErrInvalidTimeRange
+		}
+
+		// Sanitize and validate URL
+		sanitizedURL, err := urlSanitizerCmd(url)
+		if err != nil {
+			return nil, err
+		}
+
+		// Retrieves statistics
+		return statisticsStore.GetURLTimeSeries(ctx, sanitizedURL, granularity, from, to)
+	}
+}
+
+// GetStatisticsTimeSeriesCmdBuilder builds the command that will retrieves the statistics per bucket of time
+func GetStatisticsTimeSeriesCmdBuilder(authorizeCmd AuthorizeCmd, urlSanitizerCmd command.URLSanitizerCmd, statisticsStore statistics.Store) GetStatisticsTimeSeriesCmd {
+	return getStatisticsTimeSeries(authorizeCmd, urlSanitizerCmd, statisticsStore)
+}
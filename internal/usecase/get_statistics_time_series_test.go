@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatisticsTimeSeriesCmdBuilder(t *testing.T) {
+	urlSanitizerStub := func(returnedURL string, err error) command.URLSanitizerCmd {
+		return func(rawURL string) (string, error) {
+			return returnedURL, err
+		}
+	}
+	var originalURL string = "https://My-Very-Long-URL.com/needs-to-be-shortened"
+	var sanitizedURL string = "https://my-very-long-url.com/needs-to-be-shortened"
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		expectedPoints := []domain.URLStatisticPoint{{Time: from, AccessedCounter: 3, ShortenedCounter: 1}}
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetURLTimeSeries", mock.Anything, sanitizedURL, statistics.GranularityDay, from, to).Return(expectedPoints, nil)
+		cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), statisticsMock)
+
+		// When
+		points, err := cmd(context.Background(), originalURL, statistics.GranularityDay, from, to)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedPoints, points)
+	})
+	t.Run("default time range", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetURLTimeSeries", mock.Anything, sanitizedURL, statistics.GranularityHour, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				assert.Len(t, statistics.GranularityHour.Buckets(args.Get(3).(time.Time), args.Get(4).(time.Time)), defaultTimeSeriesPoints)
+				assert.WithinDuration(t, time.Now(), args.Get(4).(time.Time), time.Minute)
+			}).
+			Return([]domain.URLStatisticPoint{}, nil)
+		cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), statisticsMock)
+
+		// When
+		_, err := cmd(context.Background(), originalURL, "", time.Time{}, time.Time{})
+
+		// Then
+		require.NoError(t, err)
+	})
+	t.Run("invalid granularity", func(t *testing.T) {
+		// Given
+		cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), statistics.NewMockStore(t))
+
+		// When
+		points, err := cmd(context.Background(), originalURL, statistics.Granularity("week"), from, to)
+
+		// Then
+		require.ErrorIs(t, err, ErrInvalidGranularity)
+		assert.Empty(t, points)
+	})
+	t.Run("invalid time range", func(t *testing.T) {
+		scenarios := []struct {
+			Name string
+			From time.Time
+			To   time.Time
+		}{
+			{Name: "from after to", From: to, To: from},
+			{Name: "empty", From: from, To: from},
+			{Name: "too many buckets", From: from, To: from.Add(time.Duration(maxTimeSeriesPoints+1) * time.Hour)},
+		}
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				// Given
+				cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), statistics.NewMockStore(t))
+
+				// When
+				points, err := cmd(context.Background(), originalURL, statistics.GranularityHour, scenario.From, scenario.To)
+
+				// Then
+				require.ErrorIs(t, err, ErrInvalidTimeRange)
+				assert.Empty(t, points)
+			})
+		}
+	})
+	t.Run("forbidden without statistics permission", func(t *testing.T) {
+		// Given
+		cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(true), urlSanitizerStub(sanitizedURL, nil), statistics.NewMockStore(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.Role("unknown")})
+
+		// When
+		points, err := cmd(ctx, originalURL, statistics.GranularityHour, from, to)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, points)
+	})
+	t.Run("failed sanitizing URL", func(t *testing.T) {
+		// Given
+		cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub("", assert.AnError), statistics.NewMockStore(t))
+
+		// When
+		points, err := cmd(context.Background(), originalURL, statistics.GranularityHour, from, to)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, points)
+	})
+	t.Run("failed retrieving statistics", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetURLTimeSeries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		cmd := GetStatisticsTimeSeriesCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), statisticsMock)
+
+		// When
+		points, err := cmd(context.Background(), originalURL, statistics.GranularityHour, from, to)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, points)
+	})
+}
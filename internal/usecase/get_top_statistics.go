@@ -10,13 +10,19 @@ import (
 type GetTopStatisticsCmd func(ctx context.Context, statType statistics.StatisticType, limitOveride int64) ([]domain.URLStatistic, error)
 
 // getTopStatistics retrieves top statistics for a given statistic type
-func getTopStatistics(statisticsStore statistics.Store) GetTopStatisticsCmd {
+func getTopStatistics(authorizeCmd AuthorizeCmd, statisticsStore statistics.Store) GetTopStatisticsCmd {
 	return func(ctx context.Context, statType statistics.StatisticType, limitOveride int64) ([]domain.URLStatistic, error) {
+		// Ensure the caller is allowed to read statistics
+		err := authorizeCmd(ctx, domain.PermissionReadStatistics)
+		if err != nil {
+			return nil, err
+		}
+
 		return statisticsStore.GetTopURLs(ctx, statType, limitOveride)
 	}
 }
 
 // GetTopStatisticsCmdBuilder builds the command that will retrieves top statistics
-func GetTopStatisticsCmdBuilder(statisticsStore statistics.Store) GetTopStatisticsCmd {
-	return getTopStatistics(statisticsStore)
+func GetTopStatisticsCmdBuilder(authorizeCmd AuthorizeCmd, statisticsStore statistics.Store) GetTopStatisticsCmd {
+	return getTopStatistics(authorizeCmd, statisticsStore)
 }
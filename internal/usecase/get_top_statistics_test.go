@@ -22,7 +22,7 @@ func TestGetTopStatisticsCmdBuilder(t *testing.T) {
 		}
 		statisticsMock := statistics.NewMockStore(t)
 		statisticsMock.On("GetTopURLs", mock.Anything, statType, limitOveride).Return(expectedURLStatistics, nil)
-		cmd := GetTopStatisticsCmdBuilder(statisticsMock)
+		cmd := GetTopStatisticsCmdBuilder(AuthorizeCmdBuilder(false), statisticsMock)
 
 		// When
 		urlStatisticsResp, err := cmd(context.Background(), statType, limitOveride)
@@ -31,11 +31,33 @@ func TestGetTopStatisticsCmdBuilder(t *testing.T) {
 		// Then
 		assert.Equal(t, expectedURLStatistics, urlStatisticsResp)
 	})
+	t.Run("nominal for a viewer", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("GetTopURLs", mock.Anything, statistics.StatisticTypeAccessed, int64(0)).Return([]domain.URLStatistic{}, nil)
+		cmd := GetTopStatisticsCmdBuilder(AuthorizeCmdBuilder(true), statisticsMock)
+
+		// When
+		_, err := cmd(domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleViewer}), statistics.StatisticTypeAccessed, 0)
+
+		// Then
+		require.NoError(t, err)
+	})
+	t.Run("forbidden without API key", func(t *testing.T) {
+		// Given
+		cmd := GetTopStatisticsCmdBuilder(AuthorizeCmdBuilder(true), statistics.NewMockStore(t))
+
+		// When
+		_, err := cmd(context.Background(), statistics.StatisticTypeAccessed, 0)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
 	t.Run("failed retrieving statistics", func(t *testing.T) {
 		// Given
 		statisticsMock := statistics.NewMockStore(t)
 		statisticsMock.On("GetTopURLs", mock.Anything, mock.Anything, mock.Anything).Return([]domain.URLStatistic{}, assert.AnError)
-		cmd := GetTopStatisticsCmdBuilder(statisticsMock)
+		cmd := GetTopStatisticsCmdBuilder(AuthorizeCmdBuilder(false), statisticsMock)
 
 		// When
 		urlStatisticsResp, err := cmd(context.Background(), statistics.StatisticTypeShortened, 0)
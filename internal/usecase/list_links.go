@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+const (
+	// defaultListLinksLimit is the number of links within a page when no limit is asked
+	defaultListLinksLimit int = 50
+	// maxListLinksLimit is the maximal number of links within a page
+	maxListLinksLimit int = 1000
+)
+
+var (
+	// ErrInvalidListLimit is the error when the limit asked for a links listing is invalid
+	ErrInvalidListLimit error = errors.New("list limit is invalid")
+)
+
+// ListLinksCmd represents the function signature of the command that lists the links matching a filter
+type ListLinksCmd func(ctx context.Context, filter shorturl.ListFilter) (shorturl.ListPage, error)
+
+// listLinks lists a page of the links matching a filter
+func listLinks(authorizeCmd AuthorizeCmd, shortURLStore shorturl.Store) ListLinksCmd {
+	return func(ctx context.Context, filter shorturl.ListFilter) (shorturl.ListPage, error) {
+		// Ensure the caller is allowed to read links
+		err := authorizeCmd(ctx, domain.PermissionReadLinks)
+		if err != nil {
+			return shorturl.ListPage{}, err
+		}
+
+		// Ensure limit validity
+		if filter.Limit == 0 {
+			filter.Limit = defaultListLinksLimit
+		}
+		if filter.Limit < 0 || filter.Limit > maxListLinksLimit {
+			return shorturl.ListPage{}, ErrInvalidListLimit
+		}
+
+		// Hosts are lowercased by the URL sanitizer
+		filter.Domain = strings.ToLower(strings.TrimSpace(filter.Domain))
+
+		// Retrieves links
+		return shortURLStore.List(ctx, filter)
+	}
+}
+
+// ListLinksCmdBuilder builds the command that will lists the links
+func ListLinksCmdBuilder(authorizeCmd AuthorizeCmd, shortURLStore shorturl.Store) ListLinksCmd {
+	return listLinks(authorizeCmd, shortURLStore)
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListLinksCmdBuilder(t *testing.T) {
+	var page shorturl.ListPage = shorturl.ListPage{
+		URLMappings: []domain.URLMapping{{Slug: "zTw34enA", OriginalURL: "https://example.com/"}},
+		NextCursor:  "cursor",
+	}
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("List", mock.Anything, shorturl.ListFilter{Domain: "example.com", Limit: 10}).Return(page, nil)
+		cmd := ListLinksCmdBuilder(AuthorizeCmdBuilder(false), shortURLMock)
+
+		// When
+		listedPage, err := cmd(context.Background(), shorturl.ListFilter{Domain: " Example.COM ", Limit: 10})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, page, listedPage)
+	})
+	t.Run("default limit", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("List", mock.Anything, shorturl.ListFilter{Limit: defaultListLinksLimit}).Return(page, nil)
+		cmd := ListLinksCmdBuilder(AuthorizeCmdBuilder(false), shortURLMock)
+
+		// When
+		listedPage, err := cmd(context.Background(), shorturl.ListFilter{})
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, page, listedPage)
+	})
+	t.Run("forbidden for a viewer", func(t *testing.T) {
+		// Given
+		cmd := ListLinksCmdBuilder(AuthorizeCmdBuilder(true), shorturl.NewMock(t))
+
+		// When
+		listedPage, err := cmd(domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleViewer}), shorturl.ListFilter{})
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, listedPage)
+	})
+	t.Run("invalid limit", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		cmd := ListLinksCmdBuilder(AuthorizeCmdBuilder(false), shortURLMock)
+
+		// When
+		listedPage, err := cmd(context.Background(), shorturl.ListFilter{Limit: maxListLinksLimit + 1})
+
+		// Then
+		require.ErrorIs(t, err, ErrInvalidListLimit)
+		assert.Empty(t, listedPage)
+	})
+	t.Run("failed listing links", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("List", mock.Anything, mock.Anything).Return(shorturl.ListPage{}, assert.AnError)
+		cmd := ListLinksCmdBuilder(AuthorizeCmdBuilder(false), shortURLMock)
+
+		// When
+		listedPage, err := cmd(context.Background(), shorturl.ListFilter{})
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, listedPage)
+	})
+}
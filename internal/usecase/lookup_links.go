@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+// LookupLinksCmd represents the function signature of the command that retrieves the links of a destination URL
+type LookupLinksCmd func(ctx context.Context, url string) ([]domain.URLMapping, error)
+
+// lookupLinks retrieves the URL mappings of a destination URL once sanitized, the same way it is stored on creation
+func lookupLinks(authorizeCmd AuthorizeCmd, urlSanitizerCmd command.URLSanitizerCmd, shortURLStore shorturl.Store) LookupLinksCmd {
+	return func(ctx context.Context, url string) ([]domain.URLMapping, error) {
+		// Ensure the caller is allowed to read links
+		err := authorizeCmd(ctx, domain.PermissionReadLinks)
+		if err != nil {
+			return nil, err
+		}
+
+		// Sanitize and validate URL
+		sanitizedURL, err := urlSanitizerCmd(url)
+		if err != nil {
+			return nil, err
+		}
+
+		// Retrieves URL mappings
+		return shortURLStore.GetByOriginalURL(ctx, sanitizedURL)
+	}
+}
+
+// LookupLinksCmdBuilder builds the command that will retrieves the links of a destination URL
+func LookupLinksCmdBuilder(authorizeCmd AuthorizeCmd, urlSanitizerCmd command.URLSanitizerCmd, shortURLStore shorturl.Store) LookupLinksCmd {
+	return lookupLinks(authorizeCmd, urlSanitizerCmd, shortURLStore)
+}
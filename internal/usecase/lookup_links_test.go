@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupLinksCmdBuilder(t *testing.T) {
+	urlSanitizerStub := func(returnedURL string, err error) command.URLSanitizerCmd {
+		return func(rawURL string) (string, error) {
+			assert.Equal(t, "https://Example.com", rawURL)
+			return returnedURL, err
+		}
+	}
+	var sanitizedURL string = "https://example.com/"
+	var urlMappings []domain.URLMapping = []domain.URLMapping{
+		{Slug: "zTw34enA", OriginalURL: sanitizedURL},
+		{Slug: "spring-sale", OriginalURL: sanitizedURL},
+	}
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetByOriginalURL", mock.Anything, sanitizedURL).Return(urlMappings, nil)
+		cmd := LookupLinksCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), shortURLMock)
+
+		// When
+		retrievedURLMappings, err := cmd(context.Background(), "https://Example.com")
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, urlMappings, retrievedURLMappings)
+	})
+	t.Run("invalid URL", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		cmd := LookupLinksCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub("", command.ErrInvalidURL), shortURLMock)
+
+		// When
+		retrievedURLMappings, err := cmd(context.Background(), "https://Example.com")
+
+		// Then
+		require.ErrorIs(t, err, command.ErrInvalidURL)
+		assert.Empty(t, retrievedURLMappings)
+	})
+	t.Run("failed retrieving URL mappings", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetByOriginalURL", mock.Anything, sanitizedURL).Return(nil, assert.AnError)
+		cmd := LookupLinksCmdBuilder(AuthorizeCmdBuilder(false), urlSanitizerStub(sanitizedURL, nil), shortURLMock)
+
+		// When
+		retrievedURLMappings, err := cmd(context.Background(), "https://Example.com")
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, retrievedURLMappings)
+	})
+}
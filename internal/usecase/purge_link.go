@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+// PurgeLinkCmd represents the function signature of the command that purges a link given a slug
+type PurgeLinkCmd func(ctx context.Context, slug string) error
+
+// purgeLink deletes a link given a slug along with its tombstone, whatever its owner and its state, so that the slug is forgotten
+func purgeLink(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) PurgeLinkCmd {
+	return func(ctx context.Context, slug string) error {
+		// Ensure the caller is allowed to purge links
+		err := authorizeCmd(ctx, domain.PermissionPurgeLinks)
+		if err != nil {
+			return err
+		}
+
+		// Ensure slug validity to avoid useless query to store
+		err = slugValidatorCmd(slug)
+		if err != nil {
+			return err
+		}
+
+		// Purges URL
+		return shortURLStore.Purge(ctx, slug)
+	}
+}
+
+// PurgeLinkCmdBuilder builds the command that will purge a link
+func PurgeLinkCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store) PurgeLinkCmd {
+	return purgeLink(authorizeCmd, slugValidatorCmd, shortURLStore)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeLinkCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(err error) func(slug string) error {
+		return func(slug string) error {
+			return err
+		}
+	}
+	var slug string = "zTw34enA"
+	adminCtx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "admin", Role: domain.RoleAdmin})
+
+	t.Run("nominal for an admin", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Purge", mock.Anything, slug).Return(nil)
+		cmd := PurgeLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shortURLMock)
+
+		// When
+		err := cmd(adminCtx, slug)
+
+		// Then
+		require.NoError(t, err)
+	})
+	t.Run("forbidden for the editor owning the link", func(t *testing.T) {
+		// Given
+		cmd := PurgeLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shorturl.NewMock(t))
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-1", Role: domain.RoleEditor})
+
+		// When
+		err := cmd(ctx, slug)
+
+		// Then
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		cmd := PurgeLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(assert.AnError), shorturl.NewMock(t))
+
+		// When
+		err := cmd(adminCtx, slug)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("failed purging URL", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Purge", mock.Anything, slug).Return(shorturl.ErrNotFound)
+		cmd := PurgeLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorStub(nil), shortURLMock)
+
+		// When
+		err := cmd(adminCtx, slug)
+
+		// Then
+		assert.ErrorIs(t, err, shorturl.ErrNotFound)
+	})
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/golang/glog"
+)
+
+// maxResolveSlugsBatchSize is the maximal number of slugs that can be resolved at once
+const maxResolveSlugsBatchSize int = 1000
+
+// ResolveSlugResult holds the result of a slug resolution within a batch
+type ResolveSlugResult struct {
+	OriginalURL string
+	Err         error // Nil if the slug has been resolved
+}
+
+// ResolveSlugsCmd represents the function signature of the command that retrieves the original URLs of several slugs at once
+// It returns a result per slug, in the same order
+type ResolveSlugsCmd func(ctx context.Context, slugs []string, countAccess bool) ([]ResolveSlugResult, error)
+
+// resolveSlugs retrieves the original URLs of several slugs at once
+// The resolutions only count toward the accessed statistics if asked
+func resolveSlugs(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) ResolveSlugsCmd {
+	return func(ctx context.Context, slugs []string, countAccess bool) ([]ResolveSlugResult, error) {
+		// Ensure the caller is allowed to read links
+		err := authorizeCmd(ctx, domain.PermissionReadLinks)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(slugs) == 0 || len(slugs) > maxResolveSlugsBatchSize {
+			return nil, ErrInvalidBatchSize
+		}
+
+		// Ensure slugs validity to avoid useless query to store
+		results := make([]ResolveSlugResult, len(slugs))
+		var validSlugs []string
+		var validIndexes []int
+		for i, slug := range slugs {
+			err := slugValidatorCmd(slug)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			validSlugs = append(validSlugs, slug)
+			validIndexes = append(validIndexes, i)
+		}
+		if len(validSlugs) == 0 {
+			return results, nil
+		}
+
+		// Retrieves URLs
+		urlMappings, errs, err := shortURLStore.GetBatch(ctx, validSlugs)
+		if err != nil {
+			return nil, err
+		}
+		var resolvedURLs []domain.URLMapping
+		for j, i := range validIndexes {
+			if errs[j] != nil {
+				results[i].Err = errs[j]
+				continue
+			}
+			if urlMappings[j].Disabled {
+				results[i].Err = ErrLinkDisabled
+				continue
+			}
+			results[i].OriginalURL = urlMappings[j].OriginalURL
+			resolvedURLs = append(resolvedURLs, urlMappings[j])
+		}
+
+		// Update statistics
+		if countAccess && len(resolvedURLs) > 0 {
+			err := statisticsStore.SetURLs(ctx, resolvedURLs, statistics.StatisticTypeAccessed)
+			if err != nil {
+				glog.Errorf("failed to set [%s] statistics for [%d] URLs: %v", statistics.StatisticTypeAccessed, len(resolvedURLs), err)
+			}
+		}
+
+		return results, nil
+	}
+}
+
+// ResolveSlugsCmdBuilder builds the command that will retrieves the original URLs of several slugs at once
+func ResolveSlugsCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, shortURLStore shorturl.Store, statisticsStore statistics.Store) ResolveSlugsCmd {
+	return resolveSlugs(authorizeCmd, slugValidatorCmd, shortURLStore, statisticsStore)
+}
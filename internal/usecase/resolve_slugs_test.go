@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSlugsCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(slug string) error {
+		if slug == "invalid!" {
+			return command.ErrInvalidSlugNonAlphanumeric
+		}
+		return nil
+	}
+	slugs := []string{"zTw34enA", "invalid!", "unknown", "spring-sale"}
+	storeMock := func(t *testing.T) *shorturl.MockStore {
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetBatch", mock.Anything, []string{"zTw34enA", "unknown", "spring-sale"}).Return(
+			[]domain.URLMapping{{Slug: "zTw34enA", OriginalURL: "https://example.com/1"}, {}, {Slug: "spring-sale", OriginalURL: "https://example.com/2"}},
+			[]error{nil, shorturl.ErrNotFound, nil}, nil)
+		return shortURLMock
+	}
+	expectedResults := []ResolveSlugResult{
+		{OriginalURL: "https://example.com/1"},
+		{Err: command.ErrInvalidSlugNonAlphanumeric},
+		{Err: shorturl.ErrNotFound},
+		{OriginalURL: "https://example.com/2"},
+	}
+
+	t.Run("nominal counting access", func(t *testing.T) {
+		// Given
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURLs", mock.Anything, []domain.URLMapping{
+			{Slug: "zTw34enA", OriginalURL: "https://example.com/1"},
+			{Slug: "spring-sale", OriginalURL: "https://example.com/2"},
+		}, statistics.StatisticTypeAccessed).Return(nil)
+		cmd := ResolveSlugsCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub, storeMock(t), statisticsMock)
+
+		// When
+		results, err := cmd(context.Background(), slugs, true)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedResults, results)
+	})
+	t.Run("nominal without counting access", func(t *testing.T) {
+		// Given
+		cmd := ResolveSlugsCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub, storeMock(t), statistics.NewMockStore(t))
+
+		// When
+		results, err := cmd(context.Background(), slugs, false)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, expectedResults, results)
+	})
+	t.Run("disabled link", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetBatch", mock.Anything, []string{"zTw34enA", "spring-sale"}).Return(
+			[]domain.URLMapping{{Slug: "zTw34enA", OriginalURL: "https://example.com/1", Disabled: true}, {Slug: "spring-sale", OriginalURL: "https://example.com/2"}},
+			[]error{nil, nil}, nil)
+		statisticsMock := statistics.NewMockStore(t)
+		statisticsMock.On("SetURLs", mock.Anything, []domain.URLMapping{{Slug: "spring-sale", OriginalURL: "https://example.com/2"}}, statistics.StatisticTypeAccessed).Return(nil)
+		cmd := ResolveSlugsCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub, shortURLMock, statisticsMock)
+
+		// When
+		results, err := cmd(context.Background(), []string{"zTw34enA", "spring-sale"}, true)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []ResolveSlugResult{{Err: ErrLinkDisabled}, {OriginalURL: "https://example.com/2"}}, results)
+	})
+	t.Run("only invalid slugs", func(t *testing.T) {
+		// Given
+		cmd := ResolveSlugsCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub, shorturl.NewMock(t), statistics.NewMockStore(t))
+
+		// When
+		results, err := cmd(context.Background(), []string{"invalid!"}, true)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []ResolveSlugResult{{Err: command.ErrInvalidSlugNonAlphanumeric}}, results)
+	})
+	t.Run("invalid batch size", func(t *testing.T) {
+		// Given
+		cmd := ResolveSlugsCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub, shorturl.NewMock(t), statistics.NewMockStore(t))
+
+		// When
+		emptyResults, emptyErr := cmd(context.Background(), nil, true)
+		tooLargeResults, tooLargeErr := cmd(context.Background(), make([]string, maxResolveSlugsBatchSize+1), true)
+
+		// Then
+		assert.ErrorIs(t, emptyErr, ErrInvalidBatchSize)
+		assert.Nil(t, emptyResults)
+		assert.ErrorIs(t, tooLargeErr, ErrInvalidBatchSize)
+		assert.Nil(t, tooLargeResults)
+	})
+	t.Run("failed retrieving URLs", func(t *testing.T) {
+		// Given
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("GetBatch", mock.Anything, mock.Anything).Return(nil, nil, assert.AnError)
+		cmd := ResolveSlugsCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorStub, shortURLMock, statistics.NewMockStore(t))
+
+		// When
+		results, err := cmd(context.Background(), slugs, true)
+
+		// Then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, results)
+	})
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/malwarescanner"
+	"urlShortenerService/internal/infrastructure/shorturl"
+)
+
+// UpdateLinkCmd represents the function signature of the command that changes the original URL of a link given a slug
+type UpdateLinkCmd func(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error)
+
+// updateLink changes the original URL of a link once sanitized and scanned for malware
+func updateLink(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, urlSanitizerCmd command.URLSanitizerCmd, malwareScanner malwarescanner.Scanner,
+	shortURLStore shorturl.Store) UpdateLinkCmd {
+	return func(ctx context.Context, slug string, originalURL string) (domain.URLMapping, error) {
+		// Ensure slug validity to avoid useless query to store
+		err := slugValidatorCmd(slug)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Ensure the link belongs to the caller
+		err = authorizeLinkManagement(ctx, authorizeCmd, shortURLStore, slug)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Sanitize and validate URL
+		sanitizedURL, err := urlSanitizerCmd(originalURL)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Scan the URL for malware
+		err = scanURL(malwareScanner, sanitizedURL)
+		if err != nil {
+			return domain.URLMapping{}, err
+		}
+
+		// Update URL
+		return shortURLStore.UpdateOriginalURL(ctx, slug, sanitizedURL)
+	}
+}
+
+// UpdateLinkCmdBuilder builds the command that will change the original URL of a link
+func UpdateLinkCmdBuilder(authorizeCmd AuthorizeCmd, slugValidatorCmd command.SlugValidatorCmd, urlSanitizerCmd command.URLSanitizerCmd, malwareScanner malwarescanner.Scanner,
+	shortURLStore shorturl.Store) UpdateLinkCmd {
+	return updateLink(authorizeCmd, slugValidatorCmd, urlSanitizerCmd, malwareScanner, shortURLStore)
+}
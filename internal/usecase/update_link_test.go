@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"urlShortenerService/domain"
+	"urlShortenerService/internal/command"
+	"urlShortenerService/internal/infrastructure/malwarescanner"
+	"urlShortenerService/internal/infrastructure/shorturl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateLinkCmdBuilder(t *testing.T) {
+	slugValidatorStub := func(expectedSlug *string, err error) command.SlugValidatorCmd {
+		return func(slug string) error {
+			if expectedSlug != nil {
+				assert.Equal(t, *expectedSlug, slug)
+			}
+			return err
+		}
+	}
+	urlSanitizerStub := func(expectedURL *string, returnedURL string, err error) command.URLSanitizerCmd {
+		return func(rawURL string) (string, error) {
+			if expectedURL != nil {
+				assert.Equal(t, *expectedURL, rawURL)
+			}
+			return returnedURL, err
+		}
+	}
+	var slug string = "zTw34enA"
+	var originalURL string = "https://My-New-URL.com/retargeted"
+	var sanitizedURL string = "https://my-new-url.com/retargeted"
+	var updatedURLMapping domain.URLMapping = domain.URLMapping{
+		Slug:        slug,
+		OriginalURL: sanitizedURL,
+	}
+
+	t.Run("nominal", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(&slug, nil)
+		urlSanitizerCmd := urlSanitizerStub(&originalURL, sanitizedURL, nil)
+		malwareScannerMock := malwarescanner.NewScannerMock(t)
+		malwareScannerMock.On("Scan", mock.Anything, sanitizedURL, mock.Anything).Return(malwarescanner.MalwareScanResultClear)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("UpdateOriginalURL", mock.Anything, slug, sanitizedURL).Return(updatedURLMapping, nil)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, urlSanitizerCmd, malwareScannerMock, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), slug, originalURL)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, updatedURLMapping, urlMapping)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, assert.AnError)
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		malwareScannerMock := malwarescanner.NewScannerMock(t)
+		shortURLMock := shorturl.NewMock(t)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, urlSanitizerCmd, malwareScannerMock, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), slug, originalURL)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("failed sanitizing URL", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		urlSanitizerCmd := urlSanitizerStub(nil, "", assert.AnError)
+		malwareScannerMock := malwarescanner.NewScannerMock(t)
+		shortURLMock := shorturl.NewMock(t)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, urlSanitizerCmd, malwareScannerMock, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), slug, originalURL)
+
+		// Then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("malware detected", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		malwareScannerMock := malwarescanner.NewScannerMock(t)
+		malwareScannerMock.On("Scan", mock.Anything, sanitizedURL, mock.Anything).Return(malwarescanner.MalwareScanResultDetected)
+		shortURLMock := shorturl.NewMock(t)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, urlSanitizerCmd, malwareScannerMock, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), slug, originalURL)
+
+		// Then
+		require.ErrorIs(t, err, malwarescanner.ErrMalswareURL)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("failed updating URL", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		malwareScannerMock := malwarescanner.NewScannerMock(t)
+		malwareScannerMock.On("Scan", mock.Anything, sanitizedURL, mock.Anything).Return(malwarescanner.MalwareScanResultClear)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("UpdateOriginalURL", mock.Anything, slug, sanitizedURL).Return(domain.URLMapping{}, shorturl.ErrNotFound)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(false), slugValidatorCmd, urlSanitizerCmd, malwareScannerMock, shortURLMock)
+
+		// When
+		urlMapping, err := cmd(context.Background(), slug, originalURL)
+
+		// Then
+		require.ErrorIs(t, err, shorturl.ErrNotFound)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("forbidden when owned by another API key", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{Slug: slug, Owner: "key-1"}, nil)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorCmd, urlSanitizerCmd, malwarescanner.NewScannerMock(t), shortURLMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "key-2", Role: domain.RoleEditor})
+
+		// When
+		urlMapping, err := cmd(ctx, slug, originalURL)
+
+		// Then
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, urlMapping)
+	})
+	t.Run("allowed for an admin", func(t *testing.T) {
+		// Given
+		slugValidatorCmd := slugValidatorStub(nil, nil)
+		urlSanitizerCmd := urlSanitizerStub(nil, sanitizedURL, nil)
+		malwareScannerMock := malwarescanner.NewScannerMock(t)
+		malwareScannerMock.On("Scan", mock.Anything, sanitizedURL, mock.Anything).Return(malwarescanner.MalwareScanResultClear)
+		shortURLMock := shorturl.NewMock(t)
+		shortURLMock.On("Get", mock.Anything, slug).Return(domain.URLMapping{Slug: slug, Owner: "key-1"}, nil)
+		shortURLMock.On("UpdateOriginalURL", mock.Anything, slug, sanitizedURL).Return(updatedURLMapping, nil)
+		cmd := UpdateLinkCmdBuilder(AuthorizeCmdBuilder(true), slugValidatorCmd, urlSanitizerCmd, malwareScannerMock, shortURLMock)
+		ctx := domain.ContextWithAPIKey(context.Background(), domain.APIKey{ID: "admin", Role: domain.RoleAdmin})
+
+		// When
+		urlMapping, err := cmd(ctx, slug, originalURL)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, updatedURLMapping, urlMapping)
+	})
+}
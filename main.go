@@ -2,16 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"urlShortenerService/domain"
 	"urlShortenerService/internal/command"
 	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/geoip"
 	"urlShortenerService/internal/infrastructure/malwarescanner"
-	"urlShortenerService/internal/infrastructure/shorturl"
-	"urlShortenerService/internal/infrastructure/statistics"
+	"urlShortenerService/internal/infrastructure/ratelimit"
 	"urlShortenerService/internal/transport/http"
 	"urlShortenerService/internal/usecase"
 
@@ -20,6 +26,9 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
+// shutdownTimeout is the maximal duration given to the in flight requests to end on shutdown
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// Set flag to output glog logs to stderr
 	flag.Set("logtostderr", "true")
@@ -37,31 +46,109 @@ func main() {
 		log.Fatalf("Error loading configuration: %s", err.Error())
 	}
 
-	// Initialize the database
-	shortURLStore, err := shorturl.NewPSQLStore(cfg.Database)
-	if err != nil {
-		log.Fatalf("Error initializing database [%s]: %s", cfg.Database.DbName, err.Error())
+	// Run the migrate command instead of the service if asked
+	if flag.Arg(0) == "migrate" {
+		err = runMigrate(context.Background(), cfg.Database, cfg.Slug.TimeToExpire, flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("Error migrating database [%s]: %s", cfg.Database.DbName, err.Error())
+		}
+		return
 	}
 
-	// Initialize the redis
-	statisticsStore, err := statistics.NewRedisStore(cfg.Redis)
-	if err != nil {
-		log.Fatalf("Error initializing redis: %s", err.Error())
+	// Initialize the GeoIP locator if a database is configured, the countries of the clicks being unknown otherwise
+	var geoIPLocator geoip.Locator
+	if cfg.GeoIP.DatabasePath != "" {
+		maxMindLocator, err := geoip.NewMaxMindLocator(cfg.GeoIP.DatabasePath)
+		if err != nil {
+			log.Fatalf("Error initializing GeoIP database [%s]: %s", cfg.GeoIP.DatabasePath, err.Error())
+		}
+		defer maxMindLocator.Close()
+		geoIPLocator = maxMindLocator
+	}
+
+	// Salt the hash of the visitors with a random secret if none is configured
+	visitorSalt := cfg.Statistics.VisitorSalt
+	if visitorSalt == "" {
+		// The statistics are shared within Redis, the replicas would each count the same visitor again
+		if cfg.Storage.Backend == config.StorageBackendPSQL {
+			log.Fatalf("Error no visitor salt is configured, set VISITOR_SALT for the unique visitors to be counted once across the replicas")
+		}
+		glog.Warning("no visitor salt is configured, set VISITOR_SALT for the unique visitors to be counted once across the replicas and the restarts")
+		saltBytes := make([]byte, 32)
+		_, err = rand.Read(saltBytes)
+		if err != nil {
+			log.Fatalf("Error generating the visitor salt: %s", err.Error())
+		}
+		visitorSalt = string(saltBytes)
+	}
+
+	// Enrich the clicks recorded within the statistics
+	userAgentParserCmd := command.UserAgentParserCmdBuilder()
+	visitorHasherCmd := command.VisitorHasherCmdBuilder(visitorSalt)
+	clickEnricher := usecase.ClickEnricherBuilder(visitorHasherCmd, userAgentParserCmd, geoIPLocator)
+
+	// Initialize the stores
+	stores := initStores(cfg, clickEnricher)
+
+	// Initialize the rate limits
+	var checkRateLimitCmd usecase.CheckRateLimitCmd
+	if stores.rateLimit != nil {
+		checkRateLimitCmd = usecase.CheckRateLimitCmdBuilder(map[usecase.RateLimitRoute]ratelimit.Rule{
+			usecase.RateLimitRouteShorten:      ratelimit.Rule(cfg.RateLimit.Shorten),
+			usecase.RateLimitRouteShortenBatch: ratelimit.Rule(cfg.RateLimit.ShortenBatch),
+			usecase.RateLimitRouteRedirect:     ratelimit.Rule(cfg.RateLimit.Redirect),
+			usecase.RateLimitRouteResolveBatch: ratelimit.Rule(cfg.RateLimit.ResolveBatch),
+		}, stores.rateLimit)
 	}
 
 	// Initialize malware scanner
 	malwareScanner := malwarescanner.NewDummyScanner()
 
-	// Build the commands
+	// Build the commands, every one of them checking the permissions of the caller unless the authentication is disabled
+	authorizeCmd := usecase.AuthorizeCmdBuilder(cfg.Auth.Enabled)
 	urlSanitizerCmd := command.URLSanitizerCmdBuilder()
 	slugGeneratorCmd := command.SlugGeneratorCmdBuilder(cfg.Slug.MaximalLenght)
-	slugValidatorCmd := command.SlugValidatorCmdBuilder(cfg.Slug.MaximalLenght)
-	createShortenURLCmd := usecase.CreateShortenURLCmdBuilder(cfg.ServerDomain.CreateBaseURL(), urlSanitizerCmd, slugGeneratorCmd, shortURLStore, statisticsStore)
-	getOriginalURLCmd := usecase.GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScanner, shortURLStore, statisticsStore)
-	forceGetOriginalURLCmd := usecase.ForceGetOriginalURLCmdBuilder(slugValidatorCmd, shortURLStore, statisticsStore)
-	deleteExpiredURLsCmd := usecase.DeleteExpiredURLsCmdBuilder(cfg.Slug.TimeToExpire, shortURLStore)
-	getStatisticsForURLCmd := usecase.GetStatisticsForURLCmdBuilder(urlSanitizerCmd, statisticsStore)
-	getTopStatisticsCmd := usecase.GetTopStatisticsCmdBuilder(statisticsStore)
+	slugValidatorCmd := command.SlugValidatorCmdBuilder(cfg.Slug.ValidatorMaximalLenght(), cfg.Slug.AllowedCharacters)
+	createShortenURLCmd := usecase.CreateShortenURLCmdBuilder(authorizeCmd, cfg.ServerDomain.CreateBaseURL(), cfg.Slug.MaxCollisionRetries, cfg.Slug.TimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, stores.shortURL, stores.statistics)
+	createShortenURLsCmd := usecase.CreateShortenURLsCmdBuilder(authorizeCmd, cfg.ServerDomain.CreateBaseURL(), cfg.Slug.MaxCollisionRetries, cfg.Slug.TimeToExpire, urlSanitizerCmd, slugGeneratorCmd, slugValidatorCmd, stores.shortURL, stores.statistics)
+	getOriginalURLCmd := usecase.GetOriginalURLWithMalwareScanCmdBuilder(slugValidatorCmd, malwareScanner, stores.shortURL, stores.statistics)
+	forceGetOriginalURLCmd := usecase.ForceGetOriginalURLCmdBuilder(slugValidatorCmd, stores.shortURL, stores.statistics)
+	resolveSlugsCmd := usecase.ResolveSlugsCmdBuilder(authorizeCmd, slugValidatorCmd, stores.shortURL, stores.statistics)
+	deleteExpiredURLsCmd := usecase.DeleteExpiredURLsCmdBuilder(stores.shortURL)
+	getStatisticsForURLCmd := usecase.GetStatisticsForURLCmdBuilder(authorizeCmd, urlSanitizerCmd, stores.statistics)
+	getStatisticsForSlugCmd := usecase.GetStatisticsForSlugCmdBuilder(authorizeCmd, slugValidatorCmd, stores.shortURL, stores.statistics)
+	getClickBreakdownCmd := usecase.GetClickBreakdownCmdBuilder(authorizeCmd, slugValidatorCmd, stores.statistics)
+	getStatisticsTimeSeriesCmd := usecase.GetStatisticsTimeSeriesCmdBuilder(authorizeCmd, urlSanitizerCmd, stores.statistics)
+	getTopStatisticsCmd := usecase.GetTopStatisticsCmdBuilder(authorizeCmd, stores.statistics)
+	getLinkCmd := usecase.GetLinkCmdBuilder(authorizeCmd, slugValidatorCmd, stores.shortURL)
+	updateLinkCmd := usecase.UpdateLinkCmdBuilder(authorizeCmd, slugValidatorCmd, urlSanitizerCmd, malwareScanner, stores.shortURL)
+	deleteLinkCmd := usecase.DeleteLinkCmdBuilder(authorizeCmd, slugValidatorCmd, stores.shortURL)
+	disableLinkCmd := usecase.DisableLinkCmdBuilder(authorizeCmd, slugValidatorCmd, stores.shortURL)
+	purgeLinkCmd := usecase.PurgeLinkCmdBuilder(authorizeCmd, slugValidatorCmd, stores.shortURL)
+	listLinksCmd := usecase.ListLinksCmdBuilder(authorizeCmd, stores.shortURL)
+	lookupLinksCmd := usecase.LookupLinksCmdBuilder(authorizeCmd, urlSanitizerCmd, stores.shortURL)
+	apiKeyHasherCmd := command.APIKeyHasherCmdBuilder()
+	createAPIKeyCmd := usecase.CreateAPIKeyCmdBuilder(authorizeCmd, command.APIKeyGeneratorCmdBuilder(), apiKeyHasherCmd, stores.apiKey)
+	var authenticateAPIKeyCmd usecase.AuthenticateAPIKeyCmd
+	if cfg.Auth.Enabled {
+		authenticateAPIKeyCmd = usecase.AuthenticateAPIKeyCmdBuilder(apiKeyHasherCmd, stores.apiKey)
+	}
+
+	// Store the bootstrap admin API key
+	if cfg.Auth.AdminKey != "" {
+		err = stores.apiKey.Set(context.Background(), domain.APIKey{
+			ID:        "admin",
+			Name:      "admin",
+			Hash:      apiKeyHasherCmd(cfg.Auth.AdminKey),
+			Role:      domain.RoleAdmin,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			log.Fatalf("Error storing the admin API key: %s", err.Error())
+		}
+	} else if cfg.Auth.Enabled {
+		glog.Warning("authentication is enabled but no admin API key is configured, set ADMIN_API_KEY to create API keys")
+	}
 
 	// Build the cron job function
 	cronJob := func() {
@@ -86,8 +173,38 @@ func main() {
 	c.Start()
 
 	// Initialize the HTTP router
-	router := http.NewBuilder(domain.Environment(os.Getenv("env"))).BuildRouter(createShortenURLCmd, getOriginalURLCmd, forceGetOriginalURLCmd, getStatisticsForURLCmd, getTopStatisticsCmd)
+	builder, err := http.NewBuilder(domain.Environment(os.Getenv("env"))).WithTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Error initializing the trusted proxies: %s", err.Error())
+	}
+	router := builder.BuildRouter(authenticateAPIKeyCmd, checkRateLimitCmd, authorizeCmd, createAPIKeyCmd, createShortenURLCmd, createShortenURLsCmd, getOriginalURLCmd, forceGetOriginalURLCmd, getStatisticsForURLCmd, getStatisticsForSlugCmd, getClickBreakdownCmd, getStatisticsTimeSeriesCmd,
+		getTopStatisticsCmd, getLinkCmd, updateLinkCmd, deleteLinkCmd, disableLinkCmd, purgeLinkCmd, listLinksCmd, lookupLinksCmd, resolveSlugsCmd)
 
 	// Start the service
-	router.Run(fmt.Sprintf(":%d", cfg.ServerDomain.Port))
+	server := &nethttp.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.ServerDomain.Port),
+		Handler: router,
+	}
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			log.Fatalf("Error running the service: %s", err.Error())
+		}
+	}()
+
+	// Wait for the shutdown signal, then let the in flight requests end and drain the pools of connections
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	glog.Info("shutting down the service")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		glog.Errorf("failed to shut down the HTTP server: %s", err)
+	}
+	<-c.Stop().Done()
+	stores.close()
+	glog.Info("service shut down")
 }
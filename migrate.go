@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/psql"
+)
+
+// errMigrateUsage is the error when the migrate command is misused
+var errMigrateUsage error = errors.New("usage: migrate up | down [steps] | status")
+
+// runMigrate runs the migrate command given its arguments: up, down [steps] (1 by default) or status
+// The default expiration of the URLs backfills the ones stored before the expiration was introduced
+func runMigrate(ctx context.Context, connConf config.PSQLConnConfig, defaultTimeToExpire time.Duration, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	pool, err := psql.NewPool(ctx, connConf)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := psql.NewMigrator(pool, defaultTimeToExpire)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		migrations, err := migrator.Up(ctx)
+		for _, migration := range migrations {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errMigrateUsage
+			}
+		}
+		migrations, err := migrator.Down(ctx, steps)
+		for _, migration := range migrations {
+			fmt.Printf("rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	default:
+		return errMigrateUsage
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"urlShortenerService/internal/infrastructure/apikey"
+	"urlShortenerService/internal/infrastructure/config"
+	"urlShortenerService/internal/infrastructure/invalidation"
+	"urlShortenerService/internal/infrastructure/psql"
+	"urlShortenerService/internal/infrastructure/ratelimit"
+	"urlShortenerService/internal/infrastructure/shorturl"
+	"urlShortenerService/internal/infrastructure/statistics"
+
+	"github.com/golang/glog"
+)
+
+// stores represents the stores of the service
+type stores struct {
+	shortURL   shorturl.Store
+	apiKey     apikey.Store
+	statistics statistics.Store
+	rateLimit  ratelimit.Store // nil if the rate limiting is disabled
+	closers    []func() error  // Release the connections on shutdown
+}
+
+// close releases the connections of the stores
+func (s *stores) close() {
+	for _, closer := range s.closers {
+		err := closer()
+		if err != nil {
+			glog.Errorf("failed to close a store: %s", err)
+		}
+	}
+}
+
+// initStores initializes the stores of the configured storage backend, the statistics store enriching the clicks
+func initStores(cfg *config.Conf, clickEnricher statistics.ClickEnricher) *stores {
+	switch cfg.Storage.Backend {
+	case config.StorageBackendPSQL:
+		return initPSQLStores(cfg, clickEnricher)
+	case config.StorageBackendMemory:
+		return initMemoryStores(cfg, clickEnricher)
+	case config.StorageBackendBolt:
+		return initBoltStores(cfg, clickEnricher)
+	default:
+		log.Fatalf("Error unknown storage backend [%s]", cfg.Storage.Backend)
+		return nil
+	}
+}
+
+// initMemoryStores initializes in memory stores, needing neither database nor redis
+func initMemoryStores(cfg *config.Conf, clickEnricher statistics.ClickEnricher) *stores {
+	glog.Warning("the data is stored in memory, nothing is persisted nor shared between replicas")
+
+	s := &stores{
+		shortURL:   shorturl.NewMemoryStore(),
+		apiKey:     apikey.NewMemoryStore(),
+		statistics: statistics.NewEnrichedStore(statistics.NewMemoryStore(cfg.Redis.MaxResults, cfg.Statistics), clickEnricher),
+	}
+	if cfg.RateLimit.Enabled {
+		s.rateLimit = ratelimit.NewMemoryStore()
+	}
+	return s
+}
+
+// initBoltStores initializes the stores of the URL mappings and of the API keys within an embedded database file, the other stores being in memory
+// The statistics and the rate limits are lost on restart
+func initBoltStores(cfg *config.Conf, clickEnricher statistics.ClickEnricher) *stores {
+	glog.Warning("the statistics and the rate limits are stored in memory, they are not persisted")
+
+	shortURLStore, err := shorturl.NewBoltStore(cfg.Storage.Bolt.Path)
+	if err != nil {
+		log.Fatalf("Error initializing database [%s]: %s", cfg.Storage.Bolt.Path, err.Error())
+	}
+	// Kept within the same file, closed along with the URL mappings store
+	apiKeyStore, err := apikey.NewBoltStore(shortURLStore.DB())
+	if err != nil {
+		log.Fatalf("Error initializing database [%s]: %s", cfg.Storage.Bolt.Path, err.Error())
+	}
+
+	s := &stores{
+		shortURL:   shortURLStore,
+		apiKey:     apiKeyStore,
+		statistics: statistics.NewEnrichedStore(statistics.NewMemoryStore(cfg.Redis.MaxResults, cfg.Statistics), clickEnricher),
+		closers:    []func() error{shortURLStore.Close},
+	}
+	if cfg.RateLimit.Enabled {
+		s.rateLimit = ratelimit.NewMemoryStore()
+	}
+	return s
+}
+
+// initPSQLStores initializes the stores relying on the PSQL database and the redis, along with the caches
+func initPSQLStores(cfg *config.Conf, clickEnricher statistics.ClickEnricher) *stores {
+	// Connect a single pool of connections, shared by the PSQL stores so that the database gets at most max-conns of them
+	pool, err := psql.NewPool(context.Background(), cfg.Database)
+	if err != nil {
+		log.Fatalf("Error initializing database [%s]: %s", cfg.Database.DbName, err.Error())
+	}
+	expvar.Publish("psql_pool", expvar.Func(func() any { return psql.StatsOf(pool) }))
+
+	// Apply the pending migrations, the replicas starting together wait for each other
+	if cfg.Database.AutoMigrate {
+		migrations, err := psql.MigrateUp(context.Background(), pool, cfg.Slug.TimeToExpire)
+		if err != nil {
+			log.Fatalf("Error migrating database [%s]: %s", cfg.Database.DbName, err.Error())
+		}
+		glog.Infof("[%d] migrations applied", len(migrations))
+	}
+
+	// Initialize the database
+	shortURLStore := shorturl.NewPSQLStore(pool)
+
+	// Put the cache shared by the replicas in front of the database if enabled
+	var urlStore shorturl.Store = shortURLStore
+	if cfg.Cache.Shared.Enabled {
+		sharedCacheStore, err := shorturl.NewRedisCacheStore(shortURLStore, cfg.Redis, cfg.Cache.Shared)
+		if err != nil {
+			log.Fatalf("Error initializing shared cache redis: %s", err.Error())
+		}
+		expvar.Publish("cache_shared_urls", expvar.Func(func() any { return sharedCacheStore.Stats() }))
+		urlStore = sharedCacheStore
+	}
+
+	// Put the in memory cache in front of them if enabled
+	if cfg.Cache.Enabled {
+		// The bloom filter only learns the slugs created by the other replicas through the invalidations
+		if cfg.Cache.BloomFilter.Enabled && !cfg.Cache.Invalidation.Enabled {
+			log.Fatalf("Error the cache bloom filter can't be enabled without the cache invalidation")
+		}
+		// Share the changed slugs with the other replicas so that they evict them as well
+		var invalidationBus invalidation.Bus
+		if cfg.Cache.Invalidation.Enabled {
+			redisBus, err := invalidation.NewRedisBus(cfg.Redis, cfg.Cache.Invalidation.Channel)
+			if err != nil {
+				log.Fatalf("Error initializing cache invalidation redis: %s", err.Error())
+			}
+			invalidationBus = redisBus
+		}
+		cacheStore := shorturl.NewCacheStore(urlStore, cfg.Cache, invalidationBus)
+		go cacheStore.ListenInvalidations(context.Background())
+		err = cacheStore.LoadBloomFilter(context.Background())
+		if err != nil {
+			glog.Warningf("failed to load the bloom filter of the slugs, every lookup reaches the database: %s", err)
+		}
+		expvar.Publish("cache_urls", expvar.Func(func() any { return cacheStore.Stats() }))
+		urlStore = cacheStore
+	}
+
+	// Initialize the API keys database
+	apiKeyStore := apikey.NewPSQLStore(pool)
+
+	// Initialize the redis
+	statisticsStore, err := statistics.NewRedisStore(cfg.Redis, cfg.Statistics)
+	if err != nil {
+		log.Fatalf("Error initializing redis: %s", err.Error())
+	}
+
+	// Coalesce the statistics to store them in batches rather than one round trip per request, the clicks being enriched by its workers
+	bufferedStatisticsStore := statistics.NewBufferedStore(statisticsStore, cfg.Statistics.Buffer, clickEnricher)
+	expvar.Publish("statistics_buffer", expvar.Func(func() any { return bufferedStatisticsStore.Stats() }))
+
+	s := &stores{
+		shortURL:   urlStore,
+		apiKey:     apiKeyStore,
+		statistics: bufferedStatisticsStore,
+		// The queued statistics are drained first
+		closers: []func() error{bufferedStatisticsStore.Close, func() error { pool.Close(); return nil }},
+	}
+
+	// Initialize the rate limits, stored within the redis to be shared by all the replicas
+	if cfg.RateLimit.Enabled {
+		rateLimitStore, err := ratelimit.NewRedisStore(cfg.Redis)
+		if err != nil {
+			log.Fatalf("Error initializing rate limit redis: %s", err.Error())
+		}
+		s.rateLimit = rateLimitStore
+	}
+	return s
+}